@@ -3,19 +3,56 @@ package main
 import (
 	"context"
 	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/statistico/statistico-ratings/internal/app/bootstrap"
+	"github.com/statistico/statistico-ratings/internal/app/cache"
+	"github.com/statistico/statistico-ratings/internal/app/export"
+	"github.com/statistico/statistico-ratings/internal/app/filesystem"
+	"github.com/statistico/statistico-ratings/internal/app/team"
 	"github.com/urfave/cli"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"io"
+	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 )
 
+// exitFixturesFailed is the exit code returned when a command runs but one or more fixtures fail to process.
+const exitFixturesFailed = 2
+
+var formatFlag = &cli.StringFlag{
+	Name:  "format",
+	Usage: "The format of the run report printed on completion, either text or json",
+	Value: "text",
+}
+
+var reportOutputFlag = &cli.StringFlag{
+	Name:  "report-output",
+	Usage: "The location to also write the run report as JSON i.e. reports/run.json or s3://bucket/reports/run.json",
+}
+
 func main() {
 	app := bootstrap.BuildContainer(bootstrap.BuildConfig())
 	reader := app.FilesystemReader()
+	writer := app.FilesystemWriter()
 	handler := app.TeamRatingHandler()
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+
+	// Interrupting a command cancels ctx, aborting data service requests and database queries in progress and
+	// leaving fixtures not yet started pending.
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		<-signals
+		cancel()
+	}()
 
 	console := &cli.App{
 		Name: "Statistico Ratings - Command Line Application",
@@ -25,12 +62,7 @@ func main() {
 				Usage:       "Calculate team ratings for a competition and season",
 				Description: "Calculate team ratings for a competition and season",
 				Before: func(c *cli.Context) error {
-					fmt.Println("Calculating team ratings...")
-					return nil
-				},
-				After: func(c *cli.Context) error {
-					fmt.Println("Complete.")
-					return nil
+					return start(c, "Calculating team ratings...")
 				},
 				Action: func(c *cli.Context) error {
 					r, err := reader.Reader(c.String("filepath"))
@@ -40,64 +72,416 @@ func main() {
 					}
 
 					seasons := csv.NewReader(r)
+					seasons.FieldsPerRecord = 2
+
+					summary := team.NewReport()
+
+					for line := 1; ; line++ {
+						if err := ctx.Err(); err != nil {
+							summary.AddError(fmt.Errorf("processing stopped before all seasons were processed: %s", err.Error()))
+							break
+						}
 
-					for {
 						row, err := seasons.Read()
 
 						if err == io.EOF {
 							break
 						}
 
-						comp, _ := strconv.ParseUint(row[0], 0, 64)
-						season, _ := strconv.ParseUint(row[1], 0, 64)
-						
-						handler.ByCompetition(ctx, comp, season)
+						// Malformed rows are recorded and skipped, but the rest of the file cannot be read after
+						// any other error.
+						if _, ok := err.(*csv.ParseError); ok {
+							summary.AddError(err)
+							continue
+						}
+
+						if err != nil {
+							summary.AddError(fmt.Errorf("error reading seasons file: %s", err.Error()))
+							break
+						}
+
+						comp, season, err := parseSeason(row)
+
+						if err != nil {
+							summary.AddError(fmt.Errorf("record on line %d: %s", line, err.Error()))
+							continue
+						}
+
+						report, err := handler.ByCompetition(ctx, comp, season)
+
+						if err != nil {
+							summary.AddError(fmt.Errorf("competition %d and season %d: %s", comp, season, err.Error()))
+							continue
+						}
+
+						summary.Merge(report)
 					}
 
-					return nil
+					return complete(c, writer, summary)
 				},
 				Flags: []cli.Flag{
 					&cli.StringFlag{
 						Name:     "filepath",
-						Usage:    "The filepath where the csv resides",
+						Usage:    "The location of the csv i.e. file:///seasons.csv, s3://bucket/seasons.csv.gz or https://host/seasons.csv",
 						Required: true,
 					},
+					formatFlag,
+					reportOutputFlag,
+				},
+			},
+			{
+				Name:        "team:competition",
+				Usage:       "Calculate team ratings for the current and recent seasons of competitions",
+				Description: "Calculate team ratings for the current season and the seasons before it, resolved from the data service for each competition",
+				Before: func(c *cli.Context) error {
+					return start(c, "Calculating team ratings...")
+				},
+				Action: func(c *cli.Context) error {
+					if c.Int("seasons") < 1 {
+						return errors.New("seasons provided must be greater than 0")
+					}
+
+					competitions := app.Config.SupportedCompetitions
+
+					if c.IsSet("competition") {
+						competitions = []uint64{c.Uint64("competition")}
+					}
+
+					summary := team.NewReport()
+
+					for _, id := range competitions {
+						report, err := handler.Competition(ctx, id, c.Int("seasons"))
+
+						if err != nil {
+							summary.AddError(fmt.Errorf("competition %d: %s", id, err.Error()))
+							continue
+						}
+
+						summary.Merge(report)
+					}
+
+					return complete(c, writer, summary)
+				},
+				Flags: []cli.Flag{
+					&cli.Uint64Flag{
+						Name:  "competition",
+						Usage: "The competition to process. Defaults to every supported competition",
+					},
+					&cli.IntFlag{
+						Name:  "seasons",
+						Usage: "The number of seasons to process, counting back from and including the current season",
+						Value: 1,
+					},
+					formatFlag,
+					reportOutputFlag,
+				},
+			},
+			{
+				Name:        "team:retry-failed",
+				Usage:       "Retry fixtures that failed in previous team rating jobs",
+				Description: "Retry fixtures that failed in previous team rating jobs. Run team:csv again to resume fixtures not processed due to a failure",
+				Before: func(c *cli.Context) error {
+					return start(c, "Retrying failed team ratings...")
+				},
+				Action: func(c *cli.Context) error {
+					report, err := handler.RetryFailed(ctx)
+
+					if err != nil {
+						return err
+					}
+
+					return complete(c, writer, report)
+				},
+				Flags: []cli.Flag{
+					formatFlag,
+					reportOutputFlag,
+				},
+			},
+			{
+				Name:        "cache:clear",
+				Usage:       "Clear cached Statistico data service responses",
+				Description: "Clear cached Statistico data service responses for a fixture or the entire cache",
+				Action: func(c *cli.Context) error {
+					store := app.DataCache()
+
+					if c.IsSet("fixture") {
+						return store.Delete(ctx, cache.FixtureKeys(c.Uint64("fixture"))...)
+					}
+
+					return store.Flush(ctx)
+				},
+				Flags: []cli.Flag{
+					&cli.Uint64Flag{
+						Name:  "fixture",
+						Usage: "Clear cached fixture and fixture events for the given fixture ID only",
+					},
+				},
+			},
+			{
+				Name:        "team:range",
+				Usage:       "Calculate team ratings for fixtures played between two dates",
+				Description: "Calculate team ratings for supported competition fixtures played between two dates. Fixtures with existing ratings are skipped and fixtures for teams already rated for a later fixture fail",
+				Before: func(c *cli.Context) error {
+					return start(c, "Calculating team ratings...")
+				},
+				Action: func(c *cli.Context) error {
+					from, err := time.Parse(time.RFC3339, c.String("from"))
+
+					if err != nil {
+						return fmt.Errorf("invalid from date: %s", err.Error())
+					}
+
+					to, err := time.Parse(time.RFC3339, c.String("to"))
+
+					if err != nil {
+						return fmt.Errorf("invalid to date: %s", err.Error())
+					}
+
+					report, err := handler.ByDateRange(ctx, from, to)
+
+					if err != nil {
+						return err
+					}
+
+					return complete(c, writer, report)
+				},
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "from",
+						Usage:    "Process fixtures played from the given RFC3339 date i.e. 2021-03-12T00:00:00Z",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "to",
+						Usage:    "Process fixtures played before the given RFC3339 date i.e. 2021-03-14T00:00:00Z",
+						Required: true,
+					},
+					formatFlag,
+					reportOutputFlag,
+				},
+			},
+			{
+				Name:        "team:daemon",
+				Usage:       "Continuously calculate team ratings for fixtures as they reach full time",
+				Description: "Poll for fixtures that have reached full time and calculate team ratings until SIGINT or SIGTERM is received. Prometheus metrics are served on /metrics at METRICS_ADDRESS while running",
+				Action: func(c *cli.Context) error {
+					daemon := team.NewDaemon(handler, team.DaemonConfig{
+						Interval:        c.Duration("interval"),
+						Window:          c.Duration("window"),
+						FullTime:        c.Duration("full-time"),
+						MaxBackoff:      c.Duration("max-backoff"),
+						ShutdownTimeout: c.Duration("shutdown-timeout"),
+					})
+
+					metrics := app.MetricsServer()
+
+					go func() {
+						if err := metrics.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+							app.Logger.Errorf("Error serving metrics: %s", err.Error())
+						}
+					}()
+
+					daemon.Run(ctx)
+
+					return metrics.Close()
+				},
+				Flags: []cli.Flag{
+					&cli.DurationFlag{
+						Name:  "interval",
+						Usage: "The time waited between polls for fixtures",
+						Value: 5 * time.Minute,
+					},
+					&cli.DurationFlag{
+						Name:  "window",
+						Usage: "How far before now to look for fixtures that have not been rated",
+						Value: 24 * time.Hour,
+					},
+					&cli.DurationFlag{
+						Name:  "full-time",
+						Usage: "The time after kick off a fixture is treated as finished",
+						Value: 2*time.Hour + 15*time.Minute,
+					},
+					&cli.DurationFlag{
+						Name:  "max-backoff",
+						Usage: "The longest time waited between polls after consecutive failed polls",
+						Value: 30 * time.Minute,
+					},
+					&cli.DurationFlag{
+						Name:  "shutdown-timeout",
+						Usage: "The time a poll in progress is given to complete once a signal is received",
+						Value: 30 * time.Second,
+					},
 				},
 			},
 			{
 				Name:        "team:today",
 				Usage:       "Calculate team ratings for today's fixtures",
-				Description: "Calculate team ratings for today's fixtures",
+				Description: "Calculate team ratings for today's fixtures up to an hour of the day, or for fixtures played in the last number of hours",
 				Before: func(c *cli.Context) error {
-					fmt.Println("Calculating team ratings...")
-					return nil
+					return start(c, "Calculating team ratings...")
 				},
-				After: func(c *cli.Context) error {
-					fmt.Println("Complete.")
-					return nil
+				Action: func(c *cli.Context) error {
+					var report *team.Report
+					var err error
+
+					switch {
+					case c.IsSet("hour") == c.IsSet("hours"):
+						return errors.New("exactly one of the hour or hours flags must be provided")
+					case c.IsSet("hours"):
+						report, err = handler.LastHours(ctx, c.Int("hours"))
+					default:
+						loc, locErr := time.LoadLocation(c.String("timezone"))
+
+						if locErr != nil {
+							return fmt.Errorf("invalid timezone: %s", locErr.Error())
+						}
+
+						report, err = handler.Today(ctx, &team.TodayQuery{
+							Hour:     c.Int("hour"),
+							Location: loc,
+							Lookback: c.Duration("lookback"),
+						})
+					}
+
+					if err != nil {
+						return err
+					}
+
+					return complete(c, writer, report)
+				},
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "hour",
+						Usage: "Process the days fixture played before the given hour. If the hour has not been reached the previous day is processed",
+					},
+					&cli.StringFlag{
+						Name:  "timezone",
+						Usage: "The IANA timezone the hour and start of the day are evaluated in i.e. Europe/London",
+						Value: "UTC",
+					},
+					&cli.DurationFlag{
+						Name:  "lookback",
+						Usage: "Extend the window before midnight to include late kick offs from the previous day i.e. 3h",
+					},
+					&cli.IntFlag{
+						Name:  "hours",
+						Usage: "Process fixtures played in the given number of hours up to now instead of using the hour flag",
+					},
+					formatFlag,
+					reportOutputFlag,
 				},
+			},
+			{
+				Name:        "team:export",
+				Usage:       "Export team ratings to CSV, JSON Lines or Parquet",
+				Description: "Export team ratings filtered by competition, season or fixture date to local disk or S3",
 				Action: func(c *cli.Context) error {
-					err := handler.Today(ctx, c.Int("hour"))
+					output := c.String("output")
+
+					q, err := exportQuery(c)
 
 					if err != nil {
-						fmt.Printf("error: %s\n", err.Error())
+						return err
+					}
+
+					if q.CompetitionID != nil {
+						warnUnassigned(ctx, app, q)
+					}
+
+					format := c.String("format")
+
+					if format == "" {
+						if format, err = export.Format(output); err != nil {
+							return err
+						}
 					}
 
+					file, err := writer.Writer(output)
+
+					if err != nil {
+						return err
+					}
+
+					enc, err := export.NewEncoder(format, file)
+
+					if err != nil {
+						file.Abort()
+						return err
+					}
+
+					count := 0
+
+					err = app.TeamRatingExporter().Export(ctx, q, func(r *team.Rating) error {
+						count++
+						return enc.Encode(r)
+					})
+
+					if err == nil {
+						err = enc.Close()
+					}
+
+					if err != nil {
+						file.Abort()
+						return err
+					}
+
+					if err := file.Close(); err != nil {
+						return err
+					}
+
+					fmt.Printf("Exported %d team ratings to %s\n", count, output)
+
 					return nil
 				},
 				Flags: []cli.Flag{
-					&cli.IntFlag{
-						Name:     "hour",
-						Usage:    "Process the days fixture played before the given hour",
+					&cli.StringFlag{
+						Name:     "output",
+						Usage:    "The location to write the export i.e. ratings.csv, file:///exports/ratings.jsonl or s3://bucket/ratings.parquet",
 						Required: true,
 					},
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "The export format, either csv, jsonl or parquet. Defaults to the format matching the output extension",
+					},
+					&cli.Uint64Flag{
+						Name:  "competition",
+						Usage: "Export ratings for the competition provided",
+					},
+					&cli.Uint64Flag{
+						Name:  "season",
+						Usage: "Export ratings for the season provided",
+					},
+					&cli.StringFlag{
+						Name:  "from",
+						Usage: "Export ratings for fixtures played on or after the date provided i.e. 2021-08-01 or 2021-08-01T12:00:00Z",
+					},
+					&cli.StringFlag{
+						Name:  "to",
+						Usage: "Export ratings for fixtures played before the date provided i.e. 2021-09-01 or 2021-09-01T12:00:00Z",
+					},
 				},
 			},
 		},
 	}
 
+	var span trace.Span
+
+	// Each command runs under a span named after the command so spans for data service calls and queries are
+	// grouped by run. Spans are flushed before exiting, including when a command exits with a failure code.
+	console.Before = func(c *cli.Context) error {
+		ctx, span = app.Tracer().Start(ctx, c.Args().First())
+		return nil
+	}
+
+	cli.OsExiter = func(code int) {
+		shutdown(app, span, fmt.Errorf("command exited with code %d", code))
+		os.Exit(code)
+	}
+
 	err := console.Run(os.Args)
 
+	shutdown(app, span, err)
+
 	if err != nil {
 		fmt.Printf("Error in executing command: %s\n", err.Error())
 		os.Exit(1)
@@ -105,3 +489,167 @@ func main() {
 
 	os.Exit(0)
 }
+
+// shutdown ends the command span, recording err if not nil, and flushes spans to the configured exporter.
+func shutdown(app bootstrap.Container, span trace.Span, err error) {
+	if span != nil {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		span.End()
+	}
+
+	if err := app.Tracing.Shutdown(context.Background()); err != nil {
+		app.Logger.Warnf("Error flushing trace spans: %s", err.Error())
+	}
+}
+
+// start validates the report format flag and prints message when reporting as text, so JSON output remains valid.
+func start(c *cli.Context, message string) error {
+	switch c.String("format") {
+	case "text":
+		fmt.Println(message)
+		return nil
+	case "json":
+		return nil
+	default:
+		return fmt.Errorf("format '%s' is not supported", c.String("format"))
+	}
+}
+
+// exportQuery builds the team.ExportQuery for the competition, season, from and to flags provided.
+func exportQuery(c *cli.Context) (*team.ExportQuery, error) {
+	var q team.ExportQuery
+
+	if c.IsSet("competition") {
+		id := c.Uint64("competition")
+		q.CompetitionID = &id
+	}
+
+	if c.IsSet("season") {
+		id := c.Uint64("season")
+		q.SeasonID = &id
+	}
+
+	for flag, date := range map[string]**time.Time{"from": &q.From, "to": &q.To} {
+		if !c.IsSet(flag) {
+			continue
+		}
+
+		t, err := parseDate(c.String(flag))
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s date: %s", flag, err.Error())
+		}
+
+		*date = &t
+	}
+
+	return &q, nil
+}
+
+// warnUnassigned warns if ratings matching the export query other than its competition have no competition recorded,
+// as those ratings were calculated before competitions were persisted and are missing from competition exports.
+func warnUnassigned(ctx context.Context, app bootstrap.Container, q *team.ExportQuery) {
+	count, err := app.TeamRatingExporter().Unassigned(ctx, q)
+
+	if err != nil {
+		app.Logger.Warnf("Error counting team ratings without a competition: %s", err.Error())
+		return
+	}
+
+	if count > 0 {
+		app.Logger.Warnf(
+			"%d team ratings matching the export have no competition recorded and are not exported when filtering by competition",
+			count,
+		)
+	}
+}
+
+// parseSeason parses the competition and season IDs of a row of the team:csv seasons file.
+func parseSeason(row []string) (uint64, uint64, error) {
+	comp, err := strconv.ParseUint(row[0], 10, 64)
+
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid competition id %q", row[0])
+	}
+
+	season, err := strconv.ParseUint(row[1], 10, 64)
+
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid season id %q", row[1])
+	}
+
+	return comp, season, nil
+}
+
+// parseDate parses a date in either YYYY-MM-DD or RFC3339 format. Dates without a time are midnight UTC.
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+// complete prints the run report in the format requested and writes it to the report output if provided, returning
+// an error with a non-zero exit code if any errors were recorded so failures can be alerted on.
+func complete(c *cli.Context, w filesystem.Writer, r *team.Report) error {
+	if c.String("format") == "json" {
+		if err := json.NewEncoder(os.Stdout).Encode(r); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf(
+			"Processed: %d\nSkipped: %d\nFailed: %d\nPending: %d\nUnfinished: %d\n",
+			r.Processed,
+			r.Skipped,
+			r.Failed,
+			r.Pending,
+			r.Unfinished,
+		)
+
+		for _, s := range r.Skips {
+			fmt.Printf("skipped: fixture %d: %s\n", s.FixtureID, s.Reason)
+		}
+
+		for _, e := range r.Errors {
+			if e.FixtureID == 0 {
+				fmt.Printf("error: %s\n", e.Error)
+				continue
+			}
+
+			fmt.Printf("error: fixture %d: %s\n", e.FixtureID, e.Error)
+		}
+	}
+
+	if c.String("report-output") != "" {
+		if err := writeReport(w, c.String("report-output"), r); err != nil {
+			return fmt.Errorf("error writing run report: %s", err.Error())
+		}
+	}
+
+	if !r.Success() {
+		return cli.NewExitError("", exitFixturesFailed)
+	}
+
+	return nil
+}
+
+// writeReport writes r as JSON to filename.
+func writeReport(w filesystem.Writer, filename string, r *team.Report) error {
+	file, err := w.Writer(filename)
+
+	if err != nil {
+		return err
+	}
+
+	if err := json.NewEncoder(file).Encode(r); err != nil {
+		file.Abort()
+		return err
+	}
+
+	return file.Close()
+}
@@ -1,33 +1,111 @@
 package main
 
 import (
+	"context"
 	"github.com/statistico/statistico-proto/go"
 	"github.com/statistico/statistico-ratings/internal/app/bootstrap"
+	igrpc "github.com/statistico/statistico-ratings/internal/app/grpc"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 )
 
 func main() {
-	lis, err := net.Listen("tcp", ":50051")
+	app := bootstrap.BuildContainer(bootstrap.BuildConfig())
+	config := app.Config.Grpc
+
+	lis, err := net.Listen("tcp", config.Address)
 
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	app := bootstrap.BuildContainer(bootstrap.BuildConfig())
+	opts, err := app.GrpcServerOptions()
+
+	if err != nil {
+		log.Fatalf("Failed to configure server: %v", err)
+	}
+
+	server := grpc.NewServer(opts...)
 
-	opts := grpc.KeepaliveParams(keepalive.ServerParameters{MaxConnectionIdle:5*time.Minute})
-	server := grpc.NewServer(opts)
+	metrics := app.MetricsServer()
+
+	go func() {
+		if err := metrics.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			app.Logger.Errorf("Error serving metrics: %s", err.Error())
+		}
+	}()
+
+	healthServer := health.NewServer()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go app.GrpcHealthChecker(healthServer).Run(ctx)
+
+	streams := app.GrpcTeamRatingStreamService()
 
 	statistico.RegisterTeamRatingServiceServer(server, app.GrpcTeamRatingService())
+	igrpc.RegisterTeamRatingStreamServer(server, streams)
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
 
 	reflection.Register(server)
 
+	signals := make(chan os.Signal, 1)
+
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	done := make(chan struct{})
+
+	go func() {
+		<-signals
+		cancel()
+		shutdown(app, server, healthServer, streams, metrics)
+		close(done)
+	}()
+
 	if err := server.Serve(lis); err != nil {
 		log.Fatalf("Failed to serve: %v", err)
 	}
+
+	// Serve returns once the server stops accepting connections, so wait for in-flight requests to complete.
+	<-done
+}
+
+// shutdown reports the server as not serving so no new requests are routed to it, ends rating streams and waits for
+// in-flight requests to complete, stopping the server if they have not completed within the configured shutdown
+// timeout.
+func shutdown(app bootstrap.Container, server *grpc.Server, h *health.Server, s *igrpc.TeamRatingStreamService, metrics *http.Server) {
+	app.Logger.Info("Shutting down grpc server")
+
+	h.Shutdown()
+	s.Shutdown()
+
+	stopped := make(chan struct{})
+
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(app.Config.Grpc.ShutdownTimeout):
+		app.Logger.Warnf("grpc server did not stop within %s, closing remaining connections", app.Config.Grpc.ShutdownTimeout)
+		server.Stop()
+	}
+
+	if err := metrics.Close(); err != nil {
+		app.Logger.Warnf("Error closing metrics server: %s", err.Error())
+	}
+
+	if err := app.Tracing.Shutdown(context.Background()); err != nil {
+		app.Logger.Warnf("Error flushing trace spans: %s", err.Error())
+	}
 }
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"github.com/statistico/statistico-ratings/internal/app/bootstrap"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	app := bootstrap.BuildContainer(bootstrap.BuildConfig())
+
+	server, err := app.HttpServer()
+
+	if err != nil {
+		log.Fatalf("Failed to configure server: %v", err)
+	}
+
+	signals := make(chan os.Signal, 1)
+
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	done := make(chan struct{})
+
+	go func() {
+		<-signals
+		shutdown(app, server)
+		close(done)
+	}()
+
+	if err := serve(server); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("Failed to serve: %v", err)
+	}
+
+	// serve returns once the server stops accepting connections, so wait for in-flight requests to complete.
+	<-done
+}
+
+// serve serves requests over TLS using the certificates held by the server TLS config if one is configured.
+func serve(server *http.Server) error {
+	if server.TLSConfig != nil {
+		return server.ListenAndServeTLS("", "")
+	}
+
+	return server.ListenAndServe()
+}
+
+// shutdown stops the server accepting connections and waits for in-flight requests to complete, closing the server
+// if they have not completed within the configured shutdown timeout.
+func shutdown(app bootstrap.Container, server *http.Server) {
+	app.Logger.Info("Shutting down http server")
+
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.Http.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		app.Logger.Warnf("http server did not stop within %s, closing remaining connections", app.Config.Http.ShutdownTimeout)
+		server.Close()
+	}
+
+	if err := app.Tracing.Shutdown(context.Background()); err != nil {
+		app.Logger.Warnf("Error flushing trace spans: %s", err.Error())
+	}
+}
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE team_rating ADD COLUMN competition_id INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX ON team_rating (competition_id);
+
+CREATE FUNCTION notify_team_rating() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('team_rating', row_to_json(NEW)::text);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER team_rating_notify AFTER INSERT ON team_rating
+  FOR EACH ROW EXECUTE PROCEDURE notify_team_rating();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER team_rating_notify ON team_rating;
+DROP FUNCTION notify_team_rating();
+ALTER TABLE team_rating DROP COLUMN competition_id;
+-- +goose StatementEnd
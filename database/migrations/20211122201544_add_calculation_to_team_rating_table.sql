@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE team_rating ADD COLUMN adjusted_goals DECIMAL;
+ALTER TABLE team_rating ADD COLUMN k_factor DECIMAL;
+
+CREATE INDEX ON team_rating (fixture_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE team_rating DROP COLUMN adjusted_goals;
+ALTER TABLE team_rating DROP COLUMN k_factor;
+-- +goose StatementEnd
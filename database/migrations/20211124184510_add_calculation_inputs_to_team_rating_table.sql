@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE team_rating ADD COLUMN goals INTEGER;
+ALTER TABLE team_rating ADD COLUMN opponent_attack_total DECIMAL;
+ALTER TABLE team_rating ADD COLUMN opponent_defence_total DECIMAL;
+ALTER TABLE team_rating ADD COLUMN rule_version INTEGER;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE team_rating DROP COLUMN goals;
+ALTER TABLE team_rating DROP COLUMN opponent_attack_total;
+ALTER TABLE team_rating DROP COLUMN opponent_defence_total;
+ALTER TABLE team_rating DROP COLUMN rule_version;
+-- +goose StatementEnd
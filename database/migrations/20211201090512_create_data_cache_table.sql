@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE data_cache (
+  key VARCHAR(255) NOT NULL PRIMARY KEY,
+  value JSONB NOT NULL,
+  timestamp INTEGER NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE data_cache;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE rating_job (
+  id SERIAL PRIMARY KEY,
+  competition_id INTEGER NOT NULL,
+  season_id INTEGER NOT NULL,
+  status VARCHAR(20) NOT NULL,
+  created_at INTEGER NOT NULL,
+  updated_at INTEGER NOT NULL
+);
+
+CREATE INDEX ON rating_job (competition_id, season_id, status);
+
+CREATE TABLE rating_job_fixture (
+  job_id INTEGER NOT NULL REFERENCES rating_job (id) ON DELETE CASCADE,
+  fixture_id INTEGER NOT NULL,
+  status VARCHAR(20) NOT NULL,
+  error TEXT,
+  updated_at INTEGER NOT NULL,
+  PRIMARY KEY (job_id, fixture_id)
+);
+
+CREATE INDEX ON rating_job_fixture (status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE rating_job_fixture;
+DROP TABLE rating_job;
+-- +goose StatementEnd
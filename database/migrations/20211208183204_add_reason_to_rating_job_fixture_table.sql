@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE rating_job_fixture ADD COLUMN reason TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE rating_job_fixture DROP COLUMN reason;
+-- +goose StatementEnd
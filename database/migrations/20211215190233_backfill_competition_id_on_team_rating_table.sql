@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+UPDATE team_rating SET competition_id = seasons.competition_id
+FROM (
+  SELECT season_id, MIN(competition_id) AS competition_id FROM (
+    SELECT season_id, competition_id FROM rating_job
+    UNION
+    SELECT season_id, competition_id FROM team_rating WHERE competition_id <> 0
+  ) AS known
+  GROUP BY season_id
+  HAVING COUNT(DISTINCT competition_id) = 1
+) AS seasons
+WHERE team_rating.competition_id = 0 AND team_rating.season_id = seasons.season_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- Backfilled competitions cannot be told apart from those recorded when the rating was calculated so are kept.
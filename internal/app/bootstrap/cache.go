@@ -0,0 +1,7 @@
+package bootstrap
+
+import "github.com/statistico/statistico-ratings/internal/app/cache"
+
+func (c Container) DataCache() cache.Store {
+	return cache.NewPostgresStore(c.Database, c.Clock)
+}
@@ -0,0 +1,20 @@
+package bootstrap
+
+import (
+	"context"
+	"github.com/statistico/statistico-ratings/internal/app/certificate"
+)
+
+// certificateStore returns a certificate.Store for the files configured, reloading them every reload interval for
+// the life of the process.
+func (c Container) certificateStore(t TLS) (*certificate.Store, error) {
+	store, err := certificate.NewStore(certificate.Files{Cert: t.Cert, Key: t.Key, CA: t.CA}, c.Clock, c.Logger)
+
+	if err != nil {
+		return nil, err
+	}
+
+	go store.Watch(context.Background(), t.ReloadInterval)
+
+	return store, nil
+}
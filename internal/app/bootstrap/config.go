@@ -1,16 +1,27 @@
 package bootstrap
 
 import (
+	"math"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	AwsConfig
+	// Concurrency is the number of fixtures rated in parallel by the team rating handler.
+	Concurrency int
 	Database
+	FixtureFiles
+	Grpc
+	Http
 	KFactorMapping
+	Metrics
 	Sentry
 	StatisticoDataService
 	SupportedCompetitions []uint64
+	Tracing
 }
 
 type AwsConfig struct {
@@ -18,6 +29,9 @@ type AwsConfig struct {
 	Region   string
 	Secret   string
 	S3Bucket string
+	// S3PartSize is the size in megabytes of the parts files written to S3 are uploaded in. Files smaller than a
+	// part are uploaded in a single request.
+	S3PartSize int
 }
 
 type Database struct {
@@ -29,15 +43,116 @@ type Database struct {
 	Name     string
 }
 
+// FixtureFiles configures the fixture and event CSV files used in place of the Statistico data service when
+// Fixtures is not empty.
+type FixtureFiles struct {
+	Fixtures string
+	Events   string
+}
+
+// Grpc configures the gRPC server. Sizes are in bytes.
+type Grpc struct {
+	Address                    string
+	MaxRecvMsgSize             int
+	MaxSendMsgSize             int
+	KeepaliveMaxConnectionIdle time.Duration
+	KeepaliveTime              time.Duration
+	KeepaliveTimeout           time.Duration
+	// HealthCheckInterval is the time between database pings made to update the gRPC health service.
+	HealthCheckInterval time.Duration
+	// ShutdownTimeout is the time in-flight requests are given to complete on shutdown before the server is stopped.
+	ShutdownTimeout time.Duration
+	// LogRequests logs each request handled by the server.
+	LogRequests bool
+	// RecoverPanics returns codes.Internal for requests whose handler panics in place of crashing the server.
+	RecoverPanics bool
+	GrpcAuth
+	GrpcRateLimit
+	// TLS serves requests over TLS if TLS.Cert is set, requiring clients to present a certificate signed by TLS.CA
+	// if set.
+	TLS TLS
+}
+
+// GrpcAuth configures the authentication required for gRPC requests. Mode is either "api-key", "jwt" or empty for no
+// authentication. APIKeys are keyed by the ID of the client each key belongs to. JWTs are verified using the HMAC
+// JWTSecret, and JWTIssuer and JWTAudience if not empty.
+type GrpcAuth struct {
+	Mode        string
+	APIKeys     map[string]string
+	JWTSecret   string
+	JWTIssuer   string
+	JWTAudience string
+}
+
+// GrpcRateLimit configures the number of requests per second allowed for each client, with bursts of up to Burst
+// requests. Requests are not rate limited if PerSecond is zero.
+type GrpcRateLimit struct {
+	PerSecond float64
+	Burst     int
+}
+
+// Http configures the REST gateway. CORSOrigins are the origins browsers are allowed to make requests from, with
+// "*" allowing all origins.
+type Http struct {
+	Address string
+	// ShutdownTimeout is the time in-flight requests are given to complete on shutdown before the server is closed.
+	ShutdownTimeout time.Duration
+	CORSOrigins     []string
+	// TLS serves requests over TLS if TLS.Cert is set, requiring clients to present a certificate signed by TLS.CA
+	// if set.
+	TLS TLS
+}
+
+// TLS configures the PEM encoded certificate key pair presented to peers and the certificate authorities peer
+// certificates are verified against. Files are reloaded every ReloadInterval so certificates can be rotated without
+// restarting.
+type TLS struct {
+	Cert           string
+	Key            string
+	CA             string
+	ReloadInterval time.Duration
+}
+
 type KFactorMapping map[uint64]float64
 
+// Metrics configures the address the Prometheus metrics endpoint is served on.
+type Metrics struct {
+	Address string
+}
+
 type Sentry struct {
 	DSN string
 }
 
+// Tracing configures the exporter spans are sent to, either "otlp" or "stdout". Spans are not exported if
+// Exporter is empty.
+type Tracing struct {
+	Exporter string
+}
+
+// StatisticoDataService configures the connection to the data service. The connection uses TLS if TLSEnabled is
+// true, verifying the server certificate against TLS.CA, or the system roots if not set, and presenting TLS.Cert
+// if set. TLSServerName is the name the server certificate is verified against, defaulting to Host.
 type StatisticoDataService struct {
-	Host string
-	Port string
+	Host          string
+	Port          string
+	Cache         bool
+	TLSEnabled    bool
+	TLSServerName string
+	TLS           TLS
+	// CacheTTL is the time fixtures, fixture search results and seasons are cached for if Cache is true. Events
+	// of finished fixtures are cached until cleared.
+	CacheTTL time.Duration
+	// Timeout is the time each call, including retries, is given to complete.
+	Timeout time.Duration
+	// RetryAttempts is the number of attempts made for calls failing with a transient error, waiting from
+	// RetryBackoff up to RetryMaxBackoff between attempts.
+	RetryAttempts   int
+	RetryBackoff    time.Duration
+	RetryMaxBackoff time.Duration
+	// BreakerFailures is the number of consecutive failed calls after which calls are rejected for BreakerCooldown.
+	BreakerFailures int
+	BreakerCooldown time.Duration
 }
 
 func BuildConfig() *Config {
@@ -50,6 +165,19 @@ func BuildConfig() *Config {
 		S3Bucket: os.Getenv("AWS_S3_BUCKET"),
 	}
 
+	// S3 requires each part other than the last to be at least 5MB.
+	config.AwsConfig.S3PartSize = intEnv("AWS_S3_PART_SIZE", 5)
+
+	if config.AwsConfig.S3PartSize < 5 {
+		config.AwsConfig.S3PartSize = 5
+	}
+
+	config.Concurrency, _ = strconv.Atoi(os.Getenv("RATING_CONCURRENCY"))
+
+	if config.Concurrency < 1 {
+		config.Concurrency = 1
+	}
+
 	config.Database = Database{
 		Driver:   os.Getenv("DB_DRIVER"),
 		Host:     os.Getenv("DB_HOST"),
@@ -59,6 +187,52 @@ func BuildConfig() *Config {
 		Name:     os.Getenv("DB_NAME"),
 	}
 
+	config.FixtureFiles = FixtureFiles{
+		Fixtures: os.Getenv("FIXTURE_FILE"),
+		Events:   os.Getenv("FIXTURE_EVENTS_FILE"),
+	}
+
+	config.Grpc = Grpc{
+		Address:                    stringEnv("GRPC_ADDRESS", ":50051"),
+		MaxRecvMsgSize:             intEnv("GRPC_MAX_RECV_MSG_SIZE", 4*1024*1024),
+		MaxSendMsgSize:             intEnv("GRPC_MAX_SEND_MSG_SIZE", math.MaxInt32),
+		KeepaliveMaxConnectionIdle: durationEnv("GRPC_KEEPALIVE_MAX_CONNECTION_IDLE", 5*time.Minute),
+		KeepaliveTime:              durationEnv("GRPC_KEEPALIVE_TIME", 2*time.Hour),
+		KeepaliveTimeout:           durationEnv("GRPC_KEEPALIVE_TIMEOUT", 20*time.Second),
+		HealthCheckInterval:        durationEnv("GRPC_HEALTH_CHECK_INTERVAL", 10*time.Second),
+		ShutdownTimeout:            durationEnv("GRPC_SHUTDOWN_TIMEOUT", 30*time.Second),
+		LogRequests:                os.Getenv("GRPC_LOG_REQUESTS") != "false",
+		RecoverPanics:              os.Getenv("GRPC_RECOVER_PANICS") != "false",
+		TLS: TLS{
+			Cert:           os.Getenv("GRPC_TLS_CERT"),
+			Key:            os.Getenv("GRPC_TLS_KEY"),
+			CA:             os.Getenv("GRPC_TLS_CLIENT_CA"),
+			ReloadInterval: durationEnv("TLS_RELOAD_INTERVAL", time.Minute),
+		},
+		GrpcAuth: GrpcAuth{
+			Mode:        os.Getenv("GRPC_AUTH"),
+			APIKeys:     apiKeys(os.Getenv("GRPC_API_KEYS")),
+			JWTSecret:   os.Getenv("GRPC_JWT_SECRET"),
+			JWTIssuer:   os.Getenv("GRPC_JWT_ISSUER"),
+			JWTAudience: os.Getenv("GRPC_JWT_AUDIENCE"),
+		},
+	}
+
+	config.Grpc.PerSecond, _ = strconv.ParseFloat(os.Getenv("GRPC_RATE_LIMIT"), 64)
+	config.Grpc.Burst = intEnv("GRPC_RATE_LIMIT_BURST", int(math.Max(1, math.Ceil(config.Grpc.PerSecond))))
+
+	config.Http = Http{
+		Address:         stringEnv("HTTP_ADDRESS", ":8080"),
+		ShutdownTimeout: durationEnv("HTTP_SHUTDOWN_TIMEOUT", 30*time.Second),
+		CORSOrigins:     list(os.Getenv("HTTP_CORS_ORIGINS")),
+		TLS: TLS{
+			Cert:           stringEnv("HTTP_TLS_CERT", config.Grpc.TLS.Cert),
+			Key:            stringEnv("HTTP_TLS_KEY", config.Grpc.TLS.Key),
+			CA:             os.Getenv("HTTP_TLS_CLIENT_CA"),
+			ReloadInterval: durationEnv("TLS_RELOAD_INTERVAL", time.Minute),
+		},
+	}
+
 	config.KFactorMapping = map[uint64]float64{
 		8: 5,
 		9: 4,
@@ -66,14 +240,94 @@ func BuildConfig() *Config {
 		14: 2,
 	}
 
+	config.Metrics = Metrics{Address: os.Getenv("METRICS_ADDRESS")}
+
+	if config.Metrics.Address == "" {
+		config.Metrics.Address = ":9090"
+	}
+
 	config.Sentry = Sentry{DSN: os.Getenv("SENTRY_DSN")}
 
 	config.StatisticoDataService = StatisticoDataService{
-		Host: os.Getenv("STATISTICO_DATA_SERVICE_HOST"),
-		Port: os.Getenv("STATISTICO_DATA_SERVICE_PORT"),
+		Host:          os.Getenv("STATISTICO_DATA_SERVICE_HOST"),
+		Port:          os.Getenv("STATISTICO_DATA_SERVICE_PORT"),
+		Cache:         os.Getenv("STATISTICO_DATA_SERVICE_CACHE") == "true",
+		TLSEnabled:    os.Getenv("STATISTICO_DATA_SERVICE_TLS") == "true",
+		TLSServerName: stringEnv("STATISTICO_DATA_SERVICE_TLS_SERVER_NAME", os.Getenv("STATISTICO_DATA_SERVICE_HOST")),
+		TLS: TLS{
+			Cert:           os.Getenv("STATISTICO_DATA_SERVICE_TLS_CERT"),
+			Key:            os.Getenv("STATISTICO_DATA_SERVICE_TLS_KEY"),
+			CA:             os.Getenv("STATISTICO_DATA_SERVICE_TLS_CA"),
+			ReloadInterval: durationEnv("TLS_RELOAD_INTERVAL", time.Minute),
+		},
+		CacheTTL:        durationEnv("STATISTICO_DATA_SERVICE_CACHE_TTL", 24*time.Hour),
+		Timeout:         durationEnv("STATISTICO_DATA_SERVICE_TIMEOUT", 10*time.Second),
+		RetryAttempts:   intEnv("STATISTICO_DATA_SERVICE_RETRY_ATTEMPTS", 3),
+		RetryBackoff:    durationEnv("STATISTICO_DATA_SERVICE_RETRY_BACKOFF", 200*time.Millisecond),
+		RetryMaxBackoff: durationEnv("STATISTICO_DATA_SERVICE_RETRY_MAX_BACKOFF", 5*time.Second),
+		BreakerFailures: intEnv("STATISTICO_DATA_SERVICE_BREAKER_FAILURES", 5),
+		BreakerCooldown: durationEnv("STATISTICO_DATA_SERVICE_BREAKER_COOLDOWN", 30*time.Second),
 	}
 
 	config.SupportedCompetitions = []uint64{8}
 
+	config.Tracing = Tracing{Exporter: os.Getenv("TRACING_EXPORTER")}
+
 	return &config
 }
+
+// apiKeys parses a comma separated list of client:key pairs.
+func apiKeys(v string) map[string]string {
+	keys := map[string]string{}
+
+	for _, pair := range strings.Split(v, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+
+		if len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+			keys[parts[0]] = parts[1]
+		}
+	}
+
+	return keys
+}
+
+// list parses a comma separated list, ignoring empty values.
+func list(v string) []string {
+	var values []string
+
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			values = append(values, s)
+		}
+	}
+
+	return values
+}
+
+func stringEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+
+	return fallback
+}
+
+func intEnv(key string, fallback int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+
+	if err != nil || v < 1 {
+		return fallback
+	}
+
+	return v
+}
+
+func durationEnv(key string, fallback time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(key))
+
+	if err != nil || v <= 0 {
+		return fallback
+	}
+
+	return v
+}
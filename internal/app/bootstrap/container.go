@@ -1,11 +1,17 @@
 package bootstrap
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/evalphobia/logrus_sentry"
 	"github.com/jonboulle/clockwork"
 	"github.com/sirupsen/logrus"
+	"github.com/statistico/statistico-ratings/internal/app/metrics"
+	"github.com/statistico/statistico-ratings/internal/app/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"os"
 	"time"
 )
@@ -15,6 +21,9 @@ type Container struct {
 	Config   *Config
 	Database *sql.DB
 	Logger   *logrus.Logger
+	Metrics  *metrics.Metrics
+	Tracing  *sdktrace.TracerProvider
+	data     *dataConnection
 }
 
 func BuildContainer(config *Config) Container {
@@ -25,28 +34,49 @@ func BuildContainer(config *Config) Container {
 	c.Clock = clockwork.NewRealClock()
 	c.Database = databaseConnection(config)
 	c.Logger = logger(config)
+	c.Metrics = metrics.NewMetrics(c.Clock)
+	c.Tracing = tracerProvider(config, c.Logger)
+	c.data = &dataConnection{}
 
 	return c
 }
 
 func databaseConnection(config *Config) *sql.DB {
+	conn, err := sql.Open(config.Database.Driver, dataSourceName(config))
+
+	if err != nil {
+		panic(err)
+	}
+
+	conn.SetMaxOpenConns(50)
+	conn.SetMaxIdleConns(25)
+
+	return conn
+}
+
+func dataSourceName(config *Config) string {
 	db := config.Database
 
 	dsn := "host=%s port=%s user=%s " +
 		"password=%s dbname=%s sslmode=disable"
 
-	psqlInfo := fmt.Sprintf(dsn, db.Host, db.Port, db.User, db.Password, db.Name)
+	return fmt.Sprintf(dsn, db.Host, db.Port, db.User, db.Password, db.Name)
+}
 
-	conn, err := sql.Open(db.Driver, psqlInfo)
+// tracerProvider returns the TracerProvider for the configured exporter and registers it globally, along with the
+// W3C trace context propagator, so gRPC instrumentation creates spans under the same traces.
+func tracerProvider(config *Config, l *logrus.Logger) *sdktrace.TracerProvider {
+	tp, err := tracing.NewTracerProvider(context.Background(), config.Tracing.Exporter, "statistico-ratings", os.Stderr)
 
 	if err != nil {
-		panic(err)
+		l.Warnf("Error initializing tracing, spans will not be exported: %s", err.Error())
+		tp, _ = tracing.NewTracerProvider(context.Background(), "", "statistico-ratings", os.Stderr)
 	}
 
-	conn.SetMaxOpenConns(50)
-	conn.SetMaxIdleConns(25)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
 
-	return conn
+	return tp
 }
 
 func logger(config *Config) *logrus.Logger {
@@ -1,55 +1,133 @@
 package bootstrap
 
 import (
+	"fmt"
 	"github.com/statistico/statistico-football-data-go-grpc-client"
 	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app/cache"
+	"github.com/statistico/statistico-ratings/internal/app/metrics"
+	"github.com/statistico/statistico-ratings/internal/app/resilience"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"sync"
 )
 
-func (c Container) DataEventClient() statisticodata.EventClient {
-	config := c.Config
+// dataConnection holds the connection shared by the data service clients, dialed on first use so commands that
+// do not call the data service do not connect to it.
+type dataConnection struct {
+	once sync.Once
+	conn *grpc.ClientConn
+}
 
-	address := config.StatisticoDataService.Host + ":" + config.StatisticoDataService.Port
+// DataConnection returns the connection shared by the data service clients. Calls failing with UNAVAILABLE, and
+// FixtureEvents calls failing with INTERNAL, are retried with backoff by the connection, the only retry layer. Each
+// call is given the configured timeout across its attempts and calls are rejected by a circuit breaker while the
+// data service is failing. Dial errors are configuration errors so panic rather than return a client
+// that can never succeed.
+func (c Container) DataConnection() *grpc.ClientConn {
+	c.data.once.Do(func() {
+		config := c.Config.StatisticoDataService
+
+		address := config.Host + ":" + config.Port
+
+		breaker := resilience.NewCircuitBreaker(
+			"statistico data service",
+			config.BreakerFailures,
+			config.BreakerCooldown,
+			c.Clock,
+			c.Logger,
+		)
+
+		conn, err := grpc.Dial(
+			address,
+			c.dataTransportCredentials(),
+			grpc.WithDefaultServiceConfig(resilience.ServiceConfig(c.dataBackoff())),
+			grpc.WithChainUnaryInterceptor(
+				otelgrpc.UnaryClientInterceptor(),
+				breaker.UnaryClientInterceptor(),
+				resilience.TimeoutInterceptor(config.Timeout),
+			),
+		)
+
+		if err != nil {
+			panic(fmt.Errorf("error initializing statistico data service grpc connection: %s", err.Error()))
+		}
+
+		c.data.conn = conn
+	})
+
+	return c.data.conn
+}
 
-	conn, err := grpc.Dial(address, grpc.WithInsecure())
+func (c Container) DataEventClient() statisticodata.EventClient {
+	client := statistico.NewEventServiceClient(c.DataConnection())
 
-	if err != nil {
-		c.Logger.Warnf("Error initializing statistico data service grpc client %s", err.Error())
-	}
+	instrumented := metrics.NewEventClient(statisticodata.NewEventClient(client), c.Metrics)
 
-	client := statistico.NewEventServiceClient(conn)
+	if c.Config.StatisticoDataService.Cache {
+		return cache.NewEventClient(instrumented, c.DataCache(), c.Logger)
+	}
 
-	return statisticodata.NewEventClient(client)
+	return instrumented
 }
 
 func (c Container) DataFixtureClient() statisticodata.FixtureClient {
-	config := c.Config
+	client := statistico.NewFixtureServiceClient(c.DataConnection())
 
-	address := config.StatisticoDataService.Host + ":" + config.StatisticoDataService.Port
+	instrumented := metrics.NewFixtureClient(statisticodata.NewFixtureClient(client), c.Metrics)
 
-	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if c.Config.StatisticoDataService.Cache {
+		return cache.NewFixtureClient(instrumented, c.DataCache(), c.Config.StatisticoDataService.CacheTTL, c.Logger)
+	}
 
-	if err != nil {
-		c.Logger.Warnf("Error initializing statistico data service grpc client %s", err.Error())
+	return instrumented
+}
+
+func (c Container) DataSeasonClient() statisticodata.SeasonClient {
+	client := statistico.NewSeasonServiceClient(c.DataConnection())
+
+	instrumented := metrics.NewSeasonClient(statisticodata.NewSeasonClient(client), c.Metrics)
+
+	if c.Config.StatisticoDataService.Cache {
+		return cache.NewSeasonClient(instrumented, c.DataCache(), c.Config.StatisticoDataService.CacheTTL, c.Logger)
 	}
 
-	client := statistico.NewFixtureServiceClient(conn)
+	return instrumented
+}
+
+func (c Container) DataResultClient() statisticodata.ResultClient {
+	client := statistico.NewResultServiceClient(c.DataConnection())
 
-	return statisticodata.NewFixtureClient(client)
+	return metrics.NewResultClient(statisticodata.NewResultClient(client), c.Metrics)
 }
 
-func (c Container) DataSeasonClient() statisticodata.SeasonClient {
-	config := c.Config
+func (c Container) dataBackoff() resilience.Backoff {
+	config := c.Config.StatisticoDataService
 
-	address := config.StatisticoDataService.Host + ":" + config.StatisticoDataService.Port
+	return resilience.Backoff{
+		Attempts:   config.RetryAttempts,
+		Initial:    config.RetryBackoff,
+		Max:        config.RetryMaxBackoff,
+		Multiplier: 2,
+	}
+}
 
-	conn, err := grpc.Dial(address, grpc.WithInsecure())
+// dataTransportCredentials returns the dial option securing the connection to the data service, using TLS if
+// enabled. Certificates that cannot be loaded are a configuration error so panic rather than fall back to an
+// insecure connection.
+func (c Container) dataTransportCredentials() grpc.DialOption {
+	config := c.Config.StatisticoDataService
 
-	if err != nil {
-		c.Logger.Warnf("Error initializing statistico data service grpc client %s", err.Error())
+	if !config.TLSEnabled {
+		return grpc.WithInsecure()
 	}
 
-	client := statistico.NewSeasonServiceClient(conn)
+	store, err := c.certificateStore(config.TLS)
+
+	if err != nil {
+		panic(fmt.Errorf("error loading statistico data service tls certificates: %s", err.Error()))
+	}
 
-	return statisticodata.NewSeasonClient(client)
+	return grpc.WithTransportCredentials(credentials.NewTLS(store.ClientConfig(config.TLSServerName)))
 }
@@ -5,10 +5,53 @@ import (
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/statistico/statistico-ratings/internal/app/filesystem"
+	"net/http"
+	"time"
 )
 
+// FilesystemReader returns a filesystem.Reader selecting a local, HTTP(S) or S3 reader by the URI scheme of each
+// filename read. Filenames without a scheme are read from the configured S3 bucket, or from local disk if AWS is
+// not configured.
 func (c Container) FilesystemReader() filesystem.Reader {
+	client := &http.Client{Timeout: time.Minute}
+
+	readers := map[string]filesystem.Reader{
+		"file":  filesystem.NewGzipReader(filesystem.NewLocalReader()),
+		"http":  filesystem.NewGzipReader(filesystem.NewHTTPReader(client)),
+		"https": filesystem.NewGzipReader(filesystem.NewHTTPReader(client)),
+	}
+
+	if c.Config.AwsConfig.Region == "" {
+		return filesystem.NewSchemeReader(readers, "file")
+	}
+
+	readers["s3"] = filesystem.NewGzipReader(filesystem.NewS3Reader(c.s3Client(), c.Config.S3Bucket))
+
+	return filesystem.NewSchemeReader(readers, "s3")
+}
+
+// FilesystemWriter returns a filesystem.Writer selecting a local or S3 writer by the URI scheme of each filename
+// written. Filenames without a scheme are written to local disk, so output is only uploaded to S3 when asked for.
+// Files written to S3 are uploaded in parts of the configured size as they are written.
+func (c Container) FilesystemWriter() filesystem.Writer {
+	writers := map[string]filesystem.Writer{
+		"file": filesystem.NewLocalWriter(),
+	}
+
+	if c.Config.AwsConfig.Region != "" {
+		uploader := s3manager.NewUploaderWithClient(c.s3Client(), func(u *s3manager.Uploader) {
+			u.PartSize = int64(c.Config.S3PartSize) * 1024 * 1024
+		})
+
+		writers["s3"] = filesystem.NewS3Writer(uploader, c.Config.S3Bucket)
+	}
+
+	return filesystem.NewSchemeWriter(writers, "file")
+}
+
+func (c Container) s3Client() *s3.S3 {
 	key := c.Config.AwsConfig.Key
 	secret := c.Config.AwsConfig.Secret
 
@@ -21,5 +64,5 @@ func (c Container) FilesystemReader() filesystem.Reader {
 		panic(err)
 	}
 
-	return filesystem.NewS3Reader(s3.New(sess), c.Config.S3Bucket)
+	return s3.New(sess)
 }
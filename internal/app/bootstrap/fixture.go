@@ -1,12 +1,46 @@
 package bootstrap
 
-import "github.com/statistico/statistico-ratings/internal/app/fixture"
+import (
+	"github.com/statistico/statistico-football-data-go-grpc-client"
+	"github.com/statistico/statistico-ratings/internal/app/fixture"
+	"github.com/statistico/statistico-ratings/internal/app/metrics"
+	"github.com/statistico/statistico-ratings/internal/app/tracing"
+)
+
+func (c Container) FixtureEventClient() statisticodata.EventClient {
+	if c.Config.FixtureFiles.Fixtures != "" {
+		return tracing.NewEventClient(fixture.NewFileEventClient(c.FilesystemReader(), c.Config.FixtureFiles.Events), c.Tracer())
+	}
+
+	return tracing.NewEventClient(c.DataEventClient(), c.Tracer())
+}
 
 func (c Container) FixtureFetcher() fixture.Fetcher {
-	return fixture.NewFetcher(
+	if c.Config.FixtureFiles.Fixtures != "" {
+		f := fixture.NewFileFetcher(
+			c.Config.SupportedCompetitions,
+			c.FilesystemReader(),
+			c.Config.FixtureFiles.Fixtures,
+			c.Clock,
+		)
+
+		return tracing.NewFetcher(f, c.Tracer())
+	}
+
+	f := fixture.NewFetcher(
 		c.Config.SupportedCompetitions,
 		c.DataFixtureClient(),
 		c.DataSeasonClient(),
 		c.Clock,
 	)
+
+	return tracing.NewFetcher(f, c.Tracer())
+}
+
+func (c Container) FixtureStatusChecker() fixture.StatusChecker {
+	if c.Config.FixtureFiles.Fixtures != "" {
+		return fixture.NewFileStatusChecker()
+	}
+
+	return metrics.NewStatusChecker(fixture.NewResultStatusChecker(c.DataResultClient()), c.Metrics)
 }
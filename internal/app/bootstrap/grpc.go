@@ -1,7 +1,122 @@
 package bootstrap
 
-import "github.com/statistico/statistico-ratings/internal/app/grpc"
+import (
+	"errors"
+	"fmt"
+	"github.com/statistico/statistico-ratings/internal/app/grpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/keepalive"
+)
+
+func (c Container) GrpcHealthChecker(s *health.Server) *grpc.HealthChecker {
+	return grpc.NewHealthChecker(s, c.Database, c.Config.Grpc.HealthCheckInterval, c.Clock, c.Logger)
+}
+
+// GrpcServerOptions returns the options the gRPC server is built with. Requests are served over TLS if a
+// certificate is configured. Interceptors run in the order tracing, metrics, panic recovery, request logging,
+// authentication and rate limiting, with those disabled in config omitted. Request logging runs before
+// authentication so rejected requests are logged, and logs the client authentication records for it. Failed
+// authentication attempts count against the rate limit of the peer.
+func (c Container) GrpcServerOptions() ([]ggrpc.ServerOption, error) {
+	config := c.Config.Grpc
+
+	unary := []ggrpc.UnaryServerInterceptor{otelgrpc.UnaryServerInterceptor(), c.Metrics.UnaryServerInterceptor()}
+	stream := []ggrpc.StreamServerInterceptor{otelgrpc.StreamServerInterceptor()}
+
+	if config.RecoverPanics {
+		r := grpc.NewPanicRecoverer(c.Logger)
+		unary = append(unary, r.UnaryServerInterceptor())
+		stream = append(stream, r.StreamServerInterceptor())
+	}
+
+	if config.LogRequests {
+		l := grpc.NewRequestLogger(c.Logger, c.Clock)
+		unary = append(unary, l.UnaryServerInterceptor())
+		stream = append(stream, l.StreamServerInterceptor())
+	}
+
+	var limiter *grpc.RateLimiter
+
+	if config.PerSecond > 0 {
+		limiter = grpc.NewRateLimiter(config.PerSecond, config.Burst, c.Clock)
+	}
+
+	auth, err := c.grpcAuthenticator()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if auth != nil {
+		a := grpc.NewAuthInterceptor(auth, limiter)
+		unary = append(unary, a.UnaryServerInterceptor())
+		stream = append(stream, a.StreamServerInterceptor())
+	}
+
+	if limiter != nil {
+		unary = append(unary, limiter.UnaryServerInterceptor())
+		stream = append(stream, limiter.StreamServerInterceptor())
+	}
+
+	opts := []ggrpc.ServerOption{
+		ggrpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle: config.KeepaliveMaxConnectionIdle,
+			Time:              config.KeepaliveTime,
+			Timeout:           config.KeepaliveTimeout,
+		}),
+		ggrpc.MaxRecvMsgSize(config.MaxRecvMsgSize),
+		ggrpc.MaxSendMsgSize(config.MaxSendMsgSize),
+		ggrpc.ChainUnaryInterceptor(unary...),
+		ggrpc.ChainStreamInterceptor(stream...),
+	}
+
+	if config.TLS.Cert == "" {
+		if config.TLS.CA != "" {
+			return nil, errors.New("a grpc tls certificate is required to verify client certificates")
+		}
+
+		return opts, nil
+	}
+
+	store, err := c.certificateStore(config.TLS)
+
+	if err != nil {
+		return nil, fmt.Errorf("error loading grpc tls certificates: %s", err.Error())
+	}
+
+	return append(opts, ggrpc.Creds(credentials.NewTLS(store.ServerConfig()))), nil
+}
 
 func (c Container) GrpcTeamRatingService() *grpc.TeamRatingService {
 	return grpc.NewTeamRatingService(c.TeamRatingReader(), c.Logger)
 }
+
+func (c Container) GrpcTeamRatingStreamService() *grpc.TeamRatingStreamService {
+	return grpc.NewTeamRatingStreamService(c.TeamRatingStream(), c.Logger)
+}
+
+func (c Container) grpcAuthenticator() (grpc.Authenticator, error) {
+	auth := c.Config.Grpc.GrpcAuth
+
+	switch auth.Mode {
+	case "":
+		return nil, nil
+	case "api-key":
+		if len(auth.APIKeys) == 0 {
+			return nil, fmt.Errorf("grpc auth mode %q requires at least one api key", auth.Mode)
+		}
+
+		return grpc.NewAPIKeyAuthenticator(auth.APIKeys), nil
+	case "jwt":
+		if auth.JWTSecret == "" {
+			return nil, fmt.Errorf("grpc auth mode %q requires a jwt secret", auth.Mode)
+		}
+
+		return grpc.NewJWTAuthenticator([]byte(auth.JWTSecret), auth.JWTIssuer, auth.JWTAudience, c.Clock), nil
+	default:
+		return nil, fmt.Errorf("grpc auth mode %q is not supported", auth.Mode)
+	}
+}
@@ -0,0 +1,67 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"github.com/statistico/statistico-ratings/internal/app/grpc"
+	"github.com/statistico/statistico-ratings/internal/app/rest"
+	"net/http"
+	"time"
+)
+
+// HttpServer returns a http.Server serving the REST gateway at the configured address. Requests are authenticated
+// and rate limited as configured for the gRPC server, so the gateway does not expose ratings the gRPC server
+// protects, and served over TLS as configured for the gateway. Requests pass through request logging, CORS, authentication and rate limiting in that
+// order, so preflight requests are answered without credentials. Failed authentication attempts count against the
+// rate limit of the remote address.
+func (c Container) HttpServer() (*http.Server, error) {
+	config := c.Config.Grpc
+
+	var handler http.Handler = rest.NewServeMux(c.TeamRatingReader(), c.Logger)
+
+	var limiter *grpc.RateLimiter
+
+	if config.PerSecond > 0 {
+		limiter = grpc.NewRateLimiter(config.PerSecond, config.Burst, c.Clock)
+		handler = rest.RateLimit(handler, limiter)
+	}
+
+	auth, err := c.grpcAuthenticator()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if auth != nil {
+		handler = rest.Authenticate(handler, auth, limiter)
+	}
+
+	handler = rest.RequestLogger(rest.CORS(handler, c.Config.Http.CORSOrigins), c.Logger, c.Clock)
+
+	server := &http.Server{
+		Addr:              c.Config.Http.Address,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+
+	tls := c.Config.Http.TLS
+
+	if tls.Cert == "" {
+		if tls.CA != "" {
+			return nil, errors.New("a http tls certificate is required to verify client certificates")
+		}
+
+		return server, nil
+	}
+
+	store, err := c.certificateStore(tls)
+
+	if err != nil {
+		return nil, fmt.Errorf("error loading http tls certificates: %s", err.Error())
+	}
+
+	server.TLSConfig = store.ServerConfig()
+
+	return server, nil
+}
@@ -0,0 +1,7 @@
+package bootstrap
+
+import "github.com/statistico/statistico-ratings/internal/app/job"
+
+func (c Container) JobRepository() job.Repository {
+	return job.NewRepository(c.Database, c.Clock)
+}
@@ -0,0 +1,11 @@
+package bootstrap
+
+import "net/http"
+
+// MetricsServer returns a http.Server exposing Prometheus metrics on /metrics at the configured metrics address.
+func (c Container) MetricsServer() *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", c.Metrics.Handler())
+
+	return &http.Server{Addr: c.Config.Metrics.Address, Handler: mux}
+}
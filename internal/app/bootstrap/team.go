@@ -1,36 +1,69 @@
 package bootstrap
 
-import "github.com/statistico/statistico-ratings/internal/app/team"
+import (
+	"github.com/lib/pq"
+	"github.com/statistico/statistico-football-data-go-grpc-client"
+	"github.com/statistico/statistico-ratings/internal/app/metrics"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"github.com/statistico/statistico-ratings/internal/app/tracing"
+	"time"
+)
 
-func (c Container) TeamRatingCalculator() team.RatingCalculator {
+func (c Container) TeamRatingCalculator(e statisticodata.EventClient) team.RatingCalculator {
 	return team.NewRatingCalculator(
-		c.DataEventClient(),
+		e,
 		c.Config.KFactorMapping,
 		c.Clock,
 	)
 }
 
+func (c Container) TeamRatingExporter() team.RatingExporter {
+	return team.NewRatingExporter(c.Database)
+}
+
 func (c Container) TeamRatingHandler() team.RatingHandler {
+	events := team.NewEventPrefetcher(c.FixtureEventClient(), c.Config.Concurrency)
+
 	return team.NewHandler(
 		c.FixtureFetcher(),
-		c.TeamRatingProcessor(),
+		c.FixtureStatusChecker(),
+		c.TeamRatingProcessor(events),
+		events,
+		c.JobRepository(),
+		c.Config.Concurrency,
 		c.Clock,
 		c.Logger,
 	)
 }
 
-func (c Container) TeamRatingProcessor() team.RatingProcessor {
-	return team.NewRatingProcessor(
+func (c Container) TeamRatingProcessor(e statisticodata.EventClient) team.RatingProcessor {
+	processor := team.NewRatingProcessor(
 		c.TeamRatingReader(),
 		c.TeamRatingWriter(),
-		c.TeamRatingCalculator(),
+		c.TeamRatingCalculator(e),
 	)
+
+	return tracing.NewRatingProcessor(metrics.NewRatingProcessor(processor, c.Metrics), c.Tracer())
 }
 
 func (c Container) TeamRatingReader() team.RatingReader {
-	return team.NewRatingReader(c.Database)
+	return tracing.NewRatingReader(team.NewRatingReader(c.Database), c.Tracer())
+}
+
+func (c Container) TeamRatingStream() team.RatingStream {
+	listener := pq.NewListener(dataSourceName(c.Config), 10*time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			c.Logger.Errorf("Error in team rating listener: %s", err.Error())
+		}
+	})
+
+	if err := listener.Listen("team_rating"); err != nil {
+		c.Logger.Errorf("Error listening to team rating channel: %s", err.Error())
+	}
+
+	return team.NewRatingStream(listener.Notify, c.Logger)
 }
 
 func (c Container) TeamRatingWriter() team.RatingWriter {
-	return team.NewRatingWriter(c.Database)
+	return metrics.NewRatingWriter(tracing.NewRatingWriter(team.NewRatingWriter(c.Database), c.Tracer()), c.Metrics)
 }
@@ -0,0 +1,10 @@
+package bootstrap
+
+import (
+	"github.com/statistico/statistico-ratings/internal/app/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func (c Container) Tracer() trace.Tracer {
+	return c.Tracing.Tracer(tracing.Name)
+}
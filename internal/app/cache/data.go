@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github.com/statistico/statistico-football-data-go-grpc-client"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app/fixture"
+	"time"
+)
+
+type eventClient struct {
+	client statisticodata.EventClient
+	store  Store
+	logger *logrus.Logger
+}
+
+// FixtureEvents caches events only for fixtures recorded as finished in ctx by fixture.WithFinished, so events of a
+// fixture in progress are never held in the Store.
+func (e *eventClient) FixtureEvents(ctx context.Context, fixtureID uint64) (*statistico.FixtureEventsResponse, error) {
+	key := fmt.Sprintf("fixture_events:%d", fixtureID)
+
+	var res statistico.FixtureEventsResponse
+
+	if get(ctx, e.store, e.logger, key, &res) {
+		return &res, nil
+	}
+
+	events, err := e.client.FixtureEvents(ctx, fixtureID)
+
+	if err != nil || events == nil {
+		return events, err
+	}
+
+	if fixture.FinishedFromContext(ctx, fixtureID) {
+		set(ctx, e.store, e.logger, key, events, 0)
+	}
+
+	return events, nil
+}
+
+type fixtureClient struct {
+	client statisticodata.FixtureClient
+	store  Store
+	ttl    time.Duration
+	logger *logrus.Logger
+}
+
+func (f *fixtureClient) Search(ctx context.Context, req *statistico.FixtureSearchRequest) ([]*statistico.Fixture, error) {
+	b, err := json.Marshal(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("fixture_search:%x", sha256.Sum256(b))
+
+	var res []*statistico.Fixture
+
+	if get(ctx, f.store, f.logger, key, &res) {
+		return res, nil
+	}
+
+	fixtures, err := f.client.Search(ctx, req)
+
+	if err != nil {
+		return fixtures, err
+	}
+
+	set(ctx, f.store, f.logger, key, fixtures, f.ttl)
+
+	return fixtures, nil
+}
+
+func (f *fixtureClient) ByID(ctx context.Context, fixtureID uint64) (*statistico.Fixture, error) {
+	key := fmt.Sprintf("fixture:%d", fixtureID)
+
+	var res statistico.Fixture
+
+	if get(ctx, f.store, f.logger, key, &res) {
+		return &res, nil
+	}
+
+	fixture, err := f.client.ByID(ctx, fixtureID)
+
+	if err != nil || fixture == nil {
+		return fixture, err
+	}
+
+	set(ctx, f.store, f.logger, key, fixture, f.ttl)
+
+	return fixture, nil
+}
+
+type seasonClient struct {
+	client statisticodata.SeasonClient
+	store  Store
+	ttl    time.Duration
+	logger *logrus.Logger
+}
+
+func (s *seasonClient) ByTeamID(ctx context.Context, teamId uint64, sort string) ([]*statistico.Season, error) {
+	key := fmt.Sprintf("team_seasons:%d:%s", teamId, sort)
+
+	var res []*statistico.Season
+
+	if get(ctx, s.store, s.logger, key, &res) {
+		return res, nil
+	}
+
+	seasons, err := s.client.ByTeamID(ctx, teamId, sort)
+
+	if err != nil {
+		return seasons, err
+	}
+
+	set(ctx, s.store, s.logger, key, seasons, s.ttl)
+
+	return seasons, nil
+}
+
+func (s *seasonClient) ByCompetitionID(ctx context.Context, competitionId uint64, sort string) ([]*statistico.Season, error) {
+	key := fmt.Sprintf("competition_seasons:%d:%s", competitionId, sort)
+
+	var res []*statistico.Season
+
+	if get(ctx, s.store, s.logger, key, &res) {
+		return res, nil
+	}
+
+	seasons, err := s.client.ByCompetitionID(ctx, competitionId, sort)
+
+	if err != nil {
+		return seasons, err
+	}
+
+	set(ctx, s.store, s.logger, key, seasons, s.ttl)
+
+	return seasons, nil
+}
+
+// FixtureKeys returns the keys used to cache the fixture and fixture events for the fixture ID provided.
+func FixtureKeys(fixtureID uint64) []string {
+	return []string{
+		fmt.Sprintf("fixture:%d", fixtureID),
+		fmt.Sprintf("fixture_events:%d", fixtureID),
+	}
+}
+
+// get returns true if a value is held in the Store for the key provided. Store errors are logged and treated as a
+// cache miss so a failing cache never fails a call that the data service can still serve.
+func get(ctx context.Context, s Store, l *logrus.Logger, key string, v interface{}) bool {
+	ok, err := s.Get(ctx, key, v)
+
+	if err != nil {
+		l.Warnf("error reading %s from data cache: %s", key, err.Error())
+		return false
+	}
+
+	return ok
+}
+
+func set(ctx context.Context, s Store, l *logrus.Logger, key string, v interface{}, ttl time.Duration) {
+	if err := s.Set(ctx, key, v, ttl); err != nil {
+		l.Warnf("error writing %s to data cache: %s", key, err.Error())
+	}
+}
+
+// NewEventClient returns a statisticodata.EventClient returning fixture events held in the Store provided and
+// falling back to the wrapped EventClient for fixtures not yet cached. Events are only cached for fixtures the
+// caller has recorded as finished with fixture.WithFinished.
+func NewEventClient(c statisticodata.EventClient, s Store, l *logrus.Logger) statisticodata.EventClient {
+	return &eventClient{client: c, store: s, logger: l}
+}
+
+// NewFixtureClient returns a statisticodata.FixtureClient returning fixtures held in the Store provided and
+// falling back to the wrapped FixtureClient for requests not yet cached. Fixtures are cached for ttl, as fixture
+// dates and search results change as fixtures are rescheduled and played.
+func NewFixtureClient(c statisticodata.FixtureClient, s Store, ttl time.Duration, l *logrus.Logger) statisticodata.FixtureClient {
+	return &fixtureClient{client: c, store: s, ttl: ttl, logger: l}
+}
+
+// NewSeasonClient returns a statisticodata.SeasonClient returning seasons held in the Store provided and
+// falling back to the wrapped SeasonClient for requests not yet cached. Seasons are cached for ttl so a new
+// current season is picked up once it starts.
+func NewSeasonClient(c statisticodata.SeasonClient, s Store, ttl time.Duration, l *logrus.Logger) statisticodata.SeasonClient {
+	return &seasonClient{client: c, store: s, ttl: ttl, logger: l}
+}
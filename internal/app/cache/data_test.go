@@ -0,0 +1,389 @@
+package cache_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app/cache"
+	"github.com/statistico/statistico-ratings/internal/app/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestEventClient_FixtureEvents(t *testing.T) {
+	ctx := fixture.WithFinished(context.Background(), []*statistico.Fixture{{Id: 26}})
+
+	t.Run("returns cached fixture events without calling the wrapped client", func(t *testing.T) {
+		t.Helper()
+
+		events := new(MockEventClient)
+		store := new(MockStore)
+		logger, _ := test.NewNullLogger()
+
+		client := cache.NewEventClient(events, store, logger)
+
+		cached := statistico.FixtureEventsResponse{
+			FixtureId: 26,
+			Goals:     []*statistico.GoalEvent{{TeamId: 1, Minute: 4}},
+		}
+
+		store.On("Get", mock.Anything, "fixture_events:26", mock.Anything).Run(unmarshal(t, &cached)).Return(true, nil)
+
+		res, err := client.FixtureEvents(ctx, 26)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, uint64(26), res.FixtureId)
+		assert.Equal(t, uint64(1), res.Goals[0].TeamId)
+		assert.Equal(t, uint32(4), res.Goals[0].Minute)
+		events.AssertNotCalled(t, "FixtureEvents", ctx, uint64(26))
+		store.AssertExpectations(t)
+	})
+
+	t.Run("calls the wrapped client and caches the response on a cache miss", func(t *testing.T) {
+		t.Helper()
+
+		events := new(MockEventClient)
+		store := new(MockStore)
+		logger, _ := test.NewNullLogger()
+
+		client := cache.NewEventClient(events, store, logger)
+
+		res := statistico.FixtureEventsResponse{FixtureId: 26}
+
+		store.On("Get", mock.Anything, "fixture_events:26", mock.Anything).Return(false, nil)
+		events.On("FixtureEvents", ctx, uint64(26)).Return(&res, nil)
+		store.On("Set", mock.Anything, "fixture_events:26", &res, time.Duration(0)).Return(nil)
+
+		fetched, err := client.FixtureEvents(ctx, 26)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &res, fetched)
+		events.AssertExpectations(t)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("logs a warning and calls the wrapped client if store returns an error", func(t *testing.T) {
+		t.Helper()
+
+		events := new(MockEventClient)
+		store := new(MockStore)
+		logger, hook := test.NewNullLogger()
+
+		client := cache.NewEventClient(events, store, logger)
+
+		res := statistico.FixtureEventsResponse{FixtureId: 26}
+
+		store.On("Get", mock.Anything, "fixture_events:26", mock.Anything).Return(false, errors.New("store error"))
+		events.On("FixtureEvents", ctx, uint64(26)).Return(&res, nil)
+		store.On("Set", mock.Anything, "fixture_events:26", &res, time.Duration(0)).Return(nil)
+
+		fetched, err := client.FixtureEvents(ctx, 26)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &res, fetched)
+		assert.Equal(t, "error reading fixture_events:26 from data cache: store error", hook.LastEntry().Message)
+		events.AssertExpectations(t)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("does not cache errors returned by the wrapped client", func(t *testing.T) {
+		t.Helper()
+
+		events := new(MockEventClient)
+		store := new(MockStore)
+		logger, _ := test.NewNullLogger()
+
+		client := cache.NewEventClient(events, store, logger)
+
+		store.On("Get", mock.Anything, "fixture_events:26", mock.Anything).Return(false, nil)
+		events.On("FixtureEvents", ctx, uint64(26)).Return(&statistico.FixtureEventsResponse{}, errors.New("event client error"))
+
+		_, err := client.FixtureEvents(ctx, 26)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "event client error", err.Error())
+		store.AssertNotCalled(t, "Set", mock.Anything, "fixture_events:26", mock.Anything, mock.Anything)
+	})
+
+	t.Run("does not cache events of a fixture not recorded as finished", func(t *testing.T) {
+		t.Helper()
+
+		events := new(MockEventClient)
+		store := new(MockStore)
+		logger, _ := test.NewNullLogger()
+
+		client := cache.NewEventClient(events, store, logger)
+
+		res := statistico.FixtureEventsResponse{FixtureId: 27}
+
+		store.On("Get", mock.Anything, "fixture_events:27", mock.Anything).Return(false, nil)
+		events.On("FixtureEvents", ctx, uint64(27)).Return(&res, nil)
+
+		fetched, err := client.FixtureEvents(ctx, 27)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &res, fetched)
+		events.AssertExpectations(t)
+		store.AssertNotCalled(t, "Set", mock.Anything, "fixture_events:27", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFixtureClient_Search(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("caches search results by request", func(t *testing.T) {
+		t.Helper()
+
+		fixtures := new(MockFixtureClient)
+		store := new(MockStore)
+		logger, _ := test.NewNullLogger()
+
+		client := cache.NewFixtureClient(fixtures, store, time.Hour, logger)
+
+		req := statistico.FixtureSearchRequest{SeasonIds: []uint64{17462}}
+		res := []*statistico.Fixture{{Id: 26}, {Id: 27}}
+
+		key := mock.MatchedBy(func(k string) bool {
+			return len(k) == len("fixture_search:")+64
+		})
+
+		store.On("Get", mock.Anything, key, mock.Anything).Return(false, nil)
+		fixtures.On("Search", ctx, &req).Return(res, nil)
+		store.On("Set", mock.Anything, key, res, time.Hour).Return(nil)
+
+		fetched, err := client.Search(ctx, &req)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, res, fetched)
+		fixtures.AssertExpectations(t)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("returns cached search results", func(t *testing.T) {
+		t.Helper()
+
+		fixtures := new(MockFixtureClient)
+		store := new(MockStore)
+		logger, _ := test.NewNullLogger()
+
+		client := cache.NewFixtureClient(fixtures, store, time.Hour, logger)
+
+		req := statistico.FixtureSearchRequest{SeasonIds: []uint64{17462}}
+		cached := []*statistico.Fixture{{Id: 26}, {Id: 27}}
+
+		store.On("Get", mock.Anything, mock.Anything, mock.Anything).Run(unmarshal(t, cached)).Return(true, nil)
+
+		fetched, err := client.Search(ctx, &req)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, 2, len(fetched))
+		assert.Equal(t, int64(26), fetched[0].Id)
+		assert.Equal(t, int64(27), fetched[1].Id)
+		fixtures.AssertNotCalled(t, "Search", ctx, &req)
+	})
+}
+
+func TestFixtureClient_ByID(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("calls the wrapped client and caches the fixture on a cache miss", func(t *testing.T) {
+		t.Helper()
+
+		fixtures := new(MockFixtureClient)
+		store := new(MockStore)
+		logger, _ := test.NewNullLogger()
+
+		client := cache.NewFixtureClient(fixtures, store, time.Hour, logger)
+
+		res := statistico.Fixture{Id: 26}
+
+		store.On("Get", mock.Anything, "fixture:26", mock.Anything).Return(false, nil)
+		fixtures.On("ByID", ctx, uint64(26)).Return(&res, nil)
+		store.On("Set", mock.Anything, "fixture:26", &res, time.Hour).Return(nil)
+
+		fetched, err := client.ByID(ctx, 26)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &res, fetched)
+		fixtures.AssertExpectations(t)
+		store.AssertExpectations(t)
+	})
+}
+
+func TestSeasonClient_ByCompetitionID(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns cached seasons for a competition", func(t *testing.T) {
+		t.Helper()
+
+		seasons := new(MockSeasonClient)
+		store := new(MockStore)
+		logger, _ := test.NewNullLogger()
+
+		client := cache.NewSeasonClient(seasons, store, time.Hour, logger)
+
+		cached := []*statistico.Season{{Id: 17462, Name: "2020/2021"}}
+
+		store.On("Get", mock.Anything, "competition_seasons:8:name_desc", mock.Anything).Run(unmarshal(t, cached)).Return(true, nil)
+
+		fetched, err := client.ByCompetitionID(ctx, 8, "name_desc")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, 1, len(fetched))
+		assert.Equal(t, "2020/2021", fetched[0].Name)
+		seasons.AssertNotCalled(t, "ByCompetitionID", ctx, uint64(8), "name_desc")
+	})
+
+	t.Run("caches seasons for a competition for the ttl provided", func(t *testing.T) {
+		t.Helper()
+
+		seasons := new(MockSeasonClient)
+		store := new(MockStore)
+		logger, _ := test.NewNullLogger()
+
+		client := cache.NewSeasonClient(seasons, store, time.Hour, logger)
+
+		res := []*statistico.Season{{Id: 17462, Name: "2020/2021"}}
+
+		store.On("Get", mock.Anything, "competition_seasons:8:name_desc", mock.Anything).Return(false, nil)
+		seasons.On("ByCompetitionID", ctx, uint64(8), "name_desc").Return(res, nil)
+		store.On("Set", mock.Anything, "competition_seasons:8:name_desc", res, time.Hour).Return(nil)
+
+		fetched, err := client.ByCompetitionID(ctx, 8, "name_desc")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, res, fetched)
+		seasons.AssertExpectations(t)
+		store.AssertExpectations(t)
+	})
+}
+
+func TestFixtureKeys(t *testing.T) {
+	t.Run("returns fixture and fixture events keys", func(t *testing.T) {
+		t.Helper()
+
+		assert.Equal(t, []string{"fixture:26", "fixture_events:26"}, cache.FixtureKeys(26))
+	})
+}
+
+// unmarshal mimics a Store holding v by round tripping v through JSON into the destination argument
+func unmarshal(t *testing.T, v interface{}) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		b, err := json.Marshal(v)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		if err := json.Unmarshal(b, args.Get(2)); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+	}
+}
+
+// fixtureID matches the fixture passed to a StatusChecker by ID
+func count(t *testing.T, conn *sql.DB) int {
+	var count int
+
+	if err := conn.QueryRow("select count(*) from data_cache").Scan(&count); err != nil {
+		t.Fatalf("Error when scanning rows returned by the database: %s", err.Error())
+	}
+
+	return count
+}
+
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) Get(ctx context.Context, key string, v interface{}) (bool, error) {
+	args := m.Called(ctx, key, v)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStore) Set(ctx context.Context, key string, v interface{}, ttl time.Duration) error {
+	args := m.Called(ctx, key, v, ttl)
+	return args.Error(0)
+}
+
+func (m *MockStore) Delete(ctx context.Context, keys ...string) error {
+	args := m.Called(ctx, keys)
+	return args.Error(0)
+}
+
+func (m *MockStore) Flush(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+type MockEventClient struct {
+	mock.Mock
+}
+
+func (m *MockEventClient) FixtureEvents(ctx context.Context, fixtureID uint64) (*statistico.FixtureEventsResponse, error) {
+	args := m.Called(ctx, fixtureID)
+	return args.Get(0).(*statistico.FixtureEventsResponse), args.Error(1)
+}
+
+type MockFixtureClient struct {
+	mock.Mock
+}
+
+func (m *MockFixtureClient) Search(ctx context.Context, req *statistico.FixtureSearchRequest) ([]*statistico.Fixture, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).([]*statistico.Fixture), args.Error(1)
+}
+
+func (m *MockFixtureClient) ByID(ctx context.Context, fixtureID uint64) (*statistico.Fixture, error) {
+	args := m.Called(ctx, fixtureID)
+	return args.Get(0).(*statistico.Fixture), args.Error(1)
+}
+
+type MockSeasonClient struct {
+	mock.Mock
+}
+
+func (m *MockSeasonClient) ByTeamID(ctx context.Context, teamId uint64, sort string) ([]*statistico.Season, error) {
+	args := m.Called(ctx, teamId, sort)
+	return args.Get(0).([]*statistico.Season), args.Error(1)
+}
+
+func (m *MockSeasonClient) ByCompetitionID(ctx context.Context, competitionId uint64, sort string) ([]*statistico.Season, error) {
+	args := m.Called(ctx, competitionId, sort)
+	return args.Get(0).([]*statistico.Season), args.Error(1)
+}
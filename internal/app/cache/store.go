@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jonboulle/clockwork"
+	"time"
+)
+
+// Store persists values returned by the Statistico data service so they can be reused across runs.
+type Store interface {
+	// Get unmarshals the value stored against key into v. The bool returned is false if the key does not exist or
+	// the value has expired.
+	Get(ctx context.Context, key string, v interface{}) (bool, error)
+	// Set stores v against key, replacing any existing value. Values set with a ttl greater than zero expire once
+	// ttl has elapsed, values set with a zero ttl never expire.
+	Set(ctx context.Context, key string, v interface{}, ttl time.Duration) error
+	// Delete removes the values stored against each key provided.
+	Delete(ctx context.Context, keys ...string) error
+	// Flush removes every value held in the Store.
+	Flush(ctx context.Context) error
+}
+
+type postgresStore struct {
+	connection *sql.DB
+	clock      clockwork.Clock
+}
+
+func (p *postgresStore) Get(ctx context.Context, key string, v interface{}) (bool, error) {
+	var value []byte
+
+	err := queryBuilder(p.connection).
+		Select("value").
+		From("data_cache").
+		Where(sq.Eq{"key": key}).
+		Where(sq.Or{sq.Eq{"expires_at": nil}, sq.Gt{"expires_at": p.clock.Now().Unix()}}).
+		QueryRowContext(ctx).
+		Scan(&value)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if err := json.Unmarshal(value, v); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (p *postgresStore) Set(ctx context.Context, key string, v interface{}, ttl time.Duration) error {
+	value, err := json.Marshal(v)
+
+	if err != nil {
+		return err
+	}
+
+	now := p.clock.Now()
+
+	var expires *int64
+
+	if ttl > 0 {
+		e := now.Add(ttl).Unix()
+		expires = &e
+	}
+
+	_, err = queryBuilder(p.connection).
+		Insert("data_cache").
+		Columns("key", "value", "timestamp", "expires_at").
+		Values(key, value, now.Unix(), expires).
+		Suffix("ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, timestamp = EXCLUDED.timestamp, " +
+			"expires_at = EXCLUDED.expires_at").
+		ExecContext(ctx)
+
+	return err
+}
+
+func (p *postgresStore) Delete(ctx context.Context, keys ...string) error {
+	_, err := queryBuilder(p.connection).
+		Delete("data_cache").
+		Where(sq.Eq{"key": keys}).
+		ExecContext(ctx)
+
+	return err
+}
+
+func (p *postgresStore) Flush(ctx context.Context) error {
+	_, err := queryBuilder(p.connection).Delete("data_cache").ExecContext(ctx)
+	return err
+}
+
+func queryBuilder(c *sql.DB) sq.StatementBuilderType {
+	return sq.StatementBuilder.PlaceholderFormat(sq.Dollar).RunWith(c)
+}
+
+func NewPostgresStore(c *sql.DB, cl clockwork.Clock) Store {
+	return &postgresStore{connection: c, clock: cl}
+}
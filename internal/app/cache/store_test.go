@@ -0,0 +1,146 @@
+package cache_test
+
+import (
+	"context"
+	"github.com/jonboulle/clockwork"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app/cache"
+	"github.com/statistico/statistico-ratings/internal/app/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPostgresStore(t *testing.T) {
+	conn, cleanUp := test.GetConnection(t, []string{"data_cache"})
+	clock := clockwork.NewFakeClock()
+	store := cache.NewPostgresStore(conn, clock)
+	ctx := context.Background()
+
+	t.Run("sets and gets a value", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		f := statistico.Fixture{Id: 26, HomeTeam: &statistico.Team{Id: 1, Name: "West Ham United"}}
+
+		if err := store.Set(ctx, "fixture:26", &f, 0); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		var fetched statistico.Fixture
+
+		ok, err := store.Get(ctx, "fixture:26", &fetched)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.True(t, ok)
+		assert.Equal(t, int64(26), fetched.Id)
+		assert.Equal(t, "West Ham United", fetched.HomeTeam.Name)
+	})
+
+	t.Run("overwrites an existing value", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		if err := store.Set(ctx, "fixture:26", &statistico.Fixture{Id: 26}, 0); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		if err := store.Set(ctx, "fixture:26", &statistico.Fixture{Id: 27}, 0); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		var fetched statistico.Fixture
+
+		ok, err := store.Get(ctx, "fixture:26", &fetched)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.True(t, ok)
+		assert.Equal(t, int64(27), fetched.Id)
+	})
+
+	t.Run("returns false if key does not exist", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		var fetched statistico.Fixture
+
+		ok, err := store.Get(ctx, "fixture:26", &fetched)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.False(t, ok)
+	})
+
+	t.Run("returns false once a value set with a ttl has expired", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		if err := store.Set(ctx, "competition_seasons:8:name_desc", []*statistico.Season{{Id: 17462}}, time.Hour); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		var fetched []*statistico.Season
+
+		ok, err := store.Get(ctx, "competition_seasons:8:name_desc", &fetched)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.True(t, ok)
+
+		clock.Advance(time.Hour)
+
+		ok, err = store.Get(ctx, "competition_seasons:8:name_desc", &fetched)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.False(t, ok)
+	})
+
+	t.Run("deletes values by key and flushes all values", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		for _, key := range []string{"fixture:1", "fixture:2", "fixture:3"} {
+			if err := store.Set(ctx, key, &statistico.Fixture{}, 0); err != nil {
+				t.Fatalf("Expected nil, got %s", err.Error())
+			}
+		}
+
+		if err := store.Delete(ctx, "fixture:1", "fixture:2"); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, 1, count(t, conn))
+
+		if err := store.Flush(ctx); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, 0, count(t, conn))
+	})
+	t.Run("returns the context error if the query is cancelled", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var fetched statistico.Fixture
+
+		_, err := store.Get(ctx, "fixture:26", &fetched)
+
+		assert.Equal(t, context.Canceled, err)
+	})
+}
@@ -5,6 +5,10 @@ import (
 	"math"
 )
 
+// RuleVersion identifies the rules applied by PointsValue and AdjustedGoals and is persisted alongside each
+// rating calculated. It must be incremented whenever the rules change.
+const RuleVersion = 1
+
 func PointsValue(attack, defence float64, k, goals float64) float64 {
 	if goals == 0 {
 		val := math.Abs(defence - attack)
@@ -52,6 +56,25 @@ func AdjustedGoals(homeID, awayID uint64, goals []*statistico.GoalEvent, cards [
 	return float64(int(homeAdj*100)) / 100, float64(int(awayAdj*100)) / 100
 }
 
+// Goals returns the number of goals scored by each team. The two uint32 values returned are the home goals as the
+// first value and away goals as the second value.
+func Goals(homeID, awayID uint64, goals []*statistico.GoalEvent) (uint32, uint32) {
+	var home uint32
+	var away uint32
+
+	for _, goal := range goals {
+		if goal.TeamId == homeID {
+			home++
+		}
+
+		if goal.TeamId == awayID {
+			away++
+		}
+	}
+
+	return home, away
+}
+
 func calculateGoalValue(diff float64, min, clock uint32, teamRed, oppRed bool) float64 {
 	g := 1.0
 
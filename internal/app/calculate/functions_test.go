@@ -252,3 +252,29 @@ func TestAdjustedGoals(t *testing.T) {
 		}
 	})
 }
+
+func TestGoals(t *testing.T) {
+	t.Run("returns values for home and away goals", func(t *testing.T) {
+		t.Helper()
+
+		goals := []*statistico.GoalEvent{
+			{
+				TeamId: 1,
+				Minute: 25,
+			},
+			{
+				TeamId: 2,
+				Minute: 42,
+			},
+			{
+				TeamId: 1,
+				Minute: 85,
+			},
+		}
+
+		home, away := calculate.Goals(1, 2, goals)
+
+		assert.Equal(t, uint32(2), home)
+		assert.Equal(t, uint32(1), away)
+	})
+}
@@ -0,0 +1,251 @@
+package certificate
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// Files are the paths of the PEM encoded files a Store loads. Cert and Key are the certificate key pair presented to
+// peers and CA is the bundle of certificate authorities peer certificates are verified against. Each is optional.
+type Files struct {
+	Cert string
+	Key  string
+	CA   string
+}
+
+// Store holds the certificate key pair and certificate authority pool loaded from Files, reloading them when the
+// files change so certificates can be rotated without restarting. TLS configs returned by Store read the current
+// certificates on each handshake.
+type Store struct {
+	files  Files
+	clock  clockwork.Clock
+	logger *logrus.Logger
+	mu     sync.RWMutex
+	cert   *tls.Certificate
+	pool   *x509.CertPool
+	loaded string
+}
+
+// Reload loads the certificate files if the modification time or size of any has changed since they were last
+// loaded. The certificates already loaded are kept if loading fails.
+func (s *Store) Reload() error {
+	version, err := s.version()
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	current := version == s.loaded
+	s.mu.RUnlock()
+
+	if current {
+		return nil
+	}
+
+	cert, pool, err := s.load()
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.cert, s.pool, s.loaded = cert, pool, version
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Watch reloads the certificate files every interval until ctx is cancelled, logging reload errors.
+func (s *Store) Watch(ctx context.Context, interval time.Duration) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(interval):
+		}
+
+		if err := s.Reload(); err != nil {
+			s.logger.Errorf("Error reloading tls certificates: %s", err.Error())
+		}
+	}
+}
+
+// ServerConfig returns a tls.Config presenting the loaded certificate to clients. Clients must present a
+// certificate signed by the loaded certificate authorities if Files.CA is set.
+func (s *Store) ServerConfig() *tls.Config {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			s.mu.RLock()
+			defer s.mu.RUnlock()
+
+			if s.cert == nil {
+				return nil, errors.New("no server certificate is loaded")
+			}
+
+			return s.cert, nil
+		},
+	}
+
+	if s.files.CA == "" {
+		return config
+	}
+
+	// ClientCAs is fixed once a listener is configured, so client certificates are verified in VerifyConnection
+	// against the current pool to pick up a reloaded CA bundle.
+	config.ClientAuth = tls.RequireAnyClientCert
+	config.VerifyConnection = func(cs tls.ConnectionState) error {
+		return s.verify(cs.PeerCertificates, "", x509.ExtKeyUsageClientAuth)
+	}
+
+	return config
+}
+
+// ClientConfig returns a tls.Config verifying the server certificate is valid for serverName, presenting the
+// loaded certificate to the server if Files.Cert is set. The server certificate is verified against the loaded
+// certificate authorities if Files.CA is set, otherwise against the system roots.
+func (s *Store) ClientConfig(serverName string) *tls.Config {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			s.mu.RLock()
+			defer s.mu.RUnlock()
+
+			if s.cert == nil {
+				return &tls.Certificate{}, nil
+			}
+
+			return s.cert, nil
+		},
+	}
+
+	if s.files.CA == "" {
+		return config
+	}
+
+	// RootCAs is fixed once a connection is configured, so the server certificate is verified in VerifyConnection
+	// against the current pool to pick up a reloaded CA bundle. Skipping the default verification is safe as verify
+	// performs the same chain and host name checks.
+	config.InsecureSkipVerify = true
+	config.VerifyConnection = func(cs tls.ConnectionState) error {
+		return s.verify(cs.PeerCertificates, cs.ServerName, x509.ExtKeyUsageServerAuth)
+	}
+
+	return config
+}
+
+// verify verifies the peer certificate chain against the loaded certificate authorities, and that the leaf
+// certificate is valid for name if not empty.
+func (s *Store) verify(certs []*x509.Certificate, name string, usage x509.ExtKeyUsage) error {
+	if len(certs) == 0 {
+		return errors.New("peer did not present a certificate")
+	}
+
+	s.mu.RLock()
+	pool := s.pool
+	s.mu.RUnlock()
+
+	opts := x509.VerifyOptions{
+		DNSName:       name,
+		Roots:         pool,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	}
+
+	for _, c := range certs[1:] {
+		opts.Intermediates.AddCert(c)
+	}
+
+	_, err := certs[0].Verify(opts)
+
+	return err
+}
+
+func (s *Store) load() (*tls.Certificate, *x509.CertPool, error) {
+	var cert *tls.Certificate
+	var pool *x509.CertPool
+
+	if s.files.Cert != "" {
+		pair, err := tls.LoadX509KeyPair(s.files.Cert, s.files.Key)
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("error loading certificate key pair: %s", err.Error())
+		}
+
+		cert = &pair
+	}
+
+	if s.files.CA != "" {
+		pem, err := ioutil.ReadFile(s.files.CA)
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading certificate authorities: %s", err.Error())
+		}
+
+		pool = x509.NewCertPool()
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, nil, fmt.Errorf("no certificates found in %s", s.files.CA)
+		}
+	}
+
+	return cert, pool, nil
+}
+
+// version returns the modification time and size of each certificate file, used to detect changes to the files.
+func (s *Store) version() (string, error) {
+	var version string
+
+	for _, path := range []string{s.files.Cert, s.files.Key, s.files.CA} {
+		if path == "" {
+			continue
+		}
+
+		info, err := os.Stat(path)
+
+		if err != nil {
+			return "", err
+		}
+
+		version += fmt.Sprintf("%d:%d;", info.ModTime().UnixNano(), info.Size())
+	}
+
+	return version, nil
+}
+
+// NewStore returns a Store with the certificate files loaded, returning an error if they cannot be loaded. Key is
+// required if Cert is set.
+func NewStore(f Files, c clockwork.Clock, l *logrus.Logger) (*Store, error) {
+	if f.Cert != "" && f.Key == "" {
+		return nil, errors.New("a key is required for the certificate")
+	}
+
+	s := &Store{files: f, clock: c, logger: l}
+
+	version, err := s.version()
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.cert, s.pool, err = s.load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.loaded = version
+
+	return s, nil
+}
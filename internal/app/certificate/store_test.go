@@ -0,0 +1,497 @@
+package certificate_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/statistico/statistico-ratings/internal/app/certificate"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewStore(t *testing.T) {
+	t.Run("returns an error if a certificate is provided without a key", func(t *testing.T) {
+		t.Helper()
+
+		dir := t.TempDir()
+		ca := newAuthority(t)
+		files := ca.issue(t, dir, "server", "localhost")
+		files.Key = ""
+
+		_, err := certificate.NewStore(files, clockwork.NewFakeClock(), nil)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "a key is required for the certificate", err.Error())
+	})
+
+	t.Run("returns an error if the certificate authority file contains no certificates", func(t *testing.T) {
+		t.Helper()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "ca.pem")
+
+		write(t, path, []byte("not a certificate"))
+
+		_, err := certificate.NewStore(certificate.Files{CA: path}, clockwork.NewFakeClock(), nil)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "no certificates found in "+path, err.Error())
+	})
+
+	t.Run("returns an error if a file does not exist", func(t *testing.T) {
+		t.Helper()
+
+		_, err := certificate.NewStore(certificate.Files{CA: filepath.Join(t.TempDir(), "ca.pem")}, clockwork.NewFakeClock(), nil)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+}
+
+func TestStore_ServerConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca := newAuthority(t)
+
+	server := newStore(t, ca.issue(t, dir, "server", "localhost"))
+	client := newStore(t, ca.issue(t, dir, "client", ""))
+
+	t.Run("completes a mutual tls handshake with a client presenting a trusted certificate", func(t *testing.T) {
+		t.Helper()
+
+		if err := handshake(server.ServerConfig(), client.ClientConfig("localhost")); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+	})
+
+	t.Run("rejects a client not presenting a certificate", func(t *testing.T) {
+		t.Helper()
+
+		files := ca.issue(t, dir, "anonymous", "")
+		files.Cert, files.Key = "", ""
+
+		if err := handshake(server.ServerConfig(), newStore(t, files).ClientConfig("localhost")); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("rejects a client presenting a certificate from another authority", func(t *testing.T) {
+		t.Helper()
+
+		other := newAuthority(t)
+		files := other.issue(t, dir, "untrusted", "")
+		files.CA = ca.path
+
+		if err := handshake(server.ServerConfig(), newStore(t, files).ClientConfig("localhost")); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("does not require client certificates if no certificate authority is provided", func(t *testing.T) {
+		t.Helper()
+
+		files := ca.issue(t, dir, "tls-only", "localhost")
+		files.CA = ""
+
+		anonymous := ca.issue(t, dir, "anonymous", "")
+		anonymous.Cert, anonymous.Key = "", ""
+
+		if err := handshake(newStore(t, files).ServerConfig(), newStore(t, anonymous).ClientConfig("localhost")); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+	})
+}
+
+func TestStore_ClientConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca := newAuthority(t)
+
+	server := newStore(t, ca.issue(t, dir, "server", "localhost"))
+	client := newStore(t, ca.issue(t, dir, "client", ""))
+
+	t.Run("rejects a server certificate not valid for the server name", func(t *testing.T) {
+		t.Helper()
+
+		if err := handshake(server.ServerConfig(), client.ClientConfig("data.statistico.io")); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("rejects a server certificate from another authority", func(t *testing.T) {
+		t.Helper()
+
+		other := newAuthority(t)
+		files := other.issue(t, dir, "untrusted", "localhost")
+		files.CA = ""
+
+		if err := handshake(newStore(t, files).ServerConfig(), client.ClientConfig("localhost")); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+}
+
+func TestStore_Reload(t *testing.T) {
+	t.Run("loads rotated certificates once the files change", func(t *testing.T) {
+		t.Helper()
+
+		dir := t.TempDir()
+		old := newAuthority(t)
+		rotated := newAuthority(t)
+
+		files := old.issue(t, dir, "server", "localhost")
+		files.CA = ""
+
+		server := newStore(t, files)
+
+		trustsRotated := rotated.issue(t, dir, "client", "")
+		trustsRotated.Cert, trustsRotated.Key = "", ""
+		client := newStore(t, trustsRotated)
+
+		if err := handshake(server.ServerConfig(), client.ClientConfig("localhost")); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		rotated.issue(t, dir, "server", "localhost")
+		touch(t, files)
+
+		if err := server.Reload(); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		if err := handshake(server.ServerConfig(), client.ClientConfig("localhost")); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+	})
+
+	t.Run("keeps the loaded certificates if the changed files are invalid", func(t *testing.T) {
+		t.Helper()
+
+		dir := t.TempDir()
+		ca := newAuthority(t)
+
+		files := ca.issue(t, dir, "server", "localhost")
+		server := newStore(t, files)
+		client := newStore(t, ca.issue(t, dir, "client", ""))
+
+		write(t, files.Cert, []byte("truncated"))
+		touch(t, files)
+
+		if err := server.Reload(); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		if err := handshake(server.ServerConfig(), client.ClientConfig("localhost")); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+	})
+}
+
+func TestStore_Watch(t *testing.T) {
+	t.Run("reloads the certificate files every interval and logs errors", func(t *testing.T) {
+		t.Helper()
+
+		dir := t.TempDir()
+		ca := newAuthority(t)
+		files := ca.issue(t, dir, "server", "localhost")
+		clock := clockwork.NewFakeClock()
+		logger, hook := test.NewNullLogger()
+
+		store, err := certificate.NewStore(files, clock, logger)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+
+		go func() {
+			store.Watch(ctx, time.Minute)
+			close(done)
+		}()
+
+		if err := os.Remove(files.Key); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+		clock.BlockUntil(1)
+
+		cancel()
+		<-done
+
+		assert.Equal(t, 1, len(hook.AllEntries()))
+		assert.Contains(t, hook.LastEntry().Message, "Error reloading tls certificates: ")
+	})
+}
+
+func TestStore_Grpc(t *testing.T) {
+	t.Run("serves grpc requests over mutual tls", func(t *testing.T) {
+		t.Helper()
+
+		dir := t.TempDir()
+		ca := newAuthority(t)
+		server := newStore(t, ca.issue(t, dir, "server", "localhost"))
+		client := newStore(t, ca.issue(t, dir, "client", ""))
+
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		s := grpc.NewServer(grpc.Creds(credentials.NewTLS(server.ServerConfig())))
+		grpc_health_v1.RegisterHealthServer(s, health.NewServer())
+
+		go s.Serve(lis)
+		defer s.Stop()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		conn, err := grpc.DialContext(
+			ctx,
+			lis.Addr().String(),
+			grpc.WithTransportCredentials(credentials.NewTLS(client.ClientConfig("localhost"))),
+			grpc.WithBlock(),
+		)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		defer conn.Close()
+
+		res, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, res.Status)
+	})
+}
+
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+	path string
+}
+
+func newAuthority(t *testing.T) *authority {
+	t.Helper()
+
+	key := newKey(t)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "statistico test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+
+	cert, err := x509.ParseCertificate(der)
+
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	block := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	write(t, path, block)
+
+	return &authority{cert: cert, key: key, pem: block, path: path}
+}
+
+// issue writes a certificate signed by the authority, valid for host if not empty, to dir along with its key and
+// the authority certificate, returning the paths written.
+func (a *authority) issue(t *testing.T, dir, name, host string) certificate.Files {
+	t.Helper()
+
+	key := newKey(t)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	if host != "" {
+		template.DNSNames = []string{host}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, &key.PublicKey, a.key)
+
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+
+	files := certificate.Files{
+		Cert: filepath.Join(dir, name+".pem"),
+		Key:  filepath.Join(dir, name+"-key.pem"),
+		CA:   filepath.Join(dir, name+"-ca.pem"),
+	}
+
+	write(t, files.Cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	write(t, files.Key, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	write(t, files.CA, a.pem)
+
+	return files
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+
+	return key
+}
+
+func newStore(t *testing.T, f certificate.Files) *certificate.Store {
+	t.Helper()
+
+	store, err := certificate.NewStore(f, clockwork.NewFakeClock(), nil)
+
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+
+	return store
+}
+
+func write(t *testing.T, path string, b []byte) {
+	t.Helper()
+
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+}
+
+// touch moves the modification time of the files forward so changes are detected on file systems with coarse
+// timestamps.
+func touch(t *testing.T, f certificate.Files) {
+	t.Helper()
+
+	modified := time.Now().Add(time.Minute)
+
+	for _, path := range []string{f.Cert, f.Key, f.CA} {
+		if path == "" {
+			continue
+		}
+
+		if err := os.Chtimes(path, modified, modified); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+	}
+}
+
+// handshake completes a TLS handshake between the server and client configs over a loopback connection, returning
+// the first error from either side.
+func handshake(server, client *tls.Config) error {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		return err
+	}
+
+	defer lis.Close()
+
+	errs := make(chan error, 2)
+
+	go func() {
+		s, err := lis.Accept()
+
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		conn := tls.Server(s, server)
+		err = conn.Handshake()
+
+		if err == nil {
+			// TLS 1.3 clients complete the handshake before the server verifies the client certificate, so the
+			// server result is read by the client from a round trip.
+			_, err = conn.Write([]byte{1})
+		}
+
+		conn.Close()
+		errs <- err
+	}()
+
+	go func() {
+		c, err := net.Dial("tcp", lis.Addr().String())
+
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		conn := tls.Client(c, client)
+		err = conn.Handshake()
+
+		if err == nil {
+			_, err = conn.Read(make([]byte, 1))
+		}
+
+		conn.Close()
+		errs <- err
+	}()
+
+	first := <-errs
+	second := <-errs
+
+	if first != nil {
+		return first
+	}
+
+	return second
+}
@@ -24,3 +24,50 @@ type NotFoundError struct {
 func (n *NotFoundError) Error() string {
 	return fmt.Sprintf("team %d rating does not exist", n.TeamID)
 }
+
+type JobNotFoundError struct {
+	CompetitionID uint64
+	SeasonID      uint64
+}
+
+func (j *JobNotFoundError) Error() string {
+	return fmt.Sprintf("incomplete job for competition %d and season %d does not exist", j.CompetitionID, j.SeasonID)
+}
+
+type UnfinishedError struct {
+	FixtureID uint64
+	Reason    string
+}
+
+func (u *UnfinishedError) Error() string {
+	return fmt.Sprintf("fixture %d has not finished: %s", u.FixtureID, u.Reason)
+}
+
+// StatusCheckError is returned if the status of a fixture could not be checked, typically as the data service could
+// not be reached.
+type StatusCheckError struct {
+	FixtureID uint64
+	Err       error
+}
+
+func (s *StatusCheckError) Error() string {
+	return s.Err.Error()
+}
+
+// OutOfOrderError is returned if a team already has a rating for a fixture played after the fixture being rated.
+// Ratings are calculated from the team's latest rating, so rating an earlier fixture would apply it on top of
+// ratings it should have preceded.
+type OutOfOrderError struct {
+	TeamID          uint64
+	FixtureID       uint64
+	LatestFixtureID uint64
+}
+
+func (o *OutOfOrderError) Error() string {
+	return fmt.Sprintf(
+		"team %d has a rating for fixture %d played after fixture %d, ratings must be calculated in date order",
+		o.TeamID,
+		o.LatestFixtureID,
+		o.FixtureID,
+	)
+}
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"encoding/csv"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"io"
+	"strconv"
+	"time"
+)
+
+type csvEncoder struct {
+	writer *csv.Writer
+}
+
+// Encode writes r as a CSV row. Dates are formatted as RFC3339 in UTC and calculation inputs are left empty for
+// ratings calculated before the inputs were persisted.
+func (c *csvEncoder) Encode(r *team.Rating) error {
+	row := []string{
+		strconv.FormatUint(r.TeamID, 10),
+		strconv.FormatUint(r.FixtureID, 10),
+		strconv.FormatUint(r.SeasonID, 10),
+		strconv.FormatUint(r.CompetitionID, 10),
+		formatFloat(r.Attack.Total),
+		formatFloat(r.Attack.Difference),
+		formatFloat(r.Defence.Total),
+		formatFloat(r.Defence.Difference),
+		r.FixtureDate.UTC().Format(time.RFC3339),
+		r.Timestamp.UTC().Format(time.RFC3339),
+		"",
+		"",
+		"",
+		"",
+		"",
+		"",
+	}
+
+	if calc := r.Calculation; calc != nil {
+		row[10] = strconv.FormatUint(uint64(calc.Goals), 10)
+		row[11] = formatFloat(calc.AdjustedGoals)
+		row[12] = formatFloat(calc.KFactor)
+		row[13] = formatFloat(calc.OpponentAttack)
+		row[14] = formatFloat(calc.OpponentDefence)
+		row[15] = strconv.FormatUint(uint64(calc.RuleVersion), 10)
+	}
+
+	return c.writer.Write(row)
+}
+
+func (c *csvEncoder) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// NewCSVEncoder returns an Encoder writing ratings to w as CSV, writing the header row immediately.
+func NewCSVEncoder(w io.Writer) (Encoder, error) {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+
+	return &csvEncoder{writer: writer}, nil
+}
@@ -0,0 +1,31 @@
+package export_test
+
+import (
+	"bytes"
+	"github.com/statistico/statistico-ratings/internal/app/export"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCSVEncoder_Encode(t *testing.T) {
+	t.Run("writes header and a row for each rating", func(t *testing.T) {
+		t.Helper()
+
+		var b bytes.Buffer
+
+		enc, err := export.NewCSVEncoder(&b)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		encode(t, enc)
+
+		expected := "team_id,fixture_id,season_id,competition_id,attack_total,attack_points,defence_total,defence_points," +
+			"fixture_date,timestamp,goals,adjusted_goals,k_factor,opponent_attack_total,opponent_defence_total,rule_version\n" +
+			"1,65,17420,8,1245.04,19.37,1240.82,-12.35,2021-07-01T18:00:00Z,2021-07-01T20:00:00Z,3,2.66,5,1518.33,790.72,1\n" +
+			"2,65,17420,8,1500,0,1500,0,2021-07-01T18:00:00Z,2021-07-01T20:00:00Z,,,,,,\n"
+
+		assert.Equal(t, expected, b.String())
+	})
+}
@@ -0,0 +1,71 @@
+package export
+
+import (
+	"fmt"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"io"
+	"path"
+	"strings"
+)
+
+const (
+	CSV     = "csv"
+	JSONL   = "jsonl"
+	Parquet = "parquet"
+)
+
+// columns are the names of the fields exported for each rating, matching the columns of the team_rating table.
+var columns = []string{
+	"team_id",
+	"fixture_id",
+	"season_id",
+	"competition_id",
+	"attack_total",
+	"attack_points",
+	"defence_total",
+	"defence_points",
+	"fixture_date",
+	"timestamp",
+	"goals",
+	"adjusted_goals",
+	"k_factor",
+	"opponent_attack_total",
+	"opponent_defence_total",
+	"rule_version",
+}
+
+// Encoder writes team ratings to an underlying io.Writer in an export format.
+type Encoder interface {
+	Encode(r *team.Rating) error
+	// Close writes any ratings buffered by the Encoder and the end of the export. It does not close the
+	// underlying io.Writer.
+	Close() error
+}
+
+// NewEncoder returns an Encoder writing ratings to w in the format provided, either csv, jsonl or parquet.
+func NewEncoder(format string, w io.Writer) (Encoder, error) {
+	switch format {
+	case CSV:
+		return NewCSVEncoder(w)
+	case JSONL:
+		return NewJSONLEncoder(w), nil
+	case Parquet:
+		return NewParquetEncoder(w)
+	default:
+		return nil, fmt.Errorf("format '%s' is not supported, use csv, jsonl or parquet", format)
+	}
+}
+
+// Format returns the export format matching the extension of filename.
+func Format(filename string) (string, error) {
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(filename), "."))
+
+	switch ext {
+	case CSV, JSONL, Parquet:
+		return ext, nil
+	case "json", "ndjson":
+		return JSONL, nil
+	default:
+		return "", fmt.Errorf("format cannot be inferred from extension of %s, use .csv, .jsonl or .parquet", filename)
+	}
+}
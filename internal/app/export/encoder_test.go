@@ -0,0 +1,115 @@
+package export_test
+
+import (
+	"bytes"
+	"github.com/statistico/statistico-ratings/internal/app/export"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewEncoder(t *testing.T) {
+	t.Run("returns an encoder for each supported format", func(t *testing.T) {
+		t.Helper()
+
+		for _, format := range []string{"csv", "jsonl", "parquet"} {
+			_, err := export.NewEncoder(format, new(bytes.Buffer))
+
+			if err != nil {
+				t.Fatalf("Expected nil, got %s", err.Error())
+			}
+		}
+	})
+
+	t.Run("returns an error if format is not supported", func(t *testing.T) {
+		t.Helper()
+
+		_, err := export.NewEncoder("xlsx", new(bytes.Buffer))
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "format 'xlsx' is not supported, use csv, jsonl or parquet", err.Error())
+	})
+}
+
+func TestFormat(t *testing.T) {
+	t.Run("returns format matching filename extension", func(t *testing.T) {
+		t.Helper()
+
+		for filename, expected := range map[string]string{
+			"ratings.csv":                        "csv",
+			"/tmp/ratings.CSV":                   "csv",
+			"s3://statistico/ratings.jsonl":      "jsonl",
+			"file:///tmp/ratings.ndjson":         "jsonl",
+			"exports/2021-09-01/ratings.parquet": "parquet",
+		} {
+			format, err := export.Format(filename)
+
+			if err != nil {
+				t.Fatalf("Expected nil, got %s", err.Error())
+			}
+
+			assert.Equal(t, expected, format)
+		}
+	})
+
+	t.Run("returns an error if extension is not supported", func(t *testing.T) {
+		t.Helper()
+
+		_, err := export.Format("ratings.txt")
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "format cannot be inferred from extension of ratings.txt, use .csv, .jsonl or .parquet", err.Error())
+	})
+}
+
+func newRatings() []*team.Rating {
+	return []*team.Rating{
+		{
+			TeamID:        1,
+			FixtureID:     65,
+			SeasonID:      17420,
+			CompetitionID: 8,
+			Attack:        team.Points{Total: 1245.04, Difference: 19.37},
+			Defence:       team.Points{Total: 1240.82, Difference: -12.35},
+			FixtureDate:   time.Unix(1625162400, 0),
+			Timestamp:     time.Unix(1625169600, 0),
+			Calculation: &team.Calculation{
+				Goals:           3,
+				AdjustedGoals:   2.66,
+				KFactor:         5,
+				OpponentAttack:  1518.33,
+				OpponentDefence: 790.72,
+				RuleVersion:     1,
+			},
+		},
+		{
+			TeamID:        2,
+			FixtureID:     65,
+			SeasonID:      17420,
+			CompetitionID: 8,
+			Attack:        team.Points{Total: 1500, Difference: 0},
+			Defence:       team.Points{Total: 1500, Difference: 0},
+			FixtureDate:   time.Unix(1625162400, 0),
+			Timestamp:     time.Unix(1625169600, 0),
+		},
+	}
+}
+
+func encode(t *testing.T, e export.Encoder) {
+	for _, r := range newRatings() {
+		if err := e.Encode(r); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+	}
+
+	if err := e.Close(); err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+}
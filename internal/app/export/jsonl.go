@@ -0,0 +1,68 @@
+package export
+
+import (
+	"encoding/json"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"io"
+	"time"
+)
+
+type jsonRating struct {
+	TeamID               uint64    `json:"team_id"`
+	FixtureID            uint64    `json:"fixture_id"`
+	SeasonID             uint64    `json:"season_id"`
+	CompetitionID        uint64    `json:"competition_id"`
+	AttackTotal          float64   `json:"attack_total"`
+	AttackPoints         float64   `json:"attack_points"`
+	DefenceTotal         float64   `json:"defence_total"`
+	DefencePoints        float64   `json:"defence_points"`
+	FixtureDate          time.Time `json:"fixture_date"`
+	Timestamp            time.Time `json:"timestamp"`
+	Goals                *uint32   `json:"goals"`
+	AdjustedGoals        *float64  `json:"adjusted_goals"`
+	KFactor              *float64  `json:"k_factor"`
+	OpponentAttackTotal  *float64  `json:"opponent_attack_total"`
+	OpponentDefenceTotal *float64  `json:"opponent_defence_total"`
+	RuleVersion          *uint32   `json:"rule_version"`
+}
+
+type jsonlEncoder struct {
+	encoder *json.Encoder
+}
+
+// Encode writes r as a JSON object on a single line. Dates are formatted as RFC3339 in UTC and calculation inputs
+// are null for ratings calculated before the inputs were persisted.
+func (j *jsonlEncoder) Encode(r *team.Rating) error {
+	rating := jsonRating{
+		TeamID:        r.TeamID,
+		FixtureID:     r.FixtureID,
+		SeasonID:      r.SeasonID,
+		CompetitionID: r.CompetitionID,
+		AttackTotal:   r.Attack.Total,
+		AttackPoints:  r.Attack.Difference,
+		DefenceTotal:  r.Defence.Total,
+		DefencePoints: r.Defence.Difference,
+		FixtureDate:   r.FixtureDate.UTC(),
+		Timestamp:     r.Timestamp.UTC(),
+	}
+
+	if calc := r.Calculation; calc != nil {
+		rating.Goals = &calc.Goals
+		rating.AdjustedGoals = &calc.AdjustedGoals
+		rating.KFactor = &calc.KFactor
+		rating.OpponentAttackTotal = &calc.OpponentAttack
+		rating.OpponentDefenceTotal = &calc.OpponentDefence
+		rating.RuleVersion = &calc.RuleVersion
+	}
+
+	return j.encoder.Encode(rating)
+}
+
+func (j *jsonlEncoder) Close() error {
+	return nil
+}
+
+// NewJSONLEncoder returns an Encoder writing ratings to w as JSON Lines.
+func NewJSONLEncoder(w io.Writer) Encoder {
+	return &jsonlEncoder{encoder: json.NewEncoder(w)}
+}
@@ -0,0 +1,29 @@
+package export_test
+
+import (
+	"bytes"
+	"github.com/statistico/statistico-ratings/internal/app/export"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestJSONLEncoder_Encode(t *testing.T) {
+	t.Run("writes a json object per line for each rating", func(t *testing.T) {
+		t.Helper()
+
+		var b bytes.Buffer
+
+		encode(t, export.NewJSONLEncoder(&b))
+
+		expected := `{"team_id":1,"fixture_id":65,"season_id":17420,"competition_id":8,"attack_total":1245.04,` +
+			`"attack_points":19.37,"defence_total":1240.82,"defence_points":-12.35,"fixture_date":"2021-07-01T18:00:00Z",` +
+			`"timestamp":"2021-07-01T20:00:00Z","goals":3,"adjusted_goals":2.66,"k_factor":5,` +
+			`"opponent_attack_total":1518.33,"opponent_defence_total":790.72,"rule_version":1}` + "\n" +
+			`{"team_id":2,"fixture_id":65,"season_id":17420,"competition_id":8,"attack_total":1500,` +
+			`"attack_points":0,"defence_total":1500,"defence_points":0,"fixture_date":"2021-07-01T18:00:00Z",` +
+			`"timestamp":"2021-07-01T20:00:00Z","goals":null,"adjusted_goals":null,"k_factor":null,` +
+			`"opponent_attack_total":null,"opponent_defence_total":null,"rule_version":null}` + "\n"
+
+		assert.Equal(t, expected, b.String())
+	})
+}
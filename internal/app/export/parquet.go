@@ -0,0 +1,81 @@
+package export
+
+import (
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"github.com/xitongsys/parquet-go/writer"
+	"io"
+)
+
+type parquetRating struct {
+	TeamID               int64    `parquet:"name=team_id, type=INT64"`
+	FixtureID            int64    `parquet:"name=fixture_id, type=INT64"`
+	SeasonID             int64    `parquet:"name=season_id, type=INT64"`
+	CompetitionID        int64    `parquet:"name=competition_id, type=INT64"`
+	AttackTotal          float64  `parquet:"name=attack_total, type=DOUBLE"`
+	AttackPoints         float64  `parquet:"name=attack_points, type=DOUBLE"`
+	DefenceTotal         float64  `parquet:"name=defence_total, type=DOUBLE"`
+	DefencePoints        float64  `parquet:"name=defence_points, type=DOUBLE"`
+	FixtureDate          int64    `parquet:"name=fixture_date, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Timestamp            int64    `parquet:"name=timestamp, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Goals                *int32   `parquet:"name=goals, type=INT32, repetitiontype=OPTIONAL"`
+	AdjustedGoals        *float64 `parquet:"name=adjusted_goals, type=DOUBLE, repetitiontype=OPTIONAL"`
+	KFactor              *float64 `parquet:"name=k_factor, type=DOUBLE, repetitiontype=OPTIONAL"`
+	OpponentAttackTotal  *float64 `parquet:"name=opponent_attack_total, type=DOUBLE, repetitiontype=OPTIONAL"`
+	OpponentDefenceTotal *float64 `parquet:"name=opponent_defence_total, type=DOUBLE, repetitiontype=OPTIONAL"`
+	RuleVersion          *int32   `parquet:"name=rule_version, type=INT32, repetitiontype=OPTIONAL"`
+}
+
+type parquetEncoder struct {
+	writer *writer.ParquetWriter
+}
+
+// Encode buffers r as a Parquet row, writing row groups to the underlying io.Writer as they fill. Dates are
+// stored as millisecond timestamps and calculation inputs are null for ratings calculated before the inputs were
+// persisted.
+func (p *parquetEncoder) Encode(r *team.Rating) error {
+	rating := parquetRating{
+		TeamID:        int64(r.TeamID),
+		FixtureID:     int64(r.FixtureID),
+		SeasonID:      int64(r.SeasonID),
+		CompetitionID: int64(r.CompetitionID),
+		AttackTotal:   r.Attack.Total,
+		AttackPoints:  r.Attack.Difference,
+		DefenceTotal:  r.Defence.Total,
+		DefencePoints: r.Defence.Difference,
+		FixtureDate:   millis(r.FixtureDate.UnixNano()),
+		Timestamp:     millis(r.Timestamp.UnixNano()),
+	}
+
+	if calc := r.Calculation; calc != nil {
+		goals := int32(calc.Goals)
+		version := int32(calc.RuleVersion)
+
+		rating.Goals = &goals
+		rating.AdjustedGoals = &calc.AdjustedGoals
+		rating.KFactor = &calc.KFactor
+		rating.OpponentAttackTotal = &calc.OpponentAttack
+		rating.OpponentDefenceTotal = &calc.OpponentDefence
+		rating.RuleVersion = &version
+	}
+
+	return p.writer.Write(rating)
+}
+
+func (p *parquetEncoder) Close() error {
+	return p.writer.WriteStop()
+}
+
+func millis(nanos int64) int64 {
+	return nanos / 1e6
+}
+
+// NewParquetEncoder returns an Encoder writing ratings to w as a Snappy compressed Parquet file.
+func NewParquetEncoder(w io.Writer) (Encoder, error) {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(parquetRating), 1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &parquetEncoder{writer: pw}, nil
+}
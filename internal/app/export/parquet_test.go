@@ -0,0 +1,70 @@
+package export_test
+
+import (
+	"bytes"
+	"github.com/statistico/statistico-ratings/internal/app/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+	"testing"
+)
+
+type parquetRow struct {
+	TeamID        int64    `parquet:"name=team_id, type=INT64"`
+	FixtureID     int64    `parquet:"name=fixture_id, type=INT64"`
+	CompetitionID int64    `parquet:"name=competition_id, type=INT64"`
+	DefencePoints float64  `parquet:"name=defence_points, type=DOUBLE"`
+	FixtureDate   int64    `parquet:"name=fixture_date, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Goals         *int32   `parquet:"name=goals, type=INT32, repetitiontype=OPTIONAL"`
+	KFactor       *float64 `parquet:"name=k_factor, type=DOUBLE, repetitiontype=OPTIONAL"`
+}
+
+func TestParquetEncoder_Encode(t *testing.T) {
+	t.Run("writes a parquet file with a row for each rating", func(t *testing.T) {
+		t.Helper()
+
+		var b bytes.Buffer
+
+		enc, err := export.NewParquetEncoder(&b)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		encode(t, enc)
+
+		file, err := buffer.NewBufferFile(b.Bytes())
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		pr, err := reader.NewParquetReader(file, new(parquetRow), 1)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		defer pr.ReadStop()
+
+		rows := make([]parquetRow, pr.GetNumRows())
+
+		if err := pr.Read(&rows); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		a := assert.New(t)
+
+		a.Equal(2, len(rows))
+		a.Equal(int64(1), rows[0].TeamID)
+		a.Equal(int64(65), rows[0].FixtureID)
+		a.Equal(int64(8), rows[0].CompetitionID)
+		a.Equal(-12.35, rows[0].DefencePoints)
+		a.Equal(int64(1625162400000), rows[0].FixtureDate)
+		a.Equal(int32(3), *rows[0].Goals)
+		a.Equal(5.0, *rows[0].KFactor)
+		a.Equal(int64(2), rows[1].TeamID)
+		a.Nil(rows[1].Goals)
+		a.Nil(rows[1].KFactor)
+	})
+}
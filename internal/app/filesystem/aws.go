@@ -1,25 +1,50 @@
 package filesystem
 
 import (
+	"errors"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
 	"io"
+	"net/url"
+	"strings"
+	"sync"
 )
 
 type Reader interface {
 	Reader(filename string) (io.ReadCloser, error)
 }
 
+// File is a file being written by a Writer. The content written is only stored once Close returns without error,
+// so a failed write does not leave a partial file behind. Abort discards the content written.
+type File interface {
+	io.WriteCloser
+	Abort() error
+}
+
+type Writer interface {
+	Writer(filename string) (File, error)
+}
+
 type s3Reader struct {
-	client  s3iface.S3API
-	bucket  string
+	client s3iface.S3API
+	bucket string
 }
 
+// Reader returns the contents of the object stored against filename. Filename is either a key within the default
+// bucket or an s3://bucket/key URI.
 func (s *s3Reader) Reader(filename string) (io.ReadCloser, error) {
+	bucket, key, err := s3Location(filename, s.bucket)
+
+	if err != nil {
+		return nil, err
+	}
+
 	input := s3.GetObjectInput{
-		Bucket: aws.String(s.bucket),
-		Key:    aws.String(filename),
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
 	}
 
 	object, err := s.client.GetObject(&input)
@@ -31,9 +56,102 @@ func (s *s3Reader) Reader(filename string) (io.ReadCloser, error) {
 	return object.Body, nil
 }
 
+type s3Writer struct {
+	uploader s3manageriface.UploaderAPI
+	bucket   string
+}
+
+type s3File struct {
+	writer *io.PipeWriter
+	done   chan error
+	once   sync.Once
+	err    error
+}
+
+// errAborted is returned to the uploader reading a File when the File is aborted, failing the upload.
+var errAborted = errors.New("upload aborted")
+
+// Writer returns a File streamed to the object stored against filename as it is written, so large files are not
+// held in memory. Files larger than the part size of the uploader are uploaded in parts using a multipart upload,
+// which is completed on Close and aborted if writing fails or the File is aborted. Filename is either a key within
+// the default bucket or an s3://bucket/key URI.
+func (s *s3Writer) Writer(filename string) (File, error) {
+	bucket, key, err := s3Location(filename, s.bucket)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r, w := io.Pipe()
+
+	file := s3File{writer: w, done: make(chan error, 1)}
+
+	go func() {
+		input := s3manager.UploadInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+			Body:   r,
+		}
+
+		_, err := s.uploader.Upload(&input)
+
+		// Fail writes still to be made if the upload has failed so the caller is not blocked.
+		r.CloseWithError(err)
+
+		file.done <- err
+	}()
+
+	return &file, nil
+}
+
+func (s *s3File) Write(p []byte) (int, error) {
+	return s.writer.Write(p)
+}
+
+// Close completes the upload, returning once the object is stored or the upload has failed.
+func (s *s3File) Close() error {
+	s.writer.Close()
+	return s.wait()
+}
+
+func (s *s3File) Abort() error {
+	s.writer.CloseWithError(errAborted)
+	s.wait()
+	return nil
+}
+
+func (s *s3File) wait() error {
+	s.once.Do(func() {
+		s.err = <-s.done
+	})
+
+	return s.err
+}
+
+func s3Location(filename, bucket string) (string, string, error) {
+	if !strings.HasPrefix(filename, "s3://") {
+		return bucket, filename, nil
+	}
+
+	u, err := url.Parse(filename)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return u.Host, strings.TrimPrefix(u.Path, "/"), nil
+}
+
 func NewS3Reader(c s3iface.S3API, b string) Reader {
 	return &s3Reader{
 		client: c,
 		bucket: b,
 	}
 }
+
+func NewS3Writer(u s3manageriface.UploaderAPI, b string) Writer {
+	return &s3Writer{
+		uploader: u,
+		bucket:   b,
+	}
+}
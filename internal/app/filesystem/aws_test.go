@@ -0,0 +1,339 @@
+package filesystem_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+	"github.com/statistico/statistico-ratings/internal/app/filesystem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestS3Reader_Reader(t *testing.T) {
+	t.Run("reads key from default bucket", func(t *testing.T) {
+		t.Helper()
+
+		client := new(MockS3Client)
+		reader := filesystem.NewS3Reader(client, "statistico")
+
+		input := mock.MatchedBy(func(i *s3.GetObjectInput) bool {
+			return *i.Bucket == "statistico" && *i.Key == "ratings/seasons.csv"
+		})
+
+		client.On("GetObject", input).Return(&s3.GetObjectOutput{Body: ioutil.NopCloser(strings.NewReader("8,17420"))}, nil)
+
+		file, err := reader.Reader("ratings/seasons.csv")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "8,17420", readAll(t, file))
+		client.AssertExpectations(t)
+	})
+
+	t.Run("reads bucket and key from s3 uri", func(t *testing.T) {
+		t.Helper()
+
+		client := new(MockS3Client)
+		reader := filesystem.NewS3Reader(client, "statistico")
+
+		input := mock.MatchedBy(func(i *s3.GetObjectInput) bool {
+			return *i.Bucket == "other-bucket" && *i.Key == "ratings/seasons.csv"
+		})
+
+		client.On("GetObject", input).Return(&s3.GetObjectOutput{Body: ioutil.NopCloser(strings.NewReader("8,17420"))}, nil)
+
+		file, err := reader.Reader("s3://other-bucket/ratings/seasons.csv")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "8,17420", readAll(t, file))
+		client.AssertExpectations(t)
+	})
+}
+
+func TestS3Writer_Writer(t *testing.T) {
+	t.Run("uploads content written to key in default bucket", func(t *testing.T) {
+		t.Helper()
+
+		uploader := new(MockUploader)
+		writer := filesystem.NewS3Writer(uploader, "statistico")
+
+		var body string
+
+		input := mock.MatchedBy(func(i *s3manager.UploadInput) bool {
+			return *i.Bucket == "statistico" && *i.Key == "exports/ratings.csv"
+		})
+
+		uploader.On("Upload", input).Run(func(args mock.Arguments) {
+			b, _ := ioutil.ReadAll(args.Get(0).(*s3manager.UploadInput).Body)
+			body = string(b)
+		}).Return(&s3manager.UploadOutput{}, nil)
+
+		file, err := writer.Writer("exports/ratings.csv")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		file.Write([]byte("1,65\n"))
+		file.Write([]byte("2,65\n"))
+
+		if err := file.Close(); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "1,65\n2,65\n", body)
+		uploader.AssertExpectations(t)
+	})
+
+	t.Run("uploads to bucket and key from s3 uri", func(t *testing.T) {
+		t.Helper()
+
+		uploader := new(MockUploader)
+		writer := filesystem.NewS3Writer(uploader, "statistico")
+
+		input := mock.MatchedBy(func(i *s3manager.UploadInput) bool {
+			return *i.Bucket == "other-bucket" && *i.Key == "exports/ratings.csv"
+		})
+
+		uploader.On("Upload", input).Run(func(args mock.Arguments) {
+			ioutil.ReadAll(args.Get(0).(*s3manager.UploadInput).Body)
+		}).Return(&s3manager.UploadOutput{}, nil)
+
+		file, err := writer.Writer("s3://other-bucket/exports/ratings.csv")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		if err := file.Close(); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		uploader.AssertExpectations(t)
+	})
+
+	t.Run("returns upload error from write and close", func(t *testing.T) {
+		t.Helper()
+
+		uploader := new(MockUploader)
+		writer := filesystem.NewS3Writer(uploader, "statistico")
+
+		uploader.On("Upload", mock.Anything).Return(&s3manager.UploadOutput{}, errors.New("access denied"))
+
+		file, err := writer.Writer("exports/ratings.csv")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		_, err = file.Write([]byte("1,65"))
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		err = file.Close()
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "access denied", err.Error())
+	})
+
+	t.Run("fails upload of aborted file", func(t *testing.T) {
+		t.Helper()
+
+		uploader := new(MockUploader)
+		writer := filesystem.NewS3Writer(uploader, "statistico")
+
+		var readErr error
+
+		uploader.On("Upload", mock.Anything).Run(func(args mock.Arguments) {
+			_, readErr = ioutil.ReadAll(args.Get(0).(*s3manager.UploadInput).Body)
+		}).Return(&s3manager.UploadOutput{}, errors.New("read upload data failed"))
+
+		file, err := writer.Writer("exports/ratings.csv")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		file.Write([]byte("1,65"))
+
+		if err := file.Abort(); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		if readErr == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "upload aborted", readErr.Error())
+	})
+
+	t.Run("uploads files larger than part size in parts", func(t *testing.T) {
+		t.Helper()
+
+		server := newFakeS3()
+		defer server.Close()
+
+		writer := filesystem.NewS3Writer(server.uploader(t), "statistico")
+
+		file, err := writer.Writer("exports/ratings.csv")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		content := bytes.Repeat([]byte("1,65\n"), 3*1024*1024)
+
+		if _, err := file.Write(content); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		if err := file.Close(); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		a := assert.New(t)
+
+		a.Equal(3, server.parts)
+		a.True(bytes.Equal(content, server.objects["/statistico/exports/ratings.csv"]))
+		a.False(server.aborted)
+	})
+
+	t.Run("aborts multipart upload of aborted file", func(t *testing.T) {
+		t.Helper()
+
+		server := newFakeS3()
+		defer server.Close()
+
+		writer := filesystem.NewS3Writer(server.uploader(t), "statistico")
+
+		file, err := writer.Writer("exports/ratings.csv")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		if _, err := file.Write(bytes.Repeat([]byte("1,65\n"), 2*1024*1024)); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		if err := file.Abort(); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.True(t, server.aborted)
+		assert.Equal(t, 0, len(server.objects))
+	})
+}
+
+// fakeS3 is an S3 API server storing objects in memory, supporting the requests made by s3manager.Uploader.
+type fakeS3 struct {
+	*httptest.Server
+	mu      sync.Mutex
+	objects map[string][]byte
+	uploads map[string]map[int][]byte
+	parts   int
+	aborted bool
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	q := r.URL.Query()
+	body, _ := ioutil.ReadAll(r.Body)
+
+	switch {
+	case r.Method == http.MethodPost && q.Get("uploadId") == "":
+		f.uploads[r.URL.Path] = map[int][]byte{}
+		fmt.Fprint(w, "<InitiateMultipartUploadResult><UploadId>upload</UploadId></InitiateMultipartUploadResult>")
+	case r.Method == http.MethodPut && q.Get("partNumber") != "":
+		n, _ := strconv.Atoi(q.Get("partNumber"))
+		f.uploads[r.URL.Path][n] = body
+		f.parts++
+		w.Header().Set("ETag", fmt.Sprintf(`"%d"`, n))
+	case r.Method == http.MethodPost:
+		var content []byte
+
+		for n := 1; n <= len(f.uploads[r.URL.Path]); n++ {
+			content = append(content, f.uploads[r.URL.Path][n]...)
+		}
+
+		f.objects[r.URL.Path] = content
+		fmt.Fprint(w, "<CompleteMultipartUploadResult></CompleteMultipartUploadResult>")
+	case r.Method == http.MethodDelete:
+		f.aborted = true
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		f.objects[r.URL.Path] = body
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// uploader returns an s3manager.Uploader sending requests to the server in parts of the minimum size allowed.
+func (f *fakeS3) uploader(t *testing.T) *s3manager.Uploader {
+	sess, err := session.NewSession(&aws.Config{
+		Credentials:      credentials.NewStaticCredentials("key", "secret", ""),
+		Endpoint:         aws.String(f.URL),
+		Region:           aws.String("eu-west-2"),
+		S3ForcePathStyle: aws.Bool(true),
+	})
+
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+
+	return s3manager.NewUploaderWithClient(s3.New(sess), func(u *s3manager.Uploader) {
+		u.PartSize = s3manager.MinUploadPartSize
+	})
+}
+
+func newFakeS3() *fakeS3 {
+	f := fakeS3{objects: map[string][]byte{}, uploads: map[string]map[int][]byte{}}
+	f.Server = httptest.NewServer(&f)
+	return &f
+}
+
+type MockUploader struct {
+	s3manageriface.UploaderAPI
+	mock.Mock
+}
+
+func (m *MockUploader) Upload(i *s3manager.UploadInput, opts ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+	args := m.Called(i)
+	return args.Get(0).(*s3manager.UploadOutput), args.Error(1)
+}
+
+type MockS3Client struct {
+	s3iface.S3API
+	mock.Mock
+}
+
+func (m *MockS3Client) GetObject(i *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	args := m.Called(i)
+	return args.Get(0).(*s3.GetObjectOutput), args.Error(1)
+}
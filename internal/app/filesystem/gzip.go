@@ -0,0 +1,63 @@
+package filesystem
+
+import (
+	"compress/gzip"
+	"io"
+	"net/url"
+	"strings"
+)
+
+type gzipReader struct {
+	reader Reader
+}
+
+type gzipReadCloser struct {
+	*gzip.Reader
+	file io.ReadCloser
+}
+
+// Reader decompresses files with a .gz extension, returning all other files as read by the wrapped Reader. The
+// extension of a URL is taken from its path, ignoring any query string.
+func (g *gzipReader) Reader(filename string) (io.ReadCloser, error) {
+	file, err := g.reader.Reader(filename)
+
+	if err != nil || !gzipped(filename) {
+		return file, err
+	}
+
+	zr, err := gzip.NewReader(file)
+
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &gzipReadCloser{Reader: zr, file: file}, nil
+}
+
+func (g *gzipReadCloser) Close() error {
+	if err := g.Reader.Close(); err != nil {
+		g.file.Close()
+		return err
+	}
+
+	return g.file.Close()
+}
+
+func gzipped(filename string) bool {
+	if uriScheme(filename, "") == "" {
+		return strings.HasSuffix(filename, ".gz")
+	}
+
+	u, err := url.Parse(filename)
+
+	if err != nil {
+		return strings.HasSuffix(filename, ".gz")
+	}
+
+	return strings.HasSuffix(u.Path, ".gz")
+}
+
+func NewGzipReader(r Reader) Reader {
+	return &gzipReader{reader: r}
+}
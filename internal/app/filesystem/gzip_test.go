@@ -0,0 +1,104 @@
+package filesystem_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/statistico/statistico-ratings/internal/app/filesystem"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestGzipReader_Reader(t *testing.T) {
+	t.Run("decompresses files with a gz extension", func(t *testing.T) {
+		t.Helper()
+
+		var b bytes.Buffer
+
+		zw := gzip.NewWriter(&b)
+		zw.Write([]byte("8,17420"))
+		zw.Close()
+
+		wrapped := new(MockReader)
+		wrapped.On("Reader", "seasons.csv.gz").Return(ioutil.NopCloser(&b), nil)
+
+		file, err := filesystem.NewGzipReader(wrapped).Reader("seasons.csv.gz")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "8,17420", readAll(t, file))
+	})
+
+	t.Run("decompresses files with a gz extension in the path of a url with a query string", func(t *testing.T) {
+		t.Helper()
+
+		var b bytes.Buffer
+
+		zw := gzip.NewWriter(&b)
+		zw.Write([]byte("8,17420"))
+		zw.Close()
+
+		url := "https://example.com/seasons.csv.gz?token=abc"
+
+		wrapped := new(MockReader)
+		wrapped.On("Reader", url).Return(ioutil.NopCloser(&b), nil)
+
+		file, err := filesystem.NewGzipReader(wrapped).Reader(url)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "8,17420", readAll(t, file))
+	})
+
+	t.Run("returns files from a url without a gz extension in the path unchanged", func(t *testing.T) {
+		t.Helper()
+
+		url := "https://example.com/seasons.csv?name=seasons.gz"
+
+		wrapped := new(MockReader)
+		wrapped.On("Reader", url).Return(ioutil.NopCloser(strings.NewReader("8,17420")), nil)
+
+		file, err := filesystem.NewGzipReader(wrapped).Reader(url)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "8,17420", readAll(t, file))
+	})
+
+	t.Run("returns files without a gz extension unchanged", func(t *testing.T) {
+		t.Helper()
+
+		wrapped := new(MockReader)
+		wrapped.On("Reader", "seasons.csv").Return(ioutil.NopCloser(strings.NewReader("8,17420")), nil)
+
+		file, err := filesystem.NewGzipReader(wrapped).Reader("seasons.csv")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "8,17420", readAll(t, file))
+	})
+
+	t.Run("returns an error if file is not gzipped", func(t *testing.T) {
+		t.Helper()
+
+		wrapped := new(MockReader)
+		wrapped.On("Reader", "seasons.csv.gz").Return(ioutil.NopCloser(strings.NewReader("competition,season")), nil)
+
+		_, err := filesystem.NewGzipReader(wrapped).Reader("seasons.csv.gz")
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "gzip: invalid header", err.Error())
+	})
+}
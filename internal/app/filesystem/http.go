@@ -0,0 +1,31 @@
+package filesystem
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type httpReader struct {
+	client *http.Client
+}
+
+// Reader returns the body of a GET request to the filename URL provided.
+func (h *httpReader) Reader(filename string) (io.ReadCloser, error) {
+	res, err := h.client.Get(filename)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		res.Body.Close()
+		return nil, fmt.Errorf("error fetching %s: status code %d returned", filename, res.StatusCode)
+	}
+
+	return res.Body, nil
+}
+
+func NewHTTPReader(c *http.Client) Reader {
+	return &httpReader{client: c}
+}
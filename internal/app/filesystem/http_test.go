@@ -0,0 +1,51 @@
+package filesystem_test
+
+import (
+	"fmt"
+	"github.com/statistico/statistico-ratings/internal/app/filesystem"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPReader_Reader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/seasons.csv" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		fmt.Fprint(w, "8,17420")
+	}))
+
+	defer server.Close()
+
+	t.Run("returns response body", func(t *testing.T) {
+		t.Helper()
+
+		reader := filesystem.NewHTTPReader(server.Client())
+
+		file, err := reader.Reader(server.URL + "/seasons.csv")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "8,17420", readAll(t, file))
+	})
+
+	t.Run("returns an error if non success status code is returned", func(t *testing.T) {
+		t.Helper()
+
+		reader := filesystem.NewHTTPReader(server.Client())
+
+		_, err := reader.Reader(server.URL + "/missing.csv")
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, fmt.Sprintf("error fetching %s/missing.csv: status code 404 returned", server.URL), err.Error())
+	})
+}
@@ -0,0 +1,72 @@
+package filesystem
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type localReader struct{}
+
+// Reader opens filename on local disk. Filename is either a path or a file:// URI.
+func (l *localReader) Reader(filename string) (io.ReadCloser, error) {
+	return os.Open(strings.TrimPrefix(filename, "file://"))
+}
+
+func NewLocalReader() Reader {
+	return &localReader{}
+}
+
+type localWriter struct{}
+
+type localFile struct {
+	*os.File
+	path string
+}
+
+// Writer returns a File written to a temporary file alongside filename and renamed to filename on Close, creating
+// any missing directories. Filename is either a path or a file:// URI.
+func (l *localWriter) Writer(filename string) (File, error) {
+	path := strings.TrimPrefix(filename, "file://")
+	dir, name := filepath.Split(path)
+
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := ioutil.TempFile(dir, "."+name+".*.tmp")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &localFile{File: file, path: path}, nil
+}
+
+func (l *localFile) Close() error {
+	if err := l.File.Close(); err != nil {
+		os.Remove(l.Name())
+		return err
+	}
+
+	// Temporary files are created readable by the owner only.
+	if err := os.Chmod(l.Name(), 0644); err != nil {
+		os.Remove(l.Name())
+		return err
+	}
+
+	return os.Rename(l.Name(), l.path)
+}
+
+func (l *localFile) Abort() error {
+	l.File.Close()
+	return os.Remove(l.Name())
+}
+
+func NewLocalWriter() Writer {
+	return &localWriter{}
+}
@@ -0,0 +1,141 @@
+package filesystem_test
+
+import (
+	"github.com/statistico/statistico-ratings/internal/app/filesystem"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalReader_Reader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seasons.csv")
+
+	if err := ioutil.WriteFile(path, []byte("8,17420"), 0644); err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+
+	t.Run("reads file by path and file uri", func(t *testing.T) {
+		t.Helper()
+
+		reader := filesystem.NewLocalReader()
+
+		for _, filename := range []string{path, "file://" + path} {
+			file, err := reader.Reader(filename)
+
+			if err != nil {
+				t.Fatalf("Expected nil, got %s", err.Error())
+			}
+
+			assert.Equal(t, "8,17420", readAll(t, file))
+		}
+	})
+
+	t.Run("returns an error if file does not exist", func(t *testing.T) {
+		t.Helper()
+
+		reader := filesystem.NewLocalReader()
+
+		_, err := reader.Reader(path + ".missing")
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+}
+
+func TestLocalWriter_Writer(t *testing.T) {
+	t.Run("writes file by path and file uri creating missing directories", func(t *testing.T) {
+		t.Helper()
+
+		dir := t.TempDir()
+		writer := filesystem.NewLocalWriter()
+
+		for _, filename := range []string{filepath.Join(dir, "a", "ratings.csv"), "file://" + filepath.Join(dir, "b", "ratings.csv")} {
+			file, err := writer.Writer(filename)
+
+			if err != nil {
+				t.Fatalf("Expected nil, got %s", err.Error())
+			}
+
+			file.Write([]byte("1,65"))
+
+			if err := file.Close(); err != nil {
+				t.Fatalf("Expected nil, got %s", err.Error())
+			}
+		}
+
+		for _, path := range []string{filepath.Join(dir, "a", "ratings.csv"), filepath.Join(dir, "b", "ratings.csv")} {
+			b, err := ioutil.ReadFile(path)
+
+			if err != nil {
+				t.Fatalf("Expected nil, got %s", err.Error())
+			}
+
+			assert.Equal(t, "1,65", string(b))
+		}
+	})
+
+	t.Run("does not write file until closed", func(t *testing.T) {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), "ratings.csv")
+
+		file, err := filesystem.NewLocalWriter().Writer(path)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		file.Write([]byte("1,65"))
+
+		_, err = os.Stat(path)
+
+		assert.True(t, os.IsNotExist(err))
+
+		file.Close()
+	})
+
+	t.Run("removes aborted file leaving existing file unchanged", func(t *testing.T) {
+		t.Helper()
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "ratings.csv")
+
+		if err := ioutil.WriteFile(path, []byte("1,55"), 0644); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		file, err := filesystem.NewLocalWriter().Writer(path)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		file.Write([]byte("1,65"))
+
+		if err := file.Abort(); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		files, _ := ioutil.ReadDir(dir)
+		b, _ := ioutil.ReadFile(path)
+
+		assert.Equal(t, 1, len(files))
+		assert.Equal(t, "1,55", string(b))
+	})
+}
+
+func readAll(t *testing.T, r io.ReadCloser) string {
+	defer r.Close()
+
+	b, err := ioutil.ReadAll(r)
+
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+
+	return string(b)
+}
@@ -0,0 +1,69 @@
+package filesystem
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type schemeReader struct {
+	readers  map[string]Reader
+	fallback string
+}
+
+// Reader delegates to the Reader registered for the URI scheme of filename i.e. file, s3 or https. Filenames
+// without a scheme are read using the fallback scheme.
+func (s *schemeReader) Reader(filename string) (io.ReadCloser, error) {
+	scheme := uriScheme(filename, s.fallback)
+
+	r, ok := s.readers[scheme]
+
+	if !ok {
+		return nil, fmt.Errorf("no filesystem reader configured for scheme '%s'", scheme)
+	}
+
+	return r.Reader(filename)
+}
+
+// NewSchemeReader returns a Reader selecting a Reader from readers by the URI scheme of each filename read.
+func NewSchemeReader(readers map[string]Reader, fallback string) Reader {
+	return &schemeReader{
+		readers:  readers,
+		fallback: fallback,
+	}
+}
+
+type schemeWriter struct {
+	writers  map[string]Writer
+	fallback string
+}
+
+// Writer delegates to the Writer registered for the URI scheme of filename i.e. file or s3. Filenames without a
+// scheme are written using the fallback scheme.
+func (s *schemeWriter) Writer(filename string) (File, error) {
+	scheme := uriScheme(filename, s.fallback)
+
+	w, ok := s.writers[scheme]
+
+	if !ok {
+		return nil, fmt.Errorf("no filesystem writer configured for scheme '%s'", scheme)
+	}
+
+	return w.Writer(filename)
+}
+
+// NewSchemeWriter returns a Writer selecting a Writer from writers by the URI scheme of each filename written.
+func NewSchemeWriter(writers map[string]Writer, fallback string) Writer {
+	return &schemeWriter{
+		writers:  writers,
+		fallback: fallback,
+	}
+}
+
+func uriScheme(filename, fallback string) string {
+	if i := strings.Index(filename, "://"); i > 0 {
+		return filename[:i]
+	}
+
+	return fallback
+}
@@ -0,0 +1,132 @@
+package filesystem_test
+
+import (
+	"github.com/statistico/statistico-ratings/internal/app/filesystem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestSchemeReader_Reader(t *testing.T) {
+	t.Run("delegates to reader registered for uri scheme", func(t *testing.T) {
+		t.Helper()
+
+		local := new(MockReader)
+		s3 := new(MockReader)
+
+		reader := filesystem.NewSchemeReader(map[string]filesystem.Reader{"file": local, "s3": s3}, "s3")
+
+		local.On("Reader", "file:///tmp/seasons.csv").Return(ioutil.NopCloser(strings.NewReader("local")), nil)
+		s3.On("Reader", "s3://statistico/seasons.csv").Return(ioutil.NopCloser(strings.NewReader("s3")), nil)
+		s3.On("Reader", "seasons.csv").Return(ioutil.NopCloser(strings.NewReader("fallback")), nil)
+
+		for filename, expected := range map[string]string{
+			"file:///tmp/seasons.csv":     "local",
+			"s3://statistico/seasons.csv": "s3",
+			"seasons.csv":                 "fallback",
+		} {
+			file, err := reader.Reader(filename)
+
+			if err != nil {
+				t.Fatalf("Expected nil, got %s", err.Error())
+			}
+
+			assert.Equal(t, expected, readAll(t, file))
+		}
+
+		local.AssertExpectations(t)
+		s3.AssertExpectations(t)
+	})
+
+	t.Run("returns an error if no reader is registered for scheme", func(t *testing.T) {
+		t.Helper()
+
+		reader := filesystem.NewSchemeReader(map[string]filesystem.Reader{}, "s3")
+
+		_, err := reader.Reader("https://statistico.io/seasons.csv")
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "no filesystem reader configured for scheme 'https'", err.Error())
+	})
+}
+
+func TestSchemeWriter_Writer(t *testing.T) {
+	t.Run("delegates to writer registered for uri scheme", func(t *testing.T) {
+		t.Helper()
+
+		local := new(MockWriter)
+		s3 := new(MockWriter)
+
+		writer := filesystem.NewSchemeWriter(map[string]filesystem.Writer{"file": local, "s3": s3}, "file")
+
+		local.On("Writer", "file:///tmp/ratings.csv").Return(new(MockFile), nil)
+		local.On("Writer", "ratings.csv").Return(new(MockFile), nil)
+		s3.On("Writer", "s3://statistico/ratings.csv").Return(new(MockFile), nil)
+
+		for _, filename := range []string{"file:///tmp/ratings.csv", "s3://statistico/ratings.csv", "ratings.csv"} {
+			if _, err := writer.Writer(filename); err != nil {
+				t.Fatalf("Expected nil, got %s", err.Error())
+			}
+		}
+
+		local.AssertExpectations(t)
+		s3.AssertExpectations(t)
+	})
+
+	t.Run("returns an error if no writer is registered for scheme", func(t *testing.T) {
+		t.Helper()
+
+		writer := filesystem.NewSchemeWriter(map[string]filesystem.Writer{}, "file")
+
+		_, err := writer.Writer("s3://statistico/ratings.csv")
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "no filesystem writer configured for scheme 's3'", err.Error())
+	})
+}
+
+type MockReader struct {
+	mock.Mock
+}
+
+func (m *MockReader) Reader(filename string) (io.ReadCloser, error) {
+	args := m.Called(filename)
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+type MockWriter struct {
+	mock.Mock
+}
+
+func (m *MockWriter) Writer(filename string) (filesystem.File, error) {
+	args := m.Called(filename)
+	return args.Get(0).(filesystem.File), args.Error(1)
+}
+
+type MockFile struct {
+	mock.Mock
+}
+
+func (m *MockFile) Write(p []byte) (int, error) {
+	args := m.Called(p)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockFile) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockFile) Abort() error {
+	args := m.Called()
+	return args.Error(0)
+}
@@ -13,6 +13,10 @@ import (
 type Fetcher interface {
 	ByCompetition(ctx context.Context, competitionID, seasonID uint64) ([]*statistico.Fixture, error)
 	ByDate(ctx context.Context, from, to time.Time) ([]*statistico.Fixture, error)
+	ByID(ctx context.Context, fixtureID uint64) (*statistico.Fixture, error)
+	// Seasons returns the IDs of the current season and the seasons before it for a competition, up to last seasons
+	// in total, ordered from oldest to newest.
+	Seasons(ctx context.Context, competitionID uint64, last int) ([]uint64, error)
 }
 
 type fetcher struct {
@@ -35,13 +39,28 @@ func (f *fetcher) ByCompetition(ctx context.Context, competitionID, seasonID uin
 		return nil, err
 	}
 
+	// Fixtures yet to be played are filtered here rather than by the request so the request is the same on every
+	// call and search results can be cached.
 	req := statistico.FixtureSearchRequest{
-		SeasonIds:  []uint64{season.Id},
-		DateBefore: &wrappers.StringValue{Value: f.clock.Now().Format(time.RFC3339)},
-		Sort:       &wrappers.StringValue{Value: "date_asc"},
+		SeasonIds: []uint64{season.Id},
+		Sort:      &wrappers.StringValue{Value: "date_asc"},
 	}
 
-	return f.fixtureClient.Search(ctx, &req)
+	response, err := f.fixtureClient.Search(ctx, &req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures []*statistico.Fixture
+
+	for _, fixture := range response {
+		if fixture.GetDateTime().GetUtc() <= f.clock.Now().Unix() {
+			fixtures = append(fixtures, fixture)
+		}
+	}
+
+	return fixtures, nil
 }
 
 func (f *fetcher) ByDate(ctx context.Context, from, to time.Time) ([]*statistico.Fixture, error) {
@@ -70,6 +89,42 @@ func (f *fetcher) ByDate(ctx context.Context, from, to time.Time) ([]*statistico
 	return fixtures, nil
 }
 
+func (f *fetcher) ByID(ctx context.Context, fixtureID uint64) (*statistico.Fixture, error) {
+	return f.fixtureClient.ByID(ctx, fixtureID)
+}
+
+func (f *fetcher) Seasons(ctx context.Context, competitionID uint64, last int) ([]uint64, error) {
+	res, err := f.seasonClient.ByCompetitionID(ctx, competitionID, "name_desc")
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, season := range res {
+		if season.GetIsCurrent().GetValue() {
+			return recentSeasons(res[i:], last), nil
+		}
+	}
+
+	return nil, fmt.Errorf("competition %d does not have a current season", competitionID)
+}
+
+// recentSeasons returns the IDs of up to last seasons from seasons ordered newest first, reversed so the oldest
+// season is first.
+func recentSeasons(seasons []*statistico.Season, last int) []uint64 {
+	if last < len(seasons) {
+		seasons = seasons[:last]
+	}
+
+	ids := make([]uint64, len(seasons))
+
+	for i, season := range seasons {
+		ids[len(seasons)-1-i] = season.Id
+	}
+
+	return ids
+}
+
 func parseSeason(s []*statistico.Season, id uint64) (*statistico.Season, error) {
 	for _, season := range s {
 		if season.Id == id {
@@ -3,6 +3,7 @@ package fixture_test
 import (
 	"context"
 	"errors"
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/jonboulle/clockwork"
 	"github.com/statistico/statistico-proto/go"
 	"github.com/statistico/statistico-ratings/internal/app/fixture"
@@ -29,7 +30,7 @@ func TestFetcher_ByCompetition(t *testing.T) {
 
 		req := mock.MatchedBy(func(r *statistico.FixtureSearchRequest) bool {
 			assert.Equal(t, []uint64{2}, r.SeasonIds)
-			assert.Equal(t, "1984-04-04T00:00:00Z", r.DateBefore.GetValue())
+			assert.Nil(t, r.DateBefore)
 			assert.Equal(t, "date_asc", r.Sort.GetValue())
 			return true
 		})
@@ -49,6 +50,36 @@ func TestFetcher_ByCompetition(t *testing.T) {
 		fixtureClient.AssertExpectations(t)
 	})
 
+	t.Run("filters out fixtures yet to be played", func(t *testing.T) {
+		t.Helper()
+
+		fixtureClient := new(MockFixtureClient)
+		seasonClient := new(MockSeasonClient)
+		clock := clockwork.NewFakeClockAt(time.Unix(1627226510, 0))
+
+		ctx := context.Background()
+
+		fetcher := fixture.NewFetcher([]uint64{8}, fixtureClient, seasonClient, clock)
+
+		seasonClient.On("ByCompetitionID", ctx, uint64(8), "name_desc").Return(seasonResponse(), nil)
+
+		response := []*statistico.Fixture{
+			{Id: 1, DateTime: &statistico.Date{Utc: 1627226500}},
+			{Id: 2, DateTime: &statistico.Date{Utc: 1627226510}},
+			{Id: 3, DateTime: &statistico.Date{Utc: 1627226520}},
+		}
+
+		fixtureClient.On("Search", ctx, mock.AnythingOfType("*statistico.FixtureSearchRequest")).Return(response, nil)
+
+		fixtures, err := fetcher.ByCompetition(ctx, uint64(8), uint64(2))
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, response[:2], fixtures)
+	})
+
 	t.Run("returns an error if returned by season client", func(t *testing.T) {
 		t.Helper()
 
@@ -96,7 +127,7 @@ func TestFetcher_ByCompetition(t *testing.T) {
 
 		req := mock.MatchedBy(func(r *statistico.FixtureSearchRequest) bool {
 			assert.Equal(t, []uint64{2}, r.SeasonIds)
-			assert.Equal(t, "1984-04-04T00:00:00Z", r.DateBefore.GetValue())
+			assert.Nil(t, r.DateBefore)
 			assert.Equal(t, "date_asc", r.Sort.GetValue())
 			return true
 		})
@@ -294,6 +325,133 @@ func fixtureResponse() []*statistico.Fixture {
 	}
 }
 
+func TestFetcher_ByID(t *testing.T) {
+	t.Run("fetches and returns a fixture", func(t *testing.T) {
+		t.Helper()
+
+		fixtureClient := new(MockFixtureClient)
+		seasonClient := new(MockSeasonClient)
+
+		ctx := context.Background()
+
+		fetcher := fixture.NewFetcher([]uint64{8}, fixtureClient, seasonClient, clockwork.NewFakeClock())
+
+		fix := statistico.Fixture{Id: 26}
+
+		fixtureClient.On("ByID", ctx, uint64(26)).Return(&fix, nil)
+
+		fetched, err := fetcher.ByID(ctx, 26)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &fix, fetched)
+		fixtureClient.AssertExpectations(t)
+	})
+
+	t.Run("returns an error if returned by fixture client", func(t *testing.T) {
+		t.Helper()
+
+		fixtureClient := new(MockFixtureClient)
+		seasonClient := new(MockSeasonClient)
+
+		ctx := context.Background()
+
+		fetcher := fixture.NewFetcher([]uint64{8}, fixtureClient, seasonClient, clockwork.NewFakeClock())
+
+		fixtureClient.On("ByID", ctx, uint64(26)).Return(&statistico.Fixture{}, errors.New("fixture client error"))
+
+		_, err := fetcher.ByID(ctx, 26)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "fixture client error", err.Error())
+	})
+}
+
+func TestFetcher_Seasons(t *testing.T) {
+	seasons := []*statistico.Season{
+		{Id: 4, Name: "2021/2022", IsCurrent: &wrappers.BoolValue{Value: false}},
+		{Id: 3, Name: "2020/2021", IsCurrent: &wrappers.BoolValue{Value: true}},
+		{Id: 2, Name: "2019/2020", IsCurrent: &wrappers.BoolValue{Value: false}},
+		{Id: 1, Name: "2018/2019", IsCurrent: &wrappers.BoolValue{Value: false}},
+	}
+
+	t.Run("returns the current and previous seasons ordered oldest first", func(t *testing.T) {
+		t.Helper()
+
+		fixtureClient := new(MockFixtureClient)
+		seasonClient := new(MockSeasonClient)
+
+		ctx := context.Background()
+
+		fetcher := fixture.NewFetcher([]uint64{8}, fixtureClient, seasonClient, clockwork.NewFakeClock())
+
+		seasonClient.On("ByCompetitionID", ctx, uint64(8), "name_desc").Return(seasons, nil)
+
+		current, err := fetcher.Seasons(ctx, 8, 1)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, []uint64{3}, current)
+
+		last, err := fetcher.Seasons(ctx, 8, 5)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, []uint64{1, 2, 3}, last)
+	})
+
+	t.Run("returns an error if competition does not have a current season", func(t *testing.T) {
+		t.Helper()
+
+		fixtureClient := new(MockFixtureClient)
+		seasonClient := new(MockSeasonClient)
+
+		ctx := context.Background()
+
+		fetcher := fixture.NewFetcher([]uint64{8}, fixtureClient, seasonClient, clockwork.NewFakeClock())
+
+		seasonClient.On("ByCompetitionID", ctx, uint64(8), "name_desc").Return(seasons[2:], nil)
+
+		_, err := fetcher.Seasons(ctx, 8, 1)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "competition 8 does not have a current season", err.Error())
+	})
+
+	t.Run("returns an error if returned by season client", func(t *testing.T) {
+		t.Helper()
+
+		fixtureClient := new(MockFixtureClient)
+		seasonClient := new(MockSeasonClient)
+
+		ctx := context.Background()
+
+		fetcher := fixture.NewFetcher([]uint64{8}, fixtureClient, seasonClient, clockwork.NewFakeClock())
+
+		seasonClient.On("ByCompetitionID", ctx, uint64(8), "name_desc").Return([]*statistico.Season{}, errors.New("season client error"))
+
+		_, err := fetcher.Seasons(ctx, 8, 1)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "season client error", err.Error())
+	})
+}
+
 type MockFixtureClient struct {
 	mock.Mock
 }
@@ -0,0 +1,332 @@
+package fixture
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"github.com/jonboulle/clockwork"
+	"github.com/statistico/statistico-football-data-go-grpc-client"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app/filesystem"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	fixtureColumns = []string{"id", "competition_id", "season_id", "home_team_id", "away_team_id", "date"}
+	eventColumns   = []string{"fixture_id", "team_id", "type", "minute"}
+)
+
+type fileFetcher struct {
+	competitions []uint64
+	reader       filesystem.Reader
+	path         string
+	clock        clockwork.Clock
+	fixtures     []*statistico.Fixture
+	once         sync.Once
+	err          error
+}
+
+func (f *fileFetcher) ByCompetition(ctx context.Context, competitionID, seasonID uint64) ([]*statistico.Fixture, error) {
+	all, err := f.load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures []*statistico.Fixture
+	var exists bool
+
+	for _, fix := range all {
+		if fix.Competition.Id != competitionID || fix.Season.Id != seasonID {
+			continue
+		}
+
+		exists = true
+
+		if fix.DateTime.Utc < f.clock.Now().Unix() {
+			fixtures = append(fixtures, fix)
+		}
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("season %d does not exist", seasonID)
+	}
+
+	return fixtures, nil
+}
+
+func (f *fileFetcher) ByDate(ctx context.Context, from, to time.Time) ([]*statistico.Fixture, error) {
+	all, err := f.load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures []*statistico.Fixture
+
+	for _, fix := range all {
+		if fix.DateTime.Utc < from.Unix() || fix.DateTime.Utc > to.Unix() {
+			continue
+		}
+
+		for _, competition := range f.competitions {
+			if fix.Competition.Id == competition {
+				fixtures = append(fixtures, fix)
+			}
+		}
+	}
+
+	return fixtures, nil
+}
+
+func (f *fileFetcher) ByID(ctx context.Context, fixtureID uint64) (*statistico.Fixture, error) {
+	all, err := f.load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fix := range all {
+		if uint64(fix.Id) == fixtureID {
+			return fix, nil
+		}
+	}
+
+	return nil, fmt.Errorf("fixture %d does not exist", fixtureID)
+}
+
+// Seasons treats the season with the most recently played fixture in the file as the current season.
+func (f *fileFetcher) Seasons(ctx context.Context, competitionID uint64, last int) ([]uint64, error) {
+	all, err := f.load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var seasons []*statistico.Season
+
+	// Fixtures are sorted by date so iterating in reverse finds each season in order of its latest fixture
+	for i := len(all) - 1; i >= 0; i-- {
+		fix := all[i]
+
+		if fix.Competition.Id != competitionID || fix.DateTime.Utc > f.clock.Now().Unix() {
+			continue
+		}
+
+		exists := false
+
+		for _, s := range seasons {
+			if s.Id == fix.Season.Id {
+				exists = true
+			}
+		}
+
+		if !exists {
+			seasons = append(seasons, fix.Season)
+		}
+	}
+
+	if len(seasons) == 0 {
+		return nil, fmt.Errorf("competition %d does not have a current season", competitionID)
+	}
+
+	return recentSeasons(seasons, last), nil
+}
+
+// load parses the fixture file on first use, returning fixtures sorted by date in ascending order.
+func (f *fileFetcher) load() ([]*statistico.Fixture, error) {
+	f.once.Do(func() {
+		f.err = readCSV(f.reader, f.path, fixtureColumns, func(row map[string]string) error {
+			fix, err := parseFixture(row)
+
+			if err != nil {
+				return err
+			}
+
+			f.fixtures = append(f.fixtures, fix)
+
+			return nil
+		})
+
+		sort.SliceStable(f.fixtures, func(i, j int) bool {
+			return f.fixtures[i].DateTime.Utc < f.fixtures[j].DateTime.Utc
+		})
+	})
+
+	return f.fixtures, f.err
+}
+
+type fileEventClient struct {
+	reader filesystem.Reader
+	path   string
+	events map[uint64]*statistico.FixtureEventsResponse
+	once   sync.Once
+	err    error
+}
+
+func (f *fileEventClient) FixtureEvents(ctx context.Context, fixtureID uint64) (*statistico.FixtureEventsResponse, error) {
+	f.once.Do(func() {
+		f.events = map[uint64]*statistico.FixtureEventsResponse{}
+		f.err = readCSV(f.reader, f.path, eventColumns, f.parseEvent)
+	})
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	if events, ok := f.events[fixtureID]; ok {
+		return events, nil
+	}
+
+	return &statistico.FixtureEventsResponse{FixtureId: fixtureID}, nil
+}
+
+func (f *fileEventClient) parseEvent(row map[string]string) error {
+	ids, err := parseUints(row, "fixture_id", "team_id", "minute")
+
+	if err != nil {
+		return err
+	}
+
+	events, ok := f.events[ids[0]]
+
+	if !ok {
+		events = &statistico.FixtureEventsResponse{FixtureId: ids[0]}
+		f.events[ids[0]] = events
+	}
+
+	switch row["type"] {
+	case "goal":
+		events.Goals = append(events.Goals, &statistico.GoalEvent{TeamId: ids[1], Minute: uint32(ids[2])})
+	case "yellowcard", "redcard":
+		events.Cards = append(events.Cards, &statistico.CardEvent{TeamId: ids[1], Type: row["type"], Minute: uint32(ids[2])})
+	default:
+		return fmt.Errorf("event type '%s' is not supported", row["type"])
+	}
+
+	return nil
+}
+
+func parseFixture(row map[string]string) (*statistico.Fixture, error) {
+	ids, err := parseUints(row, "id", "competition_id", "season_id", "home_team_id", "away_team_id")
+
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := time.Parse(time.RFC3339, row["date"])
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &statistico.Fixture{
+		Id:          int64(ids[0]),
+		Competition: &statistico.Competition{Id: ids[1]},
+		Season:      &statistico.Season{Id: ids[2]},
+		HomeTeam:    &statistico.Team{Id: ids[3]},
+		AwayTeam:    &statistico.Team{Id: ids[4]},
+		DateTime: &statistico.Date{
+			Utc: date.Unix(),
+			Rfc: date.Format(time.RFC3339),
+		},
+	}, nil
+}
+
+func parseUints(row map[string]string, columns ...string) ([]uint64, error) {
+	var values []uint64
+
+	for _, col := range columns {
+		v, err := strconv.ParseUint(row[col], 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s' for column %s", row[col], col)
+		}
+
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// readCSV reads the CSV file at path, which must contain a header row including each of the columns provided, and
+// calls fn with each subsequent row keyed by column name.
+func readCSV(r filesystem.Reader, path string, columns []string, fn func(row map[string]string) error) error {
+	file, err := r.Reader(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	rows := csv.NewReader(file)
+
+	header, err := rows.Read()
+
+	if err != nil {
+		return fmt.Errorf("error reading header of %s: %s", path, err.Error())
+	}
+
+	for _, col := range columns {
+		if !contains(header, col) {
+			return fmt.Errorf("file %s is missing column %s", path, col)
+		}
+	}
+
+	for {
+		values, err := rows.Read()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		row := map[string]string{}
+
+		for i, col := range header {
+			row[col] = values[i]
+		}
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+}
+
+func contains(values []string, v string) bool {
+	for _, val := range values {
+		if val == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewFileFetcher returns a Fetcher returning fixtures parsed from the fixture CSV file at path. See the README for
+// the file format.
+func NewFileFetcher(c []uint64, r filesystem.Reader, path string, cl clockwork.Clock) Fetcher {
+	return &fileFetcher{
+		competitions: c,
+		reader:       r,
+		path:         path,
+		clock:        cl,
+	}
+}
+
+// NewFileEventClient returns a statisticodata.EventClient returning goal and card events parsed from the event
+// CSV file at path. See the README for the file format.
+func NewFileEventClient(r filesystem.Reader, path string) statisticodata.EventClient {
+	return &fileEventClient{
+		reader: r,
+		path:   path,
+	}
+}
@@ -0,0 +1,318 @@
+package fixture_test
+
+import (
+	"context"
+	"errors"
+	"github.com/jonboulle/clockwork"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+const fixtureCSV = `id,competition_id,season_id,home_team_id,away_team_id,date
+3,8,17462,5,6,2021-03-13T15:00:00Z
+1,8,17462,1,2,2021-03-12T15:00:00Z
+2,9,17463,3,4,2021-03-12T17:30:00Z
+4,8,17462,2,1,2021-05-01T15:00:00Z
+`
+
+const eventCSV = `fixture_id,team_id,type,minute
+1,1,goal,4
+1,2,redcard,30
+1,1,goal,67
+2,3,yellowcard,12
+`
+
+func TestFileFetcher_ByCompetition(t *testing.T) {
+	t.Run("returns fixtures for competition and season played before now sorted by date", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockFilesystemReader)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615680000, 0))
+
+		fetcher := fixture.NewFileFetcher([]uint64{8}, reader, "fixtures.csv", clock)
+
+		reader.On("Reader", "fixtures.csv").Once().Return(ioutil.NopCloser(strings.NewReader(fixtureCSV)), nil)
+
+		fixtures, err := fetcher.ByCompetition(context.Background(), 8, 17462)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		a := assert.New(t)
+
+		a.Equal(2, len(fixtures))
+		a.Equal(int64(1), fixtures[0].Id)
+		a.Equal(uint64(8), fixtures[0].Competition.Id)
+		a.Equal(uint64(17462), fixtures[0].Season.Id)
+		a.Equal(uint64(1), fixtures[0].HomeTeam.Id)
+		a.Equal(uint64(2), fixtures[0].AwayTeam.Id)
+		a.Equal(int64(1615561200), fixtures[0].DateTime.Utc)
+		a.Equal(int64(3), fixtures[1].Id)
+		reader.AssertExpectations(t)
+	})
+
+	t.Run("returns an error if season does not exist in file", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockFilesystemReader)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615680000, 0))
+
+		fetcher := fixture.NewFileFetcher([]uint64{8}, reader, "fixtures.csv", clock)
+
+		reader.On("Reader", "fixtures.csv").Return(ioutil.NopCloser(strings.NewReader(fixtureCSV)), nil)
+
+		_, err := fetcher.ByCompetition(context.Background(), 8, 12)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "season 12 does not exist", err.Error())
+	})
+
+	t.Run("returns an error if returned by filesystem reader", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockFilesystemReader)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615680000, 0))
+
+		fetcher := fixture.NewFileFetcher([]uint64{8}, reader, "fixtures.csv", clock)
+
+		reader.On("Reader", "fixtures.csv").Return(nil, errors.New("reader error"))
+
+		_, err := fetcher.ByCompetition(context.Background(), 8, 17462)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "reader error", err.Error())
+	})
+
+	t.Run("returns an error if file is missing a column", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockFilesystemReader)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615680000, 0))
+
+		fetcher := fixture.NewFileFetcher([]uint64{8}, reader, "fixtures.csv", clock)
+
+		file := "id,competition_id,season_id,home_team_id,away_team_id\n1,8,17462,1,2\n"
+
+		reader.On("Reader", "fixtures.csv").Return(ioutil.NopCloser(strings.NewReader(file)), nil)
+
+		_, err := fetcher.ByCompetition(context.Background(), 8, 17462)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "file fixtures.csv is missing column date", err.Error())
+	})
+}
+
+func TestFileFetcher_ByDate(t *testing.T) {
+	t.Run("returns fixtures for supported competitions between dates", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockFilesystemReader)
+		clock := clockwork.NewFakeClock()
+
+		fetcher := fixture.NewFileFetcher([]uint64{8}, reader, "fixtures.csv", clock)
+
+		reader.On("Reader", "fixtures.csv").Return(ioutil.NopCloser(strings.NewReader(fixtureCSV)), nil)
+
+		from := time.Date(2021, 3, 12, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2021, 3, 13, 0, 0, 0, 0, time.UTC)
+
+		fixtures, err := fetcher.ByDate(context.Background(), from, to)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, 1, len(fixtures))
+		assert.Equal(t, int64(1), fixtures[0].Id)
+	})
+}
+
+func TestFileFetcher_ByID(t *testing.T) {
+	t.Run("returns fixture for ID", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockFilesystemReader)
+
+		fetcher := fixture.NewFileFetcher([]uint64{8}, reader, "fixtures.csv", clockwork.NewFakeClock())
+
+		reader.On("Reader", "fixtures.csv").Return(ioutil.NopCloser(strings.NewReader(fixtureCSV)), nil)
+
+		fix, err := fetcher.ByID(context.Background(), 2)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, int64(2), fix.Id)
+		assert.Equal(t, uint64(9), fix.Competition.Id)
+	})
+
+	t.Run("returns an error if fixture does not exist in file", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockFilesystemReader)
+
+		fetcher := fixture.NewFileFetcher([]uint64{8}, reader, "fixtures.csv", clockwork.NewFakeClock())
+
+		reader.On("Reader", "fixtures.csv").Return(ioutil.NopCloser(strings.NewReader(fixtureCSV)), nil)
+
+		_, err := fetcher.ByID(context.Background(), 10)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "fixture 10 does not exist", err.Error())
+	})
+}
+
+func TestFileFetcher_Seasons(t *testing.T) {
+	file := `id,competition_id,season_id,home_team_id,away_team_id,date
+1,8,17420,1,2,2020-03-12T15:00:00Z
+2,8,17462,1,2,2021-03-12T15:00:00Z
+3,8,17361,1,2,2019-03-12T15:00:00Z
+4,9,17463,3,4,2021-03-13T15:00:00Z
+5,8,18378,1,2,2021-09-12T15:00:00Z
+`
+
+	t.Run("returns seasons with played fixtures ordered oldest first", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockFilesystemReader)
+		clock := clockwork.NewFakeClockAt(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC))
+
+		fetcher := fixture.NewFileFetcher([]uint64{8}, reader, "fixtures.csv", clock)
+
+		reader.On("Reader", "fixtures.csv").Once().Return(ioutil.NopCloser(strings.NewReader(file)), nil)
+
+		seasons, err := fetcher.Seasons(context.Background(), 8, 2)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, []uint64{17420, 17462}, seasons)
+	})
+
+	t.Run("returns an error if competition does not have a season", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockFilesystemReader)
+		clock := clockwork.NewFakeClockAt(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC))
+
+		fetcher := fixture.NewFileFetcher([]uint64{8}, reader, "fixtures.csv", clock)
+
+		reader.On("Reader", "fixtures.csv").Return(ioutil.NopCloser(strings.NewReader(file)), nil)
+
+		_, err := fetcher.Seasons(context.Background(), 10, 1)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "competition 10 does not have a current season", err.Error())
+	})
+}
+
+func TestFileEventClient_FixtureEvents(t *testing.T) {
+	t.Run("returns goal and card events for a fixture", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockFilesystemReader)
+
+		client := fixture.NewFileEventClient(reader, "events.csv")
+
+		reader.On("Reader", "events.csv").Once().Return(ioutil.NopCloser(strings.NewReader(eventCSV)), nil)
+
+		events, err := client.FixtureEvents(context.Background(), 1)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		a := assert.New(t)
+
+		a.Equal(uint64(1), events.FixtureId)
+		a.Equal([]*statistico.GoalEvent{{TeamId: 1, Minute: 4}, {TeamId: 1, Minute: 67}}, events.Goals)
+		a.Equal([]*statistico.CardEvent{{TeamId: 2, Type: "redcard", Minute: 30}}, events.Cards)
+
+		events, err = client.FixtureEvents(context.Background(), 2)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		a.Equal(0, len(events.Goals))
+		a.Equal([]*statistico.CardEvent{{TeamId: 3, Type: "yellowcard", Minute: 12}}, events.Cards)
+		reader.AssertExpectations(t)
+	})
+
+	t.Run("returns empty events for a fixture without events", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockFilesystemReader)
+
+		client := fixture.NewFileEventClient(reader, "events.csv")
+
+		reader.On("Reader", "events.csv").Return(ioutil.NopCloser(strings.NewReader(eventCSV)), nil)
+
+		events, err := client.FixtureEvents(context.Background(), 5)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &statistico.FixtureEventsResponse{FixtureId: 5}, events)
+	})
+
+	t.Run("returns an error if event type is not supported", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockFilesystemReader)
+
+		client := fixture.NewFileEventClient(reader, "events.csv")
+
+		file := "fixture_id,team_id,type,minute\n1,1,corner,4\n"
+
+		reader.On("Reader", "events.csv").Return(ioutil.NopCloser(strings.NewReader(file)), nil)
+
+		_, err := client.FixtureEvents(context.Background(), 1)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "event type 'corner' is not supported", err.Error())
+	})
+}
+
+type MockFilesystemReader struct {
+	mock.Mock
+}
+
+func (m *MockFilesystemReader) Reader(filename string) (io.ReadCloser, error) {
+	args := m.Called(filename)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
@@ -0,0 +1,75 @@
+package fixture
+
+import (
+	"context"
+	"github.com/statistico/statistico-football-data-go-grpc-client"
+	"github.com/statistico/statistico-proto/go"
+)
+
+// StatusChecker determines whether a fixture has finished and can be rated.
+type StatusChecker interface {
+	// Finished returns true if the fixture has finished. If not, the reason the fixture is treated as unfinished
+	// is returned.
+	Finished(ctx context.Context, f *statistico.Fixture) (bool, string, error)
+}
+
+type finishedKey struct{}
+
+// WithFinished returns a copy of ctx recording the fixtures provided as finished, so data fetched for them while
+// they are processed can be treated as final without checking their status again.
+func WithFinished(ctx context.Context, fixtures []*statistico.Fixture) context.Context {
+	ids := make(map[uint64]bool, len(fixtures))
+
+	for _, f := range fixtures {
+		ids[uint64(f.GetId())] = true
+	}
+
+	return context.WithValue(ctx, finishedKey{}, ids)
+}
+
+// FinishedFromContext returns true if the fixture was recorded as finished in ctx by WithFinished.
+func FinishedFromContext(ctx context.Context, fixtureID uint64) bool {
+	ids, _ := ctx.Value(finishedKey{}).(map[uint64]bool)
+	return ids[fixtureID]
+}
+
+type resultStatusChecker struct {
+	client statisticodata.ResultClient
+}
+
+// Finished treats a fixture as finished once the data service returns a full time score for it. Fixtures that are
+// in progress, abandoned or postponed do not have a full time score.
+func (r *resultStatusChecker) Finished(ctx context.Context, f *statistico.Fixture) (bool, string, error) {
+	res, err := r.client.ByID(ctx, uint64(f.GetId()))
+
+	if _, ok := err.(statisticodata.ErrorNotFound); ok {
+		return false, "result does not exist", nil
+	}
+
+	if err != nil {
+		return false, "", err
+	}
+
+	if res.GetStats().GetFullTimeScore().GetValue() == "" {
+		return false, "result does not have a full time score", nil
+	}
+
+	return true, "", nil
+}
+
+type fileStatusChecker struct{}
+
+func (f *fileStatusChecker) Finished(ctx context.Context, fix *statistico.Fixture) (bool, string, error) {
+	return true, "", nil
+}
+
+// NewResultStatusChecker returns a StatusChecker using results returned by the Statistico data service.
+func NewResultStatusChecker(r statisticodata.ResultClient) StatusChecker {
+	return &resultStatusChecker{client: r}
+}
+
+// NewFileStatusChecker returns a StatusChecker treating every fixture as finished, as fixture files only hold
+// fixtures that have been played.
+func NewFileStatusChecker() StatusChecker {
+	return &fileStatusChecker{}
+}
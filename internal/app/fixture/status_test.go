@@ -0,0 +1,125 @@
+package fixture_test
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/statistico/statistico-football-data-go-grpc-client"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestFinishedFromContext(t *testing.T) {
+	t.Run("returns true for fixtures recorded as finished in the context", func(t *testing.T) {
+		t.Helper()
+
+		ctx := fixture.WithFinished(context.Background(), []*statistico.Fixture{{Id: 26}, {Id: 27}})
+
+		assert.True(t, fixture.FinishedFromContext(ctx, 26))
+		assert.True(t, fixture.FinishedFromContext(ctx, 27))
+		assert.False(t, fixture.FinishedFromContext(ctx, 28))
+		assert.False(t, fixture.FinishedFromContext(context.Background(), 26))
+	})
+}
+
+func TestResultStatusChecker_Finished(t *testing.T) {
+	fix := statistico.Fixture{Id: 26}
+	ctx := context.Background()
+
+	t.Run("returns true if result has a full time score", func(t *testing.T) {
+		t.Helper()
+
+		client := new(MockResultClient)
+		checker := fixture.NewResultStatusChecker(client)
+
+		res := statistico.Result{Stats: &statistico.MatchStats{FullTimeScore: &wrappers.StringValue{Value: "2-1"}}}
+
+		client.On("ByID", ctx, uint64(26)).Return(&res, nil)
+
+		finished, reason, err := checker.Finished(ctx, &fix)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.True(t, finished)
+		assert.Equal(t, "", reason)
+	})
+
+	t.Run("returns false and reason if result does not have a full time score", func(t *testing.T) {
+		t.Helper()
+
+		client := new(MockResultClient)
+		checker := fixture.NewResultStatusChecker(client)
+
+		res := statistico.Result{Stats: &statistico.MatchStats{HalfTimeScore: &wrappers.StringValue{Value: "1-0"}}}
+
+		client.On("ByID", ctx, uint64(26)).Return(&res, nil)
+
+		finished, reason, err := checker.Finished(ctx, &fix)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.False(t, finished)
+		assert.Equal(t, "result does not have a full time score", reason)
+	})
+
+	t.Run("returns false and reason if result does not exist", func(t *testing.T) {
+		t.Helper()
+
+		client := new(MockResultClient)
+		checker := fixture.NewResultStatusChecker(client)
+
+		client.On("ByID", ctx, uint64(26)).Return(nil, statisticodata.ErrorNotFound{ID: 26})
+
+		finished, reason, err := checker.Finished(ctx, &fix)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.False(t, finished)
+		assert.Equal(t, "result does not exist", reason)
+	})
+
+	t.Run("returns error if returned by result client", func(t *testing.T) {
+		t.Helper()
+
+		client := new(MockResultClient)
+		checker := fixture.NewResultStatusChecker(client)
+
+		client.On("ByID", ctx, uint64(26)).Return(nil, errors.New("result client error"))
+
+		_, _, err := checker.Finished(ctx, &fix)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "result client error", err.Error())
+	})
+}
+
+type MockResultClient struct {
+	mock.Mock
+}
+
+func (m *MockResultClient) ByID(ctx context.Context, fixtureID uint64) (*statistico.Result, error) {
+	args := m.Called(ctx, fixtureID)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*statistico.Result), args.Error(1)
+}
+
+func (m *MockResultClient) ByTeam(ctx context.Context, req *statistico.TeamResultRequest) ([]*statistico.Result, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).([]*statistico.Result), args.Error(1)
+}
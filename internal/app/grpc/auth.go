@@ -0,0 +1,180 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/jonboulle/clockwork"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+)
+
+// Authenticator authenticates the credentials sent in the metadata of a request, returning the ID of the client
+// making the request.
+type Authenticator interface {
+	Authenticate(ctx context.Context) (string, error)
+}
+
+type apiKeyAuthenticator struct {
+	keys map[string]string
+}
+
+// Authenticate returns the client identified by the key sent in the x-api-key header.
+func (a *apiKeyAuthenticator) Authenticate(ctx context.Context) (string, error) {
+	key := header(ctx, "x-api-key")
+
+	if key == "" {
+		return "", errors.New("x-api-key header is missing")
+	}
+
+	for client, k := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			return client, nil
+		}
+	}
+
+	return "", errors.New("api key is invalid")
+}
+
+type jwtAuthenticator struct {
+	secret   []byte
+	issuer   string
+	audience string
+	clock    clockwork.Clock
+}
+
+// Authenticate returns the subject of the HMAC signed JWT sent as a bearer token in the authorization header. The
+// token must not have expired and must have the issuer and audience configured, if any.
+func (a *jwtAuthenticator) Authenticate(ctx context.Context) (string, error) {
+	auth := header(ctx, "authorization")
+
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return "", errors.New("authorization header does not contain a bearer token")
+	}
+
+	var claims jwt.RegisteredClaims
+
+	parser := jwt.Parser{ValidMethods: []string{"HS256", "HS384", "HS512"}, SkipClaimsValidation: true}
+
+	_, err := parser.ParseWithClaims(strings.TrimPrefix(auth, "Bearer "), &claims, func(t *jwt.Token) (interface{}, error) {
+		return a.secret, nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	now := a.clock.Now()
+
+	switch {
+	case !claims.VerifyExpiresAt(now, true):
+		return "", errors.New("token has expired or does not have an expiry")
+	case !claims.VerifyNotBefore(now, false):
+		return "", errors.New("token is not valid yet")
+	case !claims.VerifyIssuer(a.issuer, a.issuer != ""):
+		return "", errors.New("token issuer is invalid")
+	case !claims.VerifyAudience(a.audience, a.audience != ""):
+		return "", errors.New("token audience is invalid")
+	case claims.Subject == "":
+		return "", errors.New("token does not have a subject")
+	}
+
+	return claims.Subject, nil
+}
+
+// AuthInterceptor rejects requests that fail authentication with codes.Unauthenticated and adds the authenticated
+// client to the context of requests that pass. Failed attempts count against the rate limit of the peer making
+// them, and peers over the limit are rejected with codes.ResourceExhausted without being authenticated, so
+// credentials cannot be guessed faster than the rate limit allows. Health checks are not authenticated.
+type AuthInterceptor struct {
+	authenticator Authenticator
+	limiter       *RateLimiter
+}
+
+func (a *AuthInterceptor) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if exempt(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := a.authenticate(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (a *AuthInterceptor) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if exempt(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		ctx, err := a.authenticate(ss.Context())
+
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (a *AuthInterceptor) authenticate(ctx context.Context) (context.Context, error) {
+	addr := clientID(ctx)
+
+	if a.limiter != nil && a.limiter.Limited(addr) {
+		return ctx, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+
+	client, err := a.authenticator.Authenticate(ctx)
+
+	if err != nil {
+		if a.limiter != nil {
+			a.limiter.Allow(addr)
+		}
+
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if h, ok := ctx.Value(clientHolderKey{}).(*clientHolder); ok {
+		h.client = client
+	}
+
+	return context.WithValue(ctx, clientKey{}, client), nil
+}
+
+func header(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+
+	if !ok || len(md.Get(key)) == 0 {
+		return ""
+	}
+
+	return md.Get(key)[0]
+}
+
+// NewAPIKeyAuthenticator returns an Authenticator accepting the API keys provided, keyed by the ID of the client each
+// key belongs to.
+func NewAPIKeyAuthenticator(keys map[string]string) Authenticator {
+	return &apiKeyAuthenticator{keys: keys}
+}
+
+// NewJWTAuthenticator returns an Authenticator accepting JWTs signed with the secret provided. Issuer and audience
+// are not verified if empty.
+func NewJWTAuthenticator(secret []byte, issuer, audience string, c clockwork.Clock) Authenticator {
+	return &jwtAuthenticator{secret: secret, issuer: issuer, audience: audience, clock: c}
+}
+
+// NewAuthInterceptor returns an AuthInterceptor authenticating requests with the Authenticator provided. Failed
+// attempts are not limited if l is nil.
+func NewAuthInterceptor(a Authenticator, l *RateLimiter) *AuthInterceptor {
+	return &AuthInterceptor{authenticator: a, limiter: l}
+}
@@ -0,0 +1,290 @@
+package grpc_test
+
+import (
+	"context"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/jonboulle/clockwork"
+	"github.com/statistico/statistico-ratings/internal/app/grpc"
+	"github.com/stretchr/testify/assert"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	gstatus "google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+var unaryInfo = &ggrpc.UnaryServerInfo{FullMethod: "/statistico.TeamRatingService/GetFixtureRatings"}
+
+func TestAPIKeyAuthenticator_Authenticate(t *testing.T) {
+	auth := grpc.NewAPIKeyAuthenticator(map[string]string{"odds-service": "abc123"})
+
+	t.Run("returns the client the api key belongs to", func(t *testing.T) {
+		t.Helper()
+
+		client, err := auth.Authenticate(incoming("x-api-key", "abc123"))
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "odds-service", client)
+	})
+
+	t.Run("returns an error if the api key is invalid", func(t *testing.T) {
+		t.Helper()
+
+		_, err := auth.Authenticate(incoming("x-api-key", "xyz"))
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "api key is invalid", err.Error())
+	})
+
+	t.Run("returns an error if the api key is missing", func(t *testing.T) {
+		t.Helper()
+
+		_, err := auth.Authenticate(context.Background())
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "x-api-key header is missing", err.Error())
+	})
+}
+
+func TestJWTAuthenticator_Authenticate(t *testing.T) {
+	clock := clockwork.NewFakeClockAt(time.Date(2021, 11, 20, 12, 0, 0, 0, time.UTC))
+	auth := grpc.NewJWTAuthenticator([]byte("secret"), "statistico", "statistico-ratings", clock)
+
+	claims := func() jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{
+			Subject:   "odds-service",
+			Issuer:    "statistico",
+			Audience:  jwt.ClaimStrings{"statistico-ratings"},
+			ExpiresAt: jwt.NewNumericDate(clock.Now().Add(time.Hour)),
+		}
+	}
+
+	t.Run("returns the subject of a valid token", func(t *testing.T) {
+		t.Helper()
+
+		client, err := auth.Authenticate(incoming("authorization", "Bearer "+token(t, claims(), "secret")))
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "odds-service", client)
+	})
+
+	t.Run("returns an error if the token is signed with a different secret", func(t *testing.T) {
+		t.Helper()
+
+		_, err := auth.Authenticate(incoming("authorization", "Bearer "+token(t, claims(), "other")))
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "signature is invalid", err.Error())
+	})
+
+	t.Run("returns an error if the token has expired", func(t *testing.T) {
+		t.Helper()
+
+		c := claims()
+		c.ExpiresAt = jwt.NewNumericDate(clock.Now().Add(-time.Minute))
+
+		_, err := auth.Authenticate(incoming("authorization", "Bearer "+token(t, c, "secret")))
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "token has expired or does not have an expiry", err.Error())
+	})
+
+	t.Run("returns an error if the token audience does not match", func(t *testing.T) {
+		t.Helper()
+
+		c := claims()
+		c.Audience = jwt.ClaimStrings{"statistico-odds"}
+
+		_, err := auth.Authenticate(incoming("authorization", "Bearer "+token(t, c, "secret")))
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "token audience is invalid", err.Error())
+	})
+
+	t.Run("returns an error if the authorization header does not contain a bearer token", func(t *testing.T) {
+		t.Helper()
+
+		_, err := auth.Authenticate(incoming("authorization", "Basic b2RkczpzZWNyZXQ="))
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "authorization header does not contain a bearer token", err.Error())
+	})
+}
+
+func TestAuthInterceptor_UnaryServerInterceptor(t *testing.T) {
+	interceptor := grpc.NewAuthInterceptor(grpc.NewAPIKeyAuthenticator(map[string]string{"odds-service": "abc123"}), nil).UnaryServerInterceptor()
+
+	t.Run("adds the authenticated client to the handler context", func(t *testing.T) {
+		t.Helper()
+
+		var client string
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			client, _ = grpc.ClientFromContext(ctx)
+			return "response", nil
+		}
+
+		res, err := interceptor(incoming("x-api-key", "abc123"), "request", unaryInfo, handler)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "response", res)
+		assert.Equal(t, "odds-service", client)
+	})
+
+	t.Run("returns unauthenticated error without calling the handler if authentication fails", func(t *testing.T) {
+		t.Helper()
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			t.Fatal("Expected handler not to be called")
+			return nil, nil
+		}
+
+		_, err := interceptor(incoming("x-api-key", "xyz"), "request", unaryInfo, handler)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, codes.Unauthenticated, gstatus.Code(err))
+	})
+
+	t.Run("does not authenticate health checks", func(t *testing.T) {
+		t.Helper()
+
+		info := &ggrpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "serving", nil
+		}
+
+		res, err := interceptor(context.Background(), "request", info, handler)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "serving", res)
+	})
+}
+
+func TestAuthInterceptor_RateLimit(t *testing.T) {
+	t.Run("rejects peers without authenticating once failed attempts exceed the rate limit", func(t *testing.T) {
+		t.Helper()
+
+		auth := grpc.NewAPIKeyAuthenticator(map[string]string{"odds-service": "abc123"})
+		interceptor := grpc.NewAuthInterceptor(auth, grpc.NewRateLimiter(1, 1, clockwork.NewFakeClock())).UnaryServerInterceptor()
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "response", nil
+		}
+
+		request := func(addr, key string) error {
+			ctx := metadata.NewIncomingContext(fromPeer(addr), metadata.Pairs("x-api-key", key))
+			_, err := interceptor(ctx, "request", unaryInfo, handler)
+			return err
+		}
+
+		for i := 0; i < 2; i++ {
+			if err := request("10.0.0.2:50000", "abc123"); err != nil {
+				t.Fatalf("Expected nil, got %s", err.Error())
+			}
+		}
+
+		assert.Equal(t, codes.Unauthenticated, gstatus.Code(request("10.0.0.1:50000", "xyz")))
+		assert.Equal(t, codes.ResourceExhausted, gstatus.Code(request("10.0.0.1:50001", "abc123")))
+	})
+}
+
+func TestAuthInterceptor_StreamServerInterceptor(t *testing.T) {
+	interceptor := grpc.NewAuthInterceptor(grpc.NewAPIKeyAuthenticator(map[string]string{"odds-service": "abc123"}), nil).StreamServerInterceptor()
+	info := &ggrpc.StreamServerInfo{FullMethod: "/statistico.TeamRatingService/StreamRatings"}
+
+	t.Run("adds the authenticated client to the stream context", func(t *testing.T) {
+		t.Helper()
+
+		var client string
+
+		handler := func(srv interface{}, ss ggrpc.ServerStream) error {
+			client, _ = grpc.ClientFromContext(ss.Context())
+			return nil
+		}
+
+		err := interceptor(nil, &stream{ctx: incoming("x-api-key", "abc123")}, info, handler)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "odds-service", client)
+	})
+
+	t.Run("returns unauthenticated error if authentication fails", func(t *testing.T) {
+		t.Helper()
+
+		handler := func(srv interface{}, ss ggrpc.ServerStream) error {
+			t.Fatal("Expected handler not to be called")
+			return nil
+		}
+
+		err := interceptor(nil, &stream{ctx: context.Background()}, info, handler)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, codes.Unauthenticated, gstatus.Code(err))
+	})
+}
+
+func incoming(key, value string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(key, value))
+}
+
+func token(t *testing.T, c jwt.RegisteredClaims, secret string) string {
+	t.Helper()
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString([]byte(secret))
+
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+
+	return signed
+}
+
+type stream struct {
+	ggrpc.ServerStream
+	ctx context.Context
+}
+
+func (s *stream) Context() context.Context {
+	return s.ctx
+}
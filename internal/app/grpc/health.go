@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"context"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus"
+	"github.com/statistico/statistico-proto/go"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"time"
+)
+
+// Pinger checks a dependency of the gRPC server can be reached. *sql.DB implements Pinger.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// HealthChecker sets the status reported by the gRPC health service from the result of pinging the database, so the
+// server is only reported as serving while team ratings can be read.
+type HealthChecker struct {
+	server   *health.Server
+	db       Pinger
+	interval time.Duration
+	clock    clockwork.Clock
+	logger   *logrus.Logger
+	status   grpc_health_v1.HealthCheckResponse_ServingStatus
+}
+
+// Check pings the database and sets the status of the server and the team rating service. Changes in status are
+// logged.
+func (h *HealthChecker) Check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, h.interval)
+	defer cancel()
+
+	status := grpc_health_v1.HealthCheckResponse_SERVING
+
+	err := h.db.PingContext(ctx)
+
+	if err != nil {
+		status = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	}
+
+	if status != h.status {
+		if err != nil {
+			h.logger.Errorf("Error pinging database in grpc health check: %s", err.Error())
+		} else {
+			h.logger.Info("grpc health check database ping succeeded, serving requests")
+		}
+	}
+
+	h.status = status
+
+	h.server.SetServingStatus("", status)
+	h.server.SetServingStatus(statistico.TeamRatingService_ServiceDesc.ServiceName, status)
+}
+
+// Run checks the database every interval until ctx is cancelled.
+func (h *HealthChecker) Run(ctx context.Context) {
+	for {
+		h.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.clock.After(h.interval):
+		}
+	}
+}
+
+func NewHealthChecker(s *health.Server, db Pinger, interval time.Duration, c clockwork.Clock, l *logrus.Logger) *HealthChecker {
+	return &HealthChecker{
+		server:   s,
+		db:       db,
+		interval: interval,
+		clock:    c,
+		logger:   l,
+		status:   grpc_health_v1.HealthCheckResponse_UNKNOWN,
+	}
+}
@@ -0,0 +1,126 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/statistico/statistico-ratings/internal/app/grpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"testing"
+	"time"
+)
+
+func TestHealthChecker_Check(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reports the server and team rating service as serving if the database can be reached", func(t *testing.T) {
+		t.Helper()
+
+		db := new(MockPinger)
+		server := health.NewServer()
+		logger, _ := test.NewNullLogger()
+
+		checker := grpc.NewHealthChecker(server, db, 10*time.Second, clockwork.NewFakeClock(), logger)
+
+		db.On("PingContext", mock.Anything).Return(nil)
+
+		checker.Check(ctx)
+
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, status(t, server, ""))
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, status(t, server, "statistico.TeamRatingService"))
+	})
+
+	t.Run("reports the server as not serving and logs once if the database cannot be reached", func(t *testing.T) {
+		t.Helper()
+
+		db := new(MockPinger)
+		server := health.NewServer()
+		logger, hook := test.NewNullLogger()
+
+		checker := grpc.NewHealthChecker(server, db, 10*time.Second, clockwork.NewFakeClock(), logger)
+
+		db.On("PingContext", mock.Anything).Return(errors.New("connection refused"))
+
+		checker.Check(ctx)
+		checker.Check(ctx)
+
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, status(t, server, ""))
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, status(t, server, "statistico.TeamRatingService"))
+		assert.Equal(t, 1, len(hook.AllEntries()))
+		assert.Equal(t, "Error pinging database in grpc health check: connection refused", hook.LastEntry().Message)
+	})
+
+	t.Run("does not report the server as serving once shut down", func(t *testing.T) {
+		t.Helper()
+
+		db := new(MockPinger)
+		server := health.NewServer()
+		logger, _ := test.NewNullLogger()
+
+		checker := grpc.NewHealthChecker(server, db, 10*time.Second, clockwork.NewFakeClock(), logger)
+
+		db.On("PingContext", mock.Anything).Return(nil)
+
+		server.Shutdown()
+		checker.Check(ctx)
+
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, status(t, server, ""))
+	})
+}
+
+func TestHealthChecker_Run(t *testing.T) {
+	t.Run("checks the database every interval until the context is cancelled", func(t *testing.T) {
+		t.Helper()
+
+		db := new(MockPinger)
+		server := health.NewServer()
+		clock := clockwork.NewFakeClock()
+		logger, _ := test.NewNullLogger()
+
+		checker := grpc.NewHealthChecker(server, db, 10*time.Second, clock, logger)
+
+		db.On("PingContext", mock.Anything).Return(nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+
+		go func() {
+			checker.Run(ctx)
+			close(done)
+		}()
+
+		clock.BlockUntil(1)
+		clock.Advance(10 * time.Second)
+		clock.BlockUntil(1)
+
+		cancel()
+		<-done
+
+		db.AssertNumberOfCalls(t, "PingContext", 2)
+	})
+}
+
+func status(t *testing.T, s *health.Server, service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	t.Helper()
+
+	res, err := s.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+
+	return res.Status
+}
+
+type MockPinger struct {
+	mock.Mock
+}
+
+func (m *MockPinger) PingContext(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
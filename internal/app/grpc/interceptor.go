@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"net"
+	"strings"
+)
+
+type clientKey struct{}
+
+type clientHolderKey struct{}
+
+// clientHolder records the client authenticated by AuthInterceptor so interceptors chained before it, which do not
+// see the context it passes on, can identify the client once the request has been handled.
+type clientHolder struct {
+	client string
+}
+
+// withClientHolder returns a copy of ctx holding a clientHolder that AuthInterceptor records the authenticated
+// client in.
+func withClientHolder(ctx context.Context) (context.Context, *clientHolder) {
+	h := &clientHolder{}
+	return context.WithValue(ctx, clientHolderKey{}, h), h
+}
+
+// ClientFromContext returns the ID of the authenticated client making a request, if any.
+func ClientFromContext(ctx context.Context) (string, bool) {
+	client, ok := ctx.Value(clientKey{}).(string)
+	return client, ok
+}
+
+// exempt returns true for health check methods, which are called by orchestrators frequently and without
+// credentials so are not authenticated, rate limited or logged.
+func exempt(method string) bool {
+	return strings.HasPrefix(method, "/grpc.health.v1.Health/")
+}
+
+// clientID returns the ID of the authenticated client making a request, falling back to the host of the peer
+// address for unauthenticated requests.
+func clientID(ctx context.Context) string {
+	if client, ok := ClientFromContext(ctx); ok {
+		return client
+	}
+
+	p, ok := peer.FromContext(ctx)
+
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+// contextStream overrides the context of a grpc.ServerStream so stream interceptors can pass values to handlers.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// RequestLogger logs the method, status code, duration and client of each request handled. Failed requests are
+// logged at warning level as the handlers log their own errors. Health checks are not logged. Chained before
+// AuthInterceptor, requests rejected by authentication are logged and the client authenticated for other requests
+// is logged in place of the peer address.
+type RequestLogger struct {
+	logger *logrus.Logger
+	clock  clockwork.Clock
+}
+
+func (r *RequestLogger) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if exempt(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		start := r.clock.Now()
+
+		ctx, holder := withClientHolder(ctx)
+
+		res, err := handler(ctx, req)
+
+		r.log(ctx, holder, info.FullMethod, start, err)
+
+		return res, err
+	}
+}
+
+func (r *RequestLogger) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if exempt(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		start := r.clock.Now()
+
+		ctx, holder := withClientHolder(ss.Context())
+
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+
+		r.log(ctx, holder, info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+func (r *RequestLogger) log(ctx context.Context, h *clientHolder, method string, start time.Time, err error) {
+	code := status.Code(err)
+
+	client := h.client
+
+	if client == "" {
+		client = clientID(ctx)
+	}
+
+	entry := r.logger.WithFields(logrus.Fields{
+		"method":      method,
+		"code":        code.String(),
+		"duration_ms": r.clock.Since(start).Milliseconds(),
+		"client":      client,
+	})
+
+	if code != codes.OK {
+		entry.Warnf("grpc request failed: %s", status.Convert(err).Message())
+		return
+	}
+
+	entry.Info("grpc request handled")
+}
+
+func NewRequestLogger(l *logrus.Logger, c clockwork.Clock) *RequestLogger {
+	return &RequestLogger{logger: l, clock: c}
+}
@@ -0,0 +1,168 @@
+package grpc_test
+
+import (
+	"context"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/statistico/statistico-ratings/internal/app/grpc"
+	"github.com/stretchr/testify/assert"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	gstatus "google.golang.org/grpc/status"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestRequestLogger_UnaryServerInterceptor(t *testing.T) {
+	t.Run("logs the method, code, duration and client of a handled request", func(t *testing.T) {
+		t.Helper()
+
+		logger, hook := test.NewNullLogger()
+		clock := clockwork.NewFakeClock()
+		interceptor := grpc.NewRequestLogger(logger, clock).UnaryServerInterceptor()
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			clock.Advance(250 * time.Millisecond)
+			return "response", nil
+		}
+
+		res, err := interceptor(context.Background(), "request", unaryInfo, handler)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "response", res)
+		assert.Equal(t, 1, len(hook.AllEntries()))
+		assert.Equal(t, logrus.InfoLevel, hook.LastEntry().Level)
+		assert.Equal(t, "grpc request handled", hook.LastEntry().Message)
+		assert.Equal(t, "/statistico.TeamRatingService/GetFixtureRatings", hook.LastEntry().Data["method"])
+		assert.Equal(t, "OK", hook.LastEntry().Data["code"])
+		assert.Equal(t, int64(250), hook.LastEntry().Data["duration_ms"])
+		assert.Equal(t, "unknown", hook.LastEntry().Data["client"])
+	})
+
+	t.Run("logs failed requests as warnings", func(t *testing.T) {
+		t.Helper()
+
+		logger, hook := test.NewNullLogger()
+		interceptor := grpc.NewRequestLogger(logger, clockwork.NewFakeClock()).UnaryServerInterceptor()
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, gstatus.Error(codes.NotFound, "team rating does not exist")
+		}
+
+		_, err := interceptor(context.Background(), "request", unaryInfo, handler)
+
+		assert.Equal(t, codes.NotFound, gstatus.Code(err))
+		assert.Equal(t, 1, len(hook.AllEntries()))
+		assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+		assert.Equal(t, "grpc request failed: team rating does not exist", hook.LastEntry().Message)
+		assert.Equal(t, "NotFound", hook.LastEntry().Data["code"])
+	})
+
+	t.Run("does not log health checks", func(t *testing.T) {
+		t.Helper()
+
+		logger, hook := test.NewNullLogger()
+		interceptor := grpc.NewRequestLogger(logger, clockwork.NewFakeClock()).UnaryServerInterceptor()
+		info := &ggrpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "serving", nil
+		}
+
+		_, err := interceptor(context.Background(), "request", info, handler)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, 0, len(hook.AllEntries()))
+	})
+}
+
+func TestRequestLogger_ChainedBeforeAuthInterceptor(t *testing.T) {
+	auth := grpc.NewAuthInterceptor(grpc.NewAPIKeyAuthenticator(map[string]string{"odds-service": "abc123"}), nil)
+	addr := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000}
+
+	t.Run("logs the client authenticated by the auth interceptor", func(t *testing.T) {
+		t.Helper()
+
+		logger, hook := test.NewNullLogger()
+		interceptor := grpc.NewRequestLogger(logger, clockwork.NewFakeClock()).UnaryServerInterceptor()
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "response", nil
+		}
+
+		chained := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return auth.UnaryServerInterceptor()(ctx, req, unaryInfo, handler)
+		}
+
+		ctx := peer.NewContext(incoming("x-api-key", "abc123"), &peer.Peer{Addr: addr})
+
+		_, err := interceptor(ctx, "request", unaryInfo, chained)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "grpc request handled", hook.LastEntry().Message)
+		assert.Equal(t, "odds-service", hook.LastEntry().Data["client"])
+	})
+
+	t.Run("logs requests rejected by the auth interceptor with the peer address", func(t *testing.T) {
+		t.Helper()
+
+		logger, hook := test.NewNullLogger()
+		interceptor := grpc.NewRequestLogger(logger, clockwork.NewFakeClock()).UnaryServerInterceptor()
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			t.Fatal("Expected handler not to be called")
+			return nil, nil
+		}
+
+		chained := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return auth.UnaryServerInterceptor()(ctx, req, unaryInfo, handler)
+		}
+
+		ctx := peer.NewContext(incoming("x-api-key", "xyz"), &peer.Peer{Addr: addr})
+
+		_, err := interceptor(ctx, "request", unaryInfo, chained)
+
+		assert.Equal(t, codes.Unauthenticated, gstatus.Code(err))
+		assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+		assert.Equal(t, "Unauthenticated", hook.LastEntry().Data["code"])
+		assert.Equal(t, "10.0.0.1", hook.LastEntry().Data["client"])
+	})
+
+	t.Run("logs the client authenticated for a stream", func(t *testing.T) {
+		t.Helper()
+
+		logger, hook := test.NewNullLogger()
+		interceptor := grpc.NewRequestLogger(logger, clockwork.NewFakeClock()).StreamServerInterceptor()
+		info := &ggrpc.StreamServerInfo{FullMethod: "/statistico.TeamRatingService/StreamRatings"}
+
+		handler := func(srv interface{}, ss ggrpc.ServerStream) error {
+			return nil
+		}
+
+		chained := func(srv interface{}, ss ggrpc.ServerStream) error {
+			return auth.StreamServerInterceptor()(srv, ss, info, handler)
+		}
+
+		ctx := peer.NewContext(incoming("x-api-key", "abc123"), &peer.Peer{Addr: addr})
+
+		err := interceptor(nil, &stream{ctx: ctx}, info, chained)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "odds-service", hook.LastEntry().Data["client"])
+	})
+}
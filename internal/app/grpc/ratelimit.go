@@ -0,0 +1,120 @@
+package grpc
+
+import (
+	"context"
+	"github.com/jonboulle/clockwork"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
+	"time"
+)
+
+// idleLimiterTTL is the time after which the limiter of a client that has made no requests is discarded.
+const idleLimiterTTL = 10 * time.Minute
+
+type limiter struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+// RateLimiter limits the rate of requests made by each client using a token bucket, rejecting requests over the
+// limit with codes.ResourceExhausted. Clients are identified by the authenticated client ID, or the peer address
+// for unauthenticated requests, so RateLimiter must be chained after AuthInterceptor. Health checks are not
+// rate limited.
+type RateLimiter struct {
+	limit    rate.Limit
+	burst    int
+	clock    clockwork.Clock
+	mu       sync.Mutex
+	limiters map[string]*limiter
+	pruned   time.Time
+}
+
+// Allow reports whether a request from client is within the rate limit.
+func (r *RateLimiter) Allow(client string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+
+	return r.get(client, now).AllowN(now, 1)
+}
+
+// Limited reports whether client has exhausted its rate limit without counting a request against it.
+func (r *RateLimiter) Limited(client string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+
+	res := r.get(client, now).ReserveN(now, 1)
+	defer res.CancelAt(now)
+
+	return res.DelayFrom(now) > 0
+}
+
+// get returns the limiter of client, creating it if it does not exist and discarding limiters of idle clients.
+func (r *RateLimiter) get(client string, now time.Time) *rate.Limiter {
+	if now.Sub(r.pruned) > idleLimiterTTL {
+		for id, l := range r.limiters {
+			if now.Sub(l.seen) > idleLimiterTTL {
+				delete(r.limiters, id)
+			}
+		}
+
+		r.pruned = now
+	}
+
+	l, ok := r.limiters[client]
+
+	if !ok {
+		l = &limiter{limiter: rate.NewLimiter(r.limit, r.burst)}
+		r.limiters[client] = l
+	}
+
+	l.seen = now
+
+	return l.limiter
+}
+
+func (r *RateLimiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := r.check(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (r *RateLimiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := r.check(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func (r *RateLimiter) check(ctx context.Context, method string) error {
+	if exempt(method) || r.Allow(clientID(ctx)) {
+		return nil
+	}
+
+	return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+}
+
+// NewRateLimiter returns a RateLimiter allowing each client perSecond requests a second on average, with bursts
+// of up to burst requests.
+func NewRateLimiter(perSecond float64, burst int, c clockwork.Clock) *RateLimiter {
+	return &RateLimiter{
+		limit:    rate.Limit(perSecond),
+		burst:    burst,
+		clock:    c,
+		limiters: map[string]*limiter{},
+		pruned:   c.Now(),
+	}
+}
@@ -0,0 +1,112 @@
+package grpc_test
+
+import (
+	"context"
+	"github.com/jonboulle/clockwork"
+	"github.com/statistico/statistico-ratings/internal/app/grpc"
+	"github.com/stretchr/testify/assert"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	gstatus "google.golang.org/grpc/status"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	t.Run("allows bursts up to the limit and refills over time", func(t *testing.T) {
+		t.Helper()
+
+		clock := clockwork.NewFakeClock()
+		limiter := grpc.NewRateLimiter(1, 2, clock)
+
+		assert.True(t, limiter.Allow("odds-service"))
+		assert.True(t, limiter.Allow("odds-service"))
+		assert.False(t, limiter.Allow("odds-service"))
+
+		clock.Advance(time.Second)
+
+		assert.True(t, limiter.Allow("odds-service"))
+		assert.False(t, limiter.Allow("odds-service"))
+	})
+
+	t.Run("limits each client separately", func(t *testing.T) {
+		t.Helper()
+
+		limiter := grpc.NewRateLimiter(1, 1, clockwork.NewFakeClock())
+
+		assert.True(t, limiter.Allow("odds-service"))
+		assert.False(t, limiter.Allow("odds-service"))
+		assert.True(t, limiter.Allow("data-service"))
+	})
+}
+
+func TestRateLimiter_Limited(t *testing.T) {
+	t.Run("reports whether the client is over the limit without counting a request", func(t *testing.T) {
+		t.Helper()
+
+		limiter := grpc.NewRateLimiter(1, 1, clockwork.NewFakeClock())
+
+		assert.False(t, limiter.Limited("odds-service"))
+		assert.False(t, limiter.Limited("odds-service"))
+		assert.True(t, limiter.Allow("odds-service"))
+		assert.True(t, limiter.Limited("odds-service"))
+		assert.False(t, limiter.Limited("data-service"))
+	})
+}
+
+func TestRateLimiter_UnaryServerInterceptor(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	}
+
+	t.Run("returns resource exhausted error once the peer exceeds the limit", func(t *testing.T) {
+		t.Helper()
+
+		interceptor := grpc.NewRateLimiter(1, 1, clockwork.NewFakeClock()).UnaryServerInterceptor()
+
+		first := fromPeer("10.0.0.1:50000")
+		second := fromPeer("10.0.0.1:50001")
+
+		res, err := interceptor(first, "request", unaryInfo, handler)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "response", res)
+
+		_, err = interceptor(second, "request", unaryInfo, handler)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, codes.ResourceExhausted, gstatus.Code(err))
+
+		_, err = interceptor(fromPeer("10.0.0.2:50000"), "request", unaryInfo, handler)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+	})
+
+	t.Run("does not rate limit health checks", func(t *testing.T) {
+		t.Helper()
+
+		interceptor := grpc.NewRateLimiter(1, 1, clockwork.NewFakeClock()).UnaryServerInterceptor()
+		info := &ggrpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+
+		for i := 0; i < 3; i++ {
+			if _, err := interceptor(fromPeer("10.0.0.1:50000"), "request", info, handler); err != nil {
+				t.Fatalf("Expected nil, got %s", err.Error())
+			}
+		}
+	})
+}
+
+func fromPeer(addr string) context.Context {
+	a, _ := net.ResolveTCPAddr("tcp", addr)
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: a})
+}
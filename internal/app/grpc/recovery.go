@@ -0,0 +1,48 @@
+package grpc
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"runtime/debug"
+)
+
+// PanicRecoverer recovers panics raised by handlers, logging the panic and stack trace and returning
+// codes.Internal to the client in place of crashing the server.
+type PanicRecoverer struct {
+	logger *logrus.Logger
+}
+
+func (p *PanicRecoverer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+		defer p.recover(info.FullMethod, &err)
+
+		return handler(ctx, req)
+	}
+}
+
+func (p *PanicRecoverer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer p.recover(info.FullMethod, &err)
+
+		return handler(srv, ss)
+	}
+}
+
+func (p *PanicRecoverer) recover(method string, err *error) {
+	r := recover()
+
+	if r == nil {
+		return
+	}
+
+	p.logger.WithField("stack", string(debug.Stack())).Errorf("Panic handling grpc request %s: %v", method, r)
+
+	*err = status.Error(codes.Internal, "internal server error")
+}
+
+func NewPanicRecoverer(l *logrus.Logger) *PanicRecoverer {
+	return &PanicRecoverer{logger: l}
+}
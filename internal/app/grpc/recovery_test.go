@@ -0,0 +1,78 @@
+package grpc_test
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/statistico/statistico-ratings/internal/app/grpc"
+	"github.com/stretchr/testify/assert"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestPanicRecoverer_UnaryServerInterceptor(t *testing.T) {
+	t.Run("returns internal error and logs the panic if the handler panics", func(t *testing.T) {
+		t.Helper()
+
+		logger, hook := test.NewNullLogger()
+		interceptor := grpc.NewPanicRecoverer(logger).UnaryServerInterceptor()
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			panic("nil rating")
+		}
+
+		res, err := interceptor(context.Background(), "request", unaryInfo, handler)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Nil(t, res)
+		assert.Equal(t, codes.Internal, gstatus.Code(err))
+		assert.Equal(t, "internal server error", gstatus.Convert(err).Message())
+		assert.Equal(t, 1, len(hook.AllEntries()))
+		assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+		assert.Equal(t, "Panic handling grpc request /statistico.TeamRatingService/GetFixtureRatings: nil rating", hook.LastEntry().Message)
+		assert.Contains(t, hook.LastEntry().Data["stack"], "recovery_test.go")
+	})
+
+	t.Run("returns the handler response if the handler does not panic", func(t *testing.T) {
+		t.Helper()
+
+		logger, hook := test.NewNullLogger()
+		interceptor := grpc.NewPanicRecoverer(logger).UnaryServerInterceptor()
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "response", nil
+		}
+
+		res, err := interceptor(context.Background(), "request", unaryInfo, handler)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, "response", res)
+		assert.Equal(t, 0, len(hook.AllEntries()))
+	})
+}
+
+func TestPanicRecoverer_StreamServerInterceptor(t *testing.T) {
+	t.Run("returns internal error if the handler panics", func(t *testing.T) {
+		t.Helper()
+
+		logger, _ := test.NewNullLogger()
+		interceptor := grpc.NewPanicRecoverer(logger).StreamServerInterceptor()
+		info := &ggrpc.StreamServerInfo{FullMethod: "/statistico.TeamRatingService/StreamRatings"}
+
+		handler := func(srv interface{}, ss ggrpc.ServerStream) error {
+			panic("nil rating")
+		}
+
+		err := interceptor(nil, &stream{ctx: context.Background()}, info, handler)
+
+		assert.Equal(t, codes.Internal, gstatus.Code(err))
+	})
+}
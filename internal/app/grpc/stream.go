@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"google.golang.org/grpc"
+)
+
+// TeamRatingStreamServer is the server API of the TeamRatingStreamService. statistico-proto does not define a
+// streaming RPC for team ratings, so the service is described by TeamRatingStreamServiceDesc and uses the request
+// and TeamRating messages statistico-proto already has.
+type TeamRatingStreamServer interface {
+	// StreamTeamRatings sends ratings for the team requested as they are persisted, or ratings for every team if
+	// the team ID is 0.
+	StreamTeamRatings(r *statistico.TeamRequest, s grpc.ServerStream) error
+	// StreamCompetitionRatings sends ratings for fixtures in the competition requested as they are persisted.
+	StreamCompetitionRatings(r *statistico.SeasonCompetitionRequest, s grpc.ServerStream) error
+}
+
+// TeamRatingStreamServiceDesc describes the statistico.TeamRatingStreamService server-streaming RPCs.
+var TeamRatingStreamServiceDesc = grpc.ServiceDesc{
+	ServiceName: "statistico.TeamRatingStreamService",
+	HandlerType: (*TeamRatingStreamServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamTeamRatings",
+			Handler:       streamTeamRatingsHandler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamCompetitionRatings",
+			Handler:       streamCompetitionRatingsHandler,
+			ServerStreams: true,
+		},
+	},
+}
+
+// RegisterTeamRatingStreamServer registers the TeamRatingStreamService with the server provided.
+func RegisterTeamRatingStreamServer(s *grpc.Server, srv TeamRatingStreamServer) {
+	s.RegisterService(&TeamRatingStreamServiceDesc, srv)
+}
+
+func streamTeamRatingsHandler(srv interface{}, stream grpc.ServerStream) error {
+	var r statistico.TeamRequest
+
+	if err := stream.RecvMsg(&r); err != nil {
+		return err
+	}
+
+	return srv.(TeamRatingStreamServer).StreamTeamRatings(&r, stream)
+}
+
+func streamCompetitionRatingsHandler(srv interface{}, stream grpc.ServerStream) error {
+	var r statistico.SeasonCompetitionRequest
+
+	if err := stream.RecvMsg(&r); err != nil {
+		return err
+	}
+
+	return srv.(TeamRatingStreamServer).StreamCompetitionRatings(&r, stream)
+}
+
+// TeamRatingStreamService sends ratings published by a team.RatingStream to clients until they disconnect or the
+// service is shut down.
+type TeamRatingStreamService struct {
+	stream team.RatingStream
+	logger *logrus.Logger
+	done   chan struct{}
+}
+
+func (t *TeamRatingStreamService) StreamTeamRatings(r *statistico.TeamRequest, s grpc.ServerStream) error {
+	var q team.StreamQuery
+
+	if r.GetTeamId() != 0 {
+		id := r.GetTeamId()
+		q.TeamID = &id
+	}
+
+	return t.send(&q, s)
+}
+
+func (t *TeamRatingStreamService) StreamCompetitionRatings(r *statistico.SeasonCompetitionRequest, s grpc.ServerStream) error {
+	id := r.GetCompetitionId()
+
+	return t.send(&team.StreamQuery{CompetitionID: &id}, s)
+}
+
+// Shutdown ends every stream in progress, and any started afterwards, so graceful shutdown of the server is not held
+// up by clients that stay subscribed.
+func (t *TeamRatingStreamService) Shutdown() {
+	close(t.done)
+}
+
+func (t *TeamRatingStreamService) send(q *team.StreamQuery, s grpc.ServerStream) error {
+	ratings := t.stream.Subscribe(s.Context(), q)
+
+	for {
+		select {
+		case <-t.done:
+			return nil
+		case rt, ok := <-ratings:
+			if !ok {
+				return s.Context().Err()
+			}
+
+			if err := s.SendMsg(teamRating(rt)); err != nil {
+				t.logger.Warnf("Error sending team rating for team %d and fixture %d: %s", rt.TeamID, rt.FixtureID, err.Error())
+				return err
+			}
+		}
+	}
+}
+
+func NewTeamRatingStreamService(s team.RatingStream, l *logrus.Logger) *TeamRatingStreamService {
+	return &TeamRatingStreamService{stream: s, logger: l, done: make(chan struct{})}
+}
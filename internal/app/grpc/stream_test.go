@@ -0,0 +1,228 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app/grpc"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTeamRatingStreamService_StreamTeamRatings(t *testing.T) {
+	t.Run("sends ratings for the team requested until the client disconnects", func(t *testing.T) {
+		t.Helper()
+
+		ratings := make(chan *team.Rating, 1)
+		ratingStream := &fakeRatingStream{ratings: ratings}
+		logger, _ := test.NewNullLogger()
+
+		service := grpc.NewTeamRatingStreamService(ratingStream, logger)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ss := &sendStream{stream: stream{ctx: ctx}, sent: make(chan interface{}, 1)}
+
+		errs := make(chan error)
+
+		go func() {
+			errs <- service.StreamTeamRatings(&statistico.TeamRequest{TeamId: 5}, ss)
+		}()
+
+		ratings <- &team.Rating{TeamID: 5, FixtureID: 10, FixtureDate: time.Unix(1627226510, 0)}
+
+		sent := receiveMessage(t, ss.sent).(*statistico.TeamRating)
+
+		assert.Equal(t, uint64(5), sent.TeamId)
+		assert.Equal(t, uint64(10), sent.FixtureId)
+		assert.Equal(t, uint64(5), *ratingStream.query.TeamID)
+		assert.Nil(t, ratingStream.query.CompetitionID)
+
+		cancel()
+		close(ratings)
+
+		assert.Equal(t, context.Canceled, receiveError(t, errs))
+	})
+
+	t.Run("subscribes to ratings for every team if team ID is 0", func(t *testing.T) {
+		t.Helper()
+
+		ratingStream := &fakeRatingStream{ratings: make(chan *team.Rating)}
+		logger, _ := test.NewNullLogger()
+
+		service := grpc.NewTeamRatingStreamService(ratingStream, logger)
+		service.Shutdown()
+
+		err := service.StreamTeamRatings(&statistico.TeamRequest{}, &sendStream{stream: stream{ctx: context.Background()}})
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &team.StreamQuery{}, ratingStream.query)
+	})
+
+	t.Run("logs and returns error if rating cannot be sent", func(t *testing.T) {
+		t.Helper()
+
+		ratings := make(chan *team.Rating, 1)
+		logger, hook := test.NewNullLogger()
+
+		service := grpc.NewTeamRatingStreamService(&fakeRatingStream{ratings: ratings}, logger)
+
+		ratings <- &team.Rating{TeamID: 5, FixtureID: 10}
+
+		ss := &sendStream{stream: stream{ctx: context.Background()}, err: errors.New("transport is closing")}
+
+		err := service.StreamTeamRatings(&statistico.TeamRequest{TeamId: 5}, ss)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "transport is closing", err.Error())
+		assert.Equal(t, "Error sending team rating for team 5 and fixture 10: transport is closing", hook.LastEntry().Message)
+	})
+}
+
+func TestTeamRatingStreamService_StreamCompetitionRatings(t *testing.T) {
+	t.Run("subscribes to ratings for the competition requested", func(t *testing.T) {
+		t.Helper()
+
+		ratingStream := &fakeRatingStream{ratings: make(chan *team.Rating)}
+		logger, _ := test.NewNullLogger()
+
+		service := grpc.NewTeamRatingStreamService(ratingStream, logger)
+		service.Shutdown()
+
+		req := statistico.SeasonCompetitionRequest{CompetitionId: 8}
+
+		err := service.StreamCompetitionRatings(&req, &sendStream{stream: stream{ctx: context.Background()}})
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Nil(t, ratingStream.query.TeamID)
+		assert.Equal(t, uint64(8), *ratingStream.query.CompetitionID)
+	})
+}
+
+func TestTeamRatingStreamService_Shutdown(t *testing.T) {
+	t.Run("ends streams in progress", func(t *testing.T) {
+		t.Helper()
+
+		logger, _ := test.NewNullLogger()
+
+		service := grpc.NewTeamRatingStreamService(&fakeRatingStream{ratings: make(chan *team.Rating)}, logger)
+
+		errs := make(chan error)
+
+		go func() {
+			errs <- service.StreamTeamRatings(&statistico.TeamRequest{}, &sendStream{stream: stream{ctx: context.Background()}})
+		}()
+
+		service.Shutdown()
+
+		assert.Nil(t, receiveError(t, errs))
+	})
+}
+
+func TestTeamRatingStreamServiceDesc(t *testing.T) {
+	t.Run("decodes requests and calls the matching stream method", func(t *testing.T) {
+		t.Helper()
+
+		ratingStream := &fakeRatingStream{ratings: make(chan *team.Rating)}
+		logger, _ := test.NewNullLogger()
+
+		service := grpc.NewTeamRatingStreamService(ratingStream, logger)
+		service.Shutdown()
+
+		desc := grpc.TeamRatingStreamServiceDesc
+
+		assert.Equal(t, "statistico.TeamRatingStreamService", desc.ServiceName)
+		assert.Equal(t, "StreamTeamRatings", desc.Streams[0].StreamName)
+		assert.Equal(t, "StreamCompetitionRatings", desc.Streams[1].StreamName)
+
+		ss := &sendStream{stream: stream{ctx: context.Background()}, request: &statistico.TeamRequest{TeamId: 5}}
+
+		if err := desc.Streams[0].Handler(service, ss); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, uint64(5), *ratingStream.query.TeamID)
+
+		ss = &sendStream{stream: stream{ctx: context.Background()}, request: &statistico.SeasonCompetitionRequest{CompetitionId: 8}}
+
+		if err := desc.Streams[1].Handler(service, ss); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, uint64(8), *ratingStream.query.CompetitionID)
+	})
+}
+
+type fakeRatingStream struct {
+	ratings chan *team.Rating
+	query   *team.StreamQuery
+}
+
+func (f *fakeRatingStream) Subscribe(ctx context.Context, q *team.StreamQuery) <-chan *team.Rating {
+	f.query = q
+	return f.ratings
+}
+
+// sendStream is a server stream receiving request and recording the messages sent, or returning err if set.
+type sendStream struct {
+	stream
+	request interface{}
+	sent    chan interface{}
+	err     error
+}
+
+func (s *sendStream) RecvMsg(m interface{}) error {
+	switch r := m.(type) {
+	case *statistico.TeamRequest:
+		r.TeamId = s.request.(*statistico.TeamRequest).TeamId
+	case *statistico.SeasonCompetitionRequest:
+		r.CompetitionId = s.request.(*statistico.SeasonCompetitionRequest).CompetitionId
+	}
+
+	return nil
+}
+
+func (s *sendStream) SendMsg(m interface{}) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.sent <- m
+
+	return nil
+}
+
+func receiveMessage(t *testing.T, c <-chan interface{}) interface{} {
+	t.Helper()
+
+	select {
+	case m := <-c:
+		return m
+	case <-time.After(time.Second):
+		t.Fatal("Expected message to be sent, got none")
+		return nil
+	}
+}
+
+func receiveError(t *testing.T, c <-chan error) error {
+	t.Helper()
+
+	select {
+	case err := <-c:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("Expected stream to end, it did not")
+		return nil
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
-	"time"
 )
 
 type TeamRatingService struct {
@@ -24,7 +23,7 @@ func (t *TeamRatingService) GetTeamRatings(ctx context.Context, r *statistico.Te
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	ratings, err := t.reader.Get(q)
+	ratings, err := t.reader.Get(ctx, q)
 
 	if err != nil {
 		t.logger.Errorf("Error fetching team ratings: %s", err.Error())
@@ -34,49 +33,43 @@ func (t *TeamRatingService) GetTeamRatings(ctx context.Context, r *statistico.Te
 	res := statistico.TeamRatingResponse{}
 
 	for _, rt := range ratings {
-		st := statistico.TeamRating{
-			TeamId:    rt.TeamID,
-			FixtureId: rt.FixtureID,
-			SeasonId:  rt.SeasonID,
-			Attack: &statistico.Points{
-				Points:     float32(rt.Attack.Total),
-				Difference: float32(rt.Attack.Difference),
-			},
-			Defence: &statistico.Points{
-				Points:     float32(rt.Defence.Total),
-				Difference: float32(rt.Defence.Difference),
-			},
-			FixtureDate: timestamppb.New(rt.FixtureDate),
-			Timestamp:   timestamppb.New(rt.Timestamp),
-		}
-
-		res.Ratings = append(res.Ratings, &st)
+		res.Ratings = append(res.Ratings, teamRating(rt))
 	}
 
 	return &res, nil
 }
 
-func buildTeamReaderQuery(r *statistico.TeamRatingRequest) (*team.ReaderQuery, error) {
-	q := team.ReaderQuery{
-		TeamID: &r.TeamId,
-		Sort:   r.Sort,
+func teamRating(rt *team.Rating) *statistico.TeamRating {
+	return &statistico.TeamRating{
+		TeamId:    rt.TeamID,
+		FixtureId: rt.FixtureID,
+		SeasonId:  rt.SeasonID,
+		Attack: &statistico.Points{
+			Points:     float32(rt.Attack.Total),
+			Difference: float32(rt.Attack.Difference),
+		},
+		Defence: &statistico.Points{
+			Points:     float32(rt.Defence.Total),
+			Difference: float32(rt.Defence.Difference),
+		},
+		FixtureDate: timestamppb.New(rt.FixtureDate),
+		Timestamp:   timestamppb.New(rt.Timestamp),
 	}
+}
+
+func buildTeamReaderQuery(r *statistico.TeamRatingRequest) (*team.ReaderQuery, error) {
+	var seasonID *uint64
+	var before *string
 
 	if r.SeasonId != nil {
-		q.SeasonID = &r.SeasonId.Value
+		seasonID = &r.SeasonId.Value
 	}
 
 	if r.DateBefore != nil {
-		t, err := time.Parse(time.RFC3339, r.DateBefore.Value)
-
-		if err != nil {
-			return nil, err
-		}
-
-		q.Before = &t
+		before = &r.DateBefore.Value
 	}
 
-	return &q, nil
+	return team.NewReaderQuery(r.TeamId, seasonID, before, r.Sort)
 }
 
 func NewTeamRatingService(r team.RatingReader, l *logrus.Logger) *TeamRatingService {
@@ -11,11 +11,15 @@ import (
 	"github.com/statistico/statistico-ratings/internal/app/team"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
 	"testing"
 	"time"
 )
 
 func TestTeamRatingService_GetTeamRatings(t *testing.T) {
+	ctx := context.Background()
+
 	t.Run("calls team rating reader and returns statistico team rating response", func(t *testing.T) {
 		t.Helper()
 
@@ -74,9 +78,9 @@ func TestTeamRatingService_GetTeamRatings(t *testing.T) {
 			return true
 		})
 
-		reader.On("Get", query).Return(ratings, nil)
+		reader.On("Get", ctx, query).Return(ratings, nil)
 
-		res, err := service.GetTeamRatings(context.Background(), &req)
+		res, err := service.GetTeamRatings(ctx, &req)
 
 		if err != nil {
 			t.Fatalf("Expected nil, got %s", err.Error())
@@ -127,7 +131,7 @@ func TestTeamRatingService_GetTeamRatings(t *testing.T) {
 
 		reader.AssertNotCalled(t, "Get")
 
-		_, err := service.GetTeamRatings(context.Background(), &req)
+		_, err := service.GetTeamRatings(ctx, &req)
 
 		if err == nil {
 			t.Fatal("Expected error, got nil")
@@ -138,6 +142,30 @@ func TestTeamRatingService_GetTeamRatings(t *testing.T) {
 		a.Equal("rpc error: code = InvalidArgument desc = parsing time \"hello\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"hello\" as \"2006\"", err.Error())
 	})
 
+	t.Run("returns an invalid argument error if sort provided in request is not supported", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, _ := test.NewNullLogger()
+
+		service := grpc.NewTeamRatingService(reader, logger)
+
+		req := statistico.TeamRatingRequest{
+			TeamId: 5,
+			Sort:   "points_desc",
+		}
+
+		_, err := service.GetTeamRatings(ctx, &req)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, codes.InvalidArgument, gstatus.Code(err))
+		assert.Equal(t, "rpc error: code = InvalidArgument desc = sort \"points_desc\" is not supported, use timestamp_asc or timestamp_desc", err.Error())
+		reader.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	})
+
 	t.Run("logs error and returns internal server error if error returned by team rating reader", func(t *testing.T) {
 		t.Helper()
 
@@ -161,9 +189,9 @@ func TestTeamRatingService_GetTeamRatings(t *testing.T) {
 			return true
 		})
 
-		reader.On("Get", query).Return([]*team.Rating{}, errors.New("oh no"))
+		reader.On("Get", ctx, query).Return([]*team.Rating{}, errors.New("oh no"))
 
-		_, err := service.GetTeamRatings(context.Background(), &req)
+		_, err := service.GetTeamRatings(ctx, &req)
 
 		if err == nil {
 			t.Fatal("Expected error, got nil")
@@ -182,12 +210,22 @@ type MockTeamRatingReader struct {
 	mock.Mock
 }
 
-func (m *MockTeamRatingReader) Get(q *team.ReaderQuery) ([]*team.Rating, error) {
-	args := m.Called(q)
+func (m *MockTeamRatingReader) LatestByTeams(ctx context.Context, teamIDs []uint64, before *time.Time) ([]*team.Rating, error) {
+	args := m.Called(ctx, teamIDs, before)
+	return args.Get(0).([]*team.Rating), args.Error(1)
+}
+
+func (m *MockTeamRatingReader) ByFixture(ctx context.Context, fixtureID uint64) ([]*team.Rating, error) {
+	args := m.Called(ctx, fixtureID)
+	return args.Get(0).([]*team.Rating), args.Error(1)
+}
+
+func (m *MockTeamRatingReader) Get(ctx context.Context, q *team.ReaderQuery) ([]*team.Rating, error) {
+	args := m.Called(ctx, q)
 	return args.Get(0).([]*team.Rating), args.Error(1)
 }
 
-func (m *MockTeamRatingReader) Latest(teamID uint64) (*team.Rating, error) {
-	args := m.Called(teamID)
+func (m *MockTeamRatingReader) Latest(ctx context.Context, teamID uint64) (*team.Rating, error) {
+	args := m.Called(ctx, teamID)
 	return args.Get(0).(*team.Rating), args.Error(1)
 }
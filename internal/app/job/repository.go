@@ -0,0 +1,215 @@
+package job
+
+import (
+	"context"
+	"database/sql"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jonboulle/clockwork"
+	_ "github.com/lib/pq"
+	"github.com/statistico/statistico-ratings/internal/app"
+	"time"
+)
+
+// Repository persists rating jobs and the status of each fixture processed by a job.
+type Repository interface {
+	// Create inserts a running Job for the competition and season with each fixture provided marked as pending.
+	Create(ctx context.Context, competitionID, seasonID uint64, fixtureIDs []uint64) (*Job, error)
+	// Incomplete returns the most recent running Job for the competition and season. An app.JobNotFoundError is
+	// returned if one does not exist.
+	Incomplete(ctx context.Context, competitionID, seasonID uint64) (*Job, error)
+	// Fixtures returns each fixture recorded against a Job ordered by fixture ID.
+	Fixtures(ctx context.Context, jobID uint64) ([]*Fixture, error)
+	// Update inserts or updates the status of a fixture for a Job.
+	Update(ctx context.Context, f *Fixture) error
+	// Complete marks a Job as complete.
+	Complete(ctx context.Context, jobID uint64) error
+	// Failed returns every failed fixture across all jobs ordered by job and fixture ID.
+	Failed(ctx context.Context) ([]*Fixture, error)
+}
+
+type repository struct {
+	connection *sql.DB
+	clock      clockwork.Clock
+}
+
+func (r *repository) Create(ctx context.Context, competitionID, seasonID uint64, fixtureIDs []uint64) (*Job, error) {
+	now := r.clock.Now()
+
+	tx, err := r.connection.BeginTx(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	b := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).RunWith(tx)
+
+	var id uint64
+
+	err = b.
+		Insert("rating_job").
+		Columns("competition_id", "season_id", "status", "created_at", "updated_at").
+		Values(competitionID, seasonID, StatusRunning, now.Unix(), now.Unix()).
+		Suffix("RETURNING id").
+		QueryRowContext(ctx).
+		Scan(&id)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if len(fixtureIDs) > 0 {
+		query := b.
+			Insert("rating_job_fixture").
+			Columns("job_id", "fixture_id", "status", "updated_at").
+			Suffix("ON CONFLICT (job_id, fixture_id) DO NOTHING")
+
+		for _, fixtureID := range fixtureIDs {
+			query = query.Values(id, fixtureID, FixturePending, now.Unix())
+		}
+
+		if _, err := query.ExecContext(ctx); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &Job{
+		ID:            id,
+		CompetitionID: competitionID,
+		SeasonID:      seasonID,
+		Status:        StatusRunning,
+		CreatedAt:     time.Unix(now.Unix(), 0),
+		UpdatedAt:     time.Unix(now.Unix(), 0),
+	}, nil
+}
+
+func (r *repository) Incomplete(ctx context.Context, competitionID, seasonID uint64) (*Job, error) {
+	var j Job
+	var created, updated int64
+
+	err := queryBuilder(r.connection).
+		Select("id", "competition_id", "season_id", "status", "created_at", "updated_at").
+		From("rating_job").
+		Where(sq.Eq{"competition_id": competitionID}).
+		Where(sq.Eq{"season_id": seasonID}).
+		Where(sq.Eq{"status": StatusRunning}).
+		OrderBy("id DESC").
+		Limit(1).
+		QueryRowContext(ctx).
+		Scan(&j.ID, &j.CompetitionID, &j.SeasonID, &j.Status, &created, &updated)
+
+	if err == sql.ErrNoRows {
+		return nil, &app.JobNotFoundError{CompetitionID: competitionID, SeasonID: seasonID}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	j.CreatedAt = time.Unix(created, 0)
+	j.UpdatedAt = time.Unix(updated, 0)
+
+	return &j, nil
+}
+
+func (r *repository) Fixtures(ctx context.Context, jobID uint64) ([]*Fixture, error) {
+	rows, err := selectFixtures(r.connection).
+		Where(sq.Eq{"job_id": jobID}).
+		OrderBy("fixture_id ASC").
+		QueryContext(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rowsToFixtureSlice(rows)
+}
+
+func (r *repository) Update(ctx context.Context, f *Fixture) error {
+	var message, reason interface{}
+
+	if f.Error != "" {
+		message = f.Error
+	}
+
+	if f.Reason != "" {
+		reason = f.Reason
+	}
+
+	_, err := queryBuilder(r.connection).
+		Insert("rating_job_fixture").
+		Columns("job_id", "fixture_id", "status", "error", "reason", "updated_at").
+		Values(f.JobID, f.FixtureID, f.Status, message, reason, r.clock.Now().Unix()).
+		Suffix("ON CONFLICT (job_id, fixture_id) DO UPDATE SET status = EXCLUDED.status, error = EXCLUDED.error, " +
+			"reason = EXCLUDED.reason, updated_at = EXCLUDED.updated_at").
+		ExecContext(ctx)
+
+	return err
+}
+
+func (r *repository) Complete(ctx context.Context, jobID uint64) error {
+	_, err := queryBuilder(r.connection).
+		Update("rating_job").
+		Set("status", StatusComplete).
+		Set("updated_at", r.clock.Now().Unix()).
+		Where(sq.Eq{"id": jobID}).
+		ExecContext(ctx)
+
+	return err
+}
+
+func (r *repository) Failed(ctx context.Context) ([]*Fixture, error) {
+	rows, err := selectFixtures(r.connection).
+		Where(sq.Eq{"status": FixtureFailed}).
+		OrderBy("job_id ASC", "fixture_id ASC").
+		QueryContext(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rowsToFixtureSlice(rows)
+}
+
+func selectFixtures(c *sql.DB) sq.SelectBuilder {
+	return queryBuilder(c).
+		Select("job_id", "fixture_id", "status", "error", "reason", "updated_at").
+		From("rating_job_fixture")
+}
+
+func rowsToFixtureSlice(rows *sql.Rows) ([]*Fixture, error) {
+	defer rows.Close()
+
+	var fixtures []*Fixture
+
+	for rows.Next() {
+		var f Fixture
+		var message, reason sql.NullString
+		var updated int64
+
+		if err := rows.Scan(&f.JobID, &f.FixtureID, &f.Status, &message, &reason, &updated); err != nil {
+			return nil, err
+		}
+
+		f.Error = message.String
+		f.Reason = reason.String
+		f.UpdatedAt = time.Unix(updated, 0)
+
+		fixtures = append(fixtures, &f)
+	}
+
+	return fixtures, rows.Err()
+}
+
+func queryBuilder(c *sql.DB) sq.StatementBuilderType {
+	return sq.StatementBuilder.PlaceholderFormat(sq.Dollar).RunWith(c)
+}
+
+func NewRepository(c *sql.DB, cl clockwork.Clock) Repository {
+	return &repository{connection: c, clock: cl}
+}
@@ -0,0 +1,139 @@
+package job_test
+
+import (
+	"context"
+	"github.com/jonboulle/clockwork"
+	"github.com/statistico/statistico-ratings/internal/app"
+	"github.com/statistico/statistico-ratings/internal/app/job"
+	"github.com/statistico/statistico-ratings/internal/app/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRepository(t *testing.T) {
+	conn, cleanUp := test.GetConnection(t, []string{"rating_job_fixture", "rating_job"})
+	clock := clockwork.NewFakeClockAt(time.Unix(1638820800, 0))
+	repo := job.NewRepository(conn, clock)
+	ctx := context.Background()
+
+	t.Run("creates a job with pending fixtures and returns it as incomplete", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		created, err := repo.Create(ctx, 8, 17420, []uint64{3, 1, 2})
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		fetched, err := repo.Incomplete(ctx, 8, 17420)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		a := assert.New(t)
+
+		a.Equal(created, fetched)
+		a.Equal(job.StatusRunning, fetched.Status)
+		a.Equal(int64(1638820800), fetched.CreatedAt.Unix())
+
+		fixtures, err := repo.Fixtures(ctx, created.ID)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		a.Equal(3, len(fixtures))
+		a.Equal(uint64(1), fixtures[0].FixtureID)
+		a.Equal(job.FixturePending, fixtures[0].Status)
+		a.Equal(uint64(3), fixtures[2].FixtureID)
+	})
+
+	t.Run("returns a JobNotFoundError if an incomplete job does not exist", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		created, err := repo.Create(ctx, 8, 17420, []uint64{1})
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		if err := repo.Complete(ctx, created.ID); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		_, err = repo.Incomplete(ctx, 8, 17420)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.IsType(t, &app.JobNotFoundError{}, err)
+		assert.Equal(t, "incomplete job for competition 8 and season 17420 does not exist", err.Error())
+	})
+
+	t.Run("updates fixture statuses and returns failed fixtures", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		created, err := repo.Create(ctx, 8, 17420, []uint64{1, 2})
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		updates := []*job.Fixture{
+			{JobID: created.ID, FixtureID: 1, Status: job.FixtureDone},
+			{JobID: created.ID, FixtureID: 2, Status: job.FixtureFailed, Error: "event client error"},
+			{JobID: created.ID, FixtureID: 3, Status: job.FixtureSkipped, Reason: job.ReasonRated},
+		}
+
+		for _, u := range updates {
+			if err := repo.Update(ctx, u); err != nil {
+				t.Fatalf("Expected nil, got %s", err.Error())
+			}
+		}
+
+		fixtures, err := repo.Fixtures(ctx, created.ID)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		a := assert.New(t)
+
+		a.Equal(3, len(fixtures))
+		a.Equal(job.FixtureDone, fixtures[0].Status)
+		a.Equal("", fixtures[0].Error)
+		a.Equal(job.FixtureSkipped, fixtures[2].Status)
+		a.Equal(job.ReasonRated, fixtures[2].Reason)
+
+		failed, err := repo.Failed(ctx)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		a.Equal(1, len(failed))
+		a.Equal(uint64(2), failed[0].FixtureID)
+		a.Equal("event client error", failed[0].Error)
+	})
+	t.Run("does not create a job if the query is cancelled", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		cancelled, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := repo.Create(cancelled, 8, 17420, []uint64{1}); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		_, err := repo.Incomplete(ctx, 8, 17420)
+
+		assert.IsType(t, &app.JobNotFoundError{}, err)
+	})
+}
@@ -0,0 +1,39 @@
+package job
+
+import "time"
+
+const (
+	StatusRunning  = "running"
+	StatusComplete = "complete"
+)
+
+const (
+	FixturePending = "pending"
+	FixtureDone    = "done"
+	FixtureFailed  = "failed"
+	FixtureSkipped = "skipped"
+)
+
+// Job records a run calculating team ratings for a competition and season.
+type Job struct {
+	ID            uint64
+	CompetitionID uint64
+	SeasonID      uint64
+	Status        string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// ReasonRated is the reason recorded for fixtures skipped as ratings already exist for the fixture.
+const ReasonRated = "ratings exist for fixture"
+
+// Fixture records the status of a fixture processed as part of a Job. Error is populated for failed fixtures and
+// Reason for skipped fixtures.
+type Fixture struct {
+	JobID     uint64
+	FixtureID uint64
+	Status    string
+	Error     string
+	Reason    string
+	UpdatedAt time.Time
+}
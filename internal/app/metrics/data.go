@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"context"
+	"github.com/statistico/statistico-football-data-go-grpc-client"
+	"github.com/statistico/statistico-proto/go"
+)
+
+type eventClient struct {
+	client  statisticodata.EventClient
+	metrics *Metrics
+}
+
+func (e *eventClient) FixtureEvents(ctx context.Context, fixtureID uint64) (*statistico.FixtureEventsResponse, error) {
+	start := e.metrics.clock.Now()
+
+	res, err := e.client.FixtureEvents(ctx, fixtureID)
+
+	e.metrics.DataRequest("event", "FixtureEvents", start, err)
+
+	return res, err
+}
+
+type fixtureClient struct {
+	client  statisticodata.FixtureClient
+	metrics *Metrics
+}
+
+func (f *fixtureClient) Search(ctx context.Context, req *statistico.FixtureSearchRequest) ([]*statistico.Fixture, error) {
+	start := f.metrics.clock.Now()
+
+	res, err := f.client.Search(ctx, req)
+
+	f.metrics.DataRequest("fixture", "Search", start, err)
+
+	return res, err
+}
+
+func (f *fixtureClient) ByID(ctx context.Context, fixtureID uint64) (*statistico.Fixture, error) {
+	start := f.metrics.clock.Now()
+
+	res, err := f.client.ByID(ctx, fixtureID)
+
+	f.metrics.DataRequest("fixture", "ByID", start, err)
+
+	return res, err
+}
+
+type resultClient struct {
+	client  statisticodata.ResultClient
+	metrics *Metrics
+}
+
+func (r *resultClient) ByID(ctx context.Context, fixtureID uint64) (*statistico.Result, error) {
+	start := r.metrics.clock.Now()
+
+	res, err := r.client.ByID(ctx, fixtureID)
+
+	r.metrics.DataRequest("result", "ByID", start, err)
+
+	return res, err
+}
+
+func (r *resultClient) ByTeam(ctx context.Context, req *statistico.TeamResultRequest) ([]*statistico.Result, error) {
+	start := r.metrics.clock.Now()
+
+	res, err := r.client.ByTeam(ctx, req)
+
+	r.metrics.DataRequest("result", "ByTeam", start, err)
+
+	return res, err
+}
+
+type seasonClient struct {
+	client  statisticodata.SeasonClient
+	metrics *Metrics
+}
+
+func (s *seasonClient) ByTeamID(ctx context.Context, teamId uint64, sort string) ([]*statistico.Season, error) {
+	start := s.metrics.clock.Now()
+
+	res, err := s.client.ByTeamID(ctx, teamId, sort)
+
+	s.metrics.DataRequest("season", "ByTeamID", start, err)
+
+	return res, err
+}
+
+func (s *seasonClient) ByCompetitionID(ctx context.Context, competitionId uint64, sort string) ([]*statistico.Season, error) {
+	start := s.metrics.clock.Now()
+
+	res, err := s.client.ByCompetitionID(ctx, competitionId, sort)
+
+	s.metrics.DataRequest("season", "ByCompetitionID", start, err)
+
+	return res, err
+}
+
+// NewEventClient returns a statisticodata.EventClient recording the latency of each request made by the wrapped
+// EventClient.
+func NewEventClient(c statisticodata.EventClient, m *Metrics) statisticodata.EventClient {
+	return &eventClient{client: c, metrics: m}
+}
+
+// NewFixtureClient returns a statisticodata.FixtureClient recording the latency of each request made by the wrapped
+// FixtureClient.
+func NewFixtureClient(c statisticodata.FixtureClient, m *Metrics) statisticodata.FixtureClient {
+	return &fixtureClient{client: c, metrics: m}
+}
+
+// NewResultClient returns a statisticodata.ResultClient recording the latency of each request made by the wrapped
+// ResultClient.
+func NewResultClient(c statisticodata.ResultClient, m *Metrics) statisticodata.ResultClient {
+	return &resultClient{client: c, metrics: m}
+}
+
+// NewSeasonClient returns a statisticodata.SeasonClient recording the latency of each request made by the wrapped
+// SeasonClient.
+func NewSeasonClient(c statisticodata.SeasonClient, m *Metrics) statisticodata.SeasonClient {
+	return &seasonClient{client: c, metrics: m}
+}
@@ -0,0 +1,113 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"github.com/jonboulle/clockwork"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestEventClient_FixtureEvents(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("records the latency of successful requests made by the wrapped client", func(t *testing.T) {
+		t.Helper()
+
+		events := new(MockEventClient)
+		clock := clockwork.NewFakeClockAt(time.Unix(1638820800, 0))
+		m := metrics.NewMetrics(clock)
+
+		client := metrics.NewEventClient(events, m)
+
+		res := statistico.FixtureEventsResponse{FixtureId: 26}
+
+		events.On("FixtureEvents", ctx, uint64(26)).
+			Run(func(args mock.Arguments) { clock.Advance(500 * time.Millisecond) }).
+			Return(&res, nil)
+
+		fetched, err := client.FixtureEvents(ctx, 26)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		body := scrape(t, m)
+
+		assert.Equal(t, &res, fetched)
+		assert.Contains(t, body, `statistico_ratings_data_request_duration_seconds_sum{client="event",method="FixtureEvents",result="success"} 0.5`)
+		assert.Contains(t, body, `statistico_ratings_data_request_duration_seconds_count{client="event",method="FixtureEvents",result="success"} 1`)
+	})
+
+	t.Run("records failed requests and returns the error from the wrapped client", func(t *testing.T) {
+		t.Helper()
+
+		events := new(MockEventClient)
+		m := metrics.NewMetrics(clockwork.NewFakeClock())
+
+		client := metrics.NewEventClient(events, m)
+
+		events.On("FixtureEvents", ctx, uint64(26)).Return(&statistico.FixtureEventsResponse{}, errors.New("event client error"))
+
+		_, err := client.FixtureEvents(ctx, 26)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "event client error", err.Error())
+		assert.Contains(t, scrape(t, m), `statistico_ratings_data_request_duration_seconds_count{client="event",method="FixtureEvents",result="error"} 1`)
+	})
+}
+
+func TestResultClient_ByID(t *testing.T) {
+	t.Run("records the latency of requests made by the wrapped client", func(t *testing.T) {
+		t.Helper()
+
+		results := new(MockResultClient)
+		m := metrics.NewMetrics(clockwork.NewFakeClock())
+
+		client := metrics.NewResultClient(results, m)
+
+		ctx := context.Background()
+		res := statistico.Result{Id: 26}
+
+		results.On("ByID", ctx, uint64(26)).Return(&res, nil)
+
+		fetched, err := client.ByID(ctx, 26)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &res, fetched)
+		assert.Contains(t, scrape(t, m), `statistico_ratings_data_request_duration_seconds_count{client="result",method="ByID",result="success"} 1`)
+	})
+}
+
+type MockEventClient struct {
+	mock.Mock
+}
+
+func (m *MockEventClient) FixtureEvents(ctx context.Context, fixtureID uint64) (*statistico.FixtureEventsResponse, error) {
+	args := m.Called(ctx, fixtureID)
+	return args.Get(0).(*statistico.FixtureEventsResponse), args.Error(1)
+}
+
+type MockResultClient struct {
+	mock.Mock
+}
+
+func (m *MockResultClient) ByID(ctx context.Context, fixtureID uint64) (*statistico.Result, error) {
+	args := m.Called(ctx, fixtureID)
+	return args.Get(0).(*statistico.Result), args.Error(1)
+}
+
+func (m *MockResultClient) ByTeam(ctx context.Context, req *statistico.TeamResultRequest) ([]*statistico.Result, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).([]*statistico.Result), args.Error(1)
+}
@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor recording the count and latency of each request by
+// method and status code.
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := m.clock.Now()
+
+		res, err := handler(ctx, req)
+
+		m.GrpcRequest(info.FullMethod, status.Code(err).String(), start)
+
+		return res, err
+	}
+}
@@ -0,0 +1,55 @@
+package metrics_test
+
+import (
+	"context"
+	"github.com/jonboulle/clockwork"
+	"github.com/statistico/statistico-ratings/internal/app/metrics"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestMetrics_UnaryServerInterceptor(t *testing.T) {
+	t.Run("records requests by method and status code", func(t *testing.T) {
+		t.Helper()
+
+		clock := clockwork.NewFakeClockAt(time.Unix(1638820800, 0))
+		m := metrics.NewMetrics(clock)
+
+		interceptor := m.UnaryServerInterceptor()
+		info := grpc.UnaryServerInfo{FullMethod: "/proto.TeamRatingService/GetTeamRatings"}
+
+		ok := func(ctx context.Context, req interface{}) (interface{}, error) {
+			clock.Advance(time.Second)
+			return "response", nil
+		}
+
+		invalid := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.InvalidArgument, "invalid date")
+		}
+
+		res, err := interceptor(context.Background(), "request", &info, ok)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		_, err = interceptor(context.Background(), "request", &info, invalid)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		body := scrape(t, m)
+
+		a := assert.New(t)
+		a.Equal("response", res)
+		a.Equal(codes.InvalidArgument, status.Code(err))
+		a.Contains(body, `statistico_ratings_grpc_requests_total{code="OK",method="/proto.TeamRatingService/GetTeamRatings"} 1`)
+		a.Contains(body, `statistico_ratings_grpc_requests_total{code="InvalidArgument",method="/proto.TeamRatingService/GetTeamRatings"} 1`)
+		a.Contains(body, `statistico_ratings_grpc_request_duration_seconds_sum{code="OK",method="/proto.TeamRatingService/GetTeamRatings"} 1`)
+	})
+}
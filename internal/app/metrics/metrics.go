@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"github.com/jonboulle/clockwork"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const namespace = "statistico_ratings"
+
+// Fixture outcomes recorded against the fixtures total metric.
+const (
+	OutcomeProcessed  = "processed"
+	OutcomeSkipped    = "skipped"
+	OutcomeFailed     = "failed"
+	OutcomeUnfinished = "unfinished"
+)
+
+// Metrics holds the Prometheus collectors for the rating pipeline and gRPC server, registered against a registry
+// owned by Metrics.
+type Metrics struct {
+	registry        *prometheus.Registry
+	clock           clockwork.Clock
+	fixtures        *prometheus.CounterVec
+	dataRequests    *prometheus.HistogramVec
+	ratingWrites    *prometheus.HistogramVec
+	grpcRequests    *prometheus.CounterVec
+	grpcRequestTime *prometheus.HistogramVec
+}
+
+// Handler returns a http.Handler exposing the registered metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Fixture records the outcome of processing team ratings for a fixture in a competition.
+func (m *Metrics) Fixture(competitionID uint64, outcome string) {
+	m.fixtures.WithLabelValues(strconv.FormatUint(competitionID, 10), outcome).Inc()
+}
+
+// DataRequest records the time taken by a Statistico data service request started at the time provided.
+func (m *Metrics) DataRequest(client, method string, start time.Time, err error) {
+	m.dataRequests.WithLabelValues(client, method, result(err)).Observe(m.since(start))
+}
+
+// RatingWrite records the time taken to write a team rating started at the time provided.
+func (m *Metrics) RatingWrite(start time.Time, err error) {
+	m.ratingWrites.WithLabelValues(result(err)).Observe(m.since(start))
+}
+
+// GrpcRequest records a gRPC request and the time taken to handle it by method and status code.
+func (m *Metrics) GrpcRequest(method, code string, start time.Time) {
+	m.grpcRequests.WithLabelValues(method, code).Inc()
+	m.grpcRequestTime.WithLabelValues(method, code).Observe(m.since(start))
+}
+
+func (m *Metrics) since(start time.Time) float64 {
+	return m.clock.Since(start).Seconds()
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+
+	return "success"
+}
+
+func NewMetrics(c clockwork.Clock) *Metrics {
+	m := Metrics{
+		registry: prometheus.NewRegistry(),
+		clock:    c,
+		fixtures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "fixtures_total",
+			Help:      "Fixtures processed by the team rating pipeline by competition and outcome.",
+		}, []string{"competition", "outcome"}),
+		dataRequests: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "data_request_duration_seconds",
+			Help:      "Latency of requests to the Statistico data service by client, method and result.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"client", "method", "result"}),
+		ratingWrites: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rating_write_duration_seconds",
+			Help:      "Latency of team rating writes by result.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"result"}),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "gRPC requests handled by method and status code.",
+		}, []string{"method", "code"}),
+		grpcRequestTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "Latency of gRPC requests by method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+	}
+
+	m.registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		m.fixtures,
+		m.dataRequests,
+		m.ratingWrites,
+		m.grpcRequests,
+		m.grpcRequestTime,
+	)
+
+	return &m
+}
@@ -0,0 +1,53 @@
+package metrics_test
+
+import (
+	"github.com/jonboulle/clockwork"
+	"github.com/statistico/statistico-ratings/internal/app/metrics"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMetrics_Handler(t *testing.T) {
+	t.Run("exposes recorded metrics in the prometheus text format", func(t *testing.T) {
+		t.Helper()
+
+		clock := clockwork.NewFakeClockAt(time.Unix(1638820800, 0))
+		m := metrics.NewMetrics(clock)
+
+		start := clock.Now()
+		clock.Advance(2 * time.Second)
+
+		m.Fixture(8, metrics.OutcomeProcessed)
+		m.Fixture(8, metrics.OutcomeProcessed)
+		m.RatingWrite(start, nil)
+		m.GrpcRequest("/proto.TeamRatingService/GetTeamRatings", "OK", start)
+
+		body := scrape(t, m)
+
+		a := assert.New(t)
+		a.Contains(body, `statistico_ratings_fixtures_total{competition="8",outcome="processed"} 2`)
+		a.Contains(body, `statistico_ratings_rating_write_duration_seconds_sum{result="success"} 2`)
+		a.Contains(body, `statistico_ratings_grpc_requests_total{code="OK",method="/proto.TeamRatingService/GetTeamRatings"} 1`)
+		a.Contains(body, `statistico_ratings_grpc_request_duration_seconds_count{code="OK",method="/proto.TeamRatingService/GetTeamRatings"} 1`)
+		a.Contains(body, "go_goroutines")
+	})
+}
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := ioutil.ReadAll(rec.Body)
+
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+
+	return string(body)
+}
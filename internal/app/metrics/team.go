@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"context"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app"
+	"github.com/statistico/statistico-ratings/internal/app/fixture"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+)
+
+type ratingProcessor struct {
+	processor team.RatingProcessor
+	metrics   *Metrics
+}
+
+// ByFixture records a fixture as skipped if the wrapped RatingProcessor returns an app.DuplicationError, failed
+// for any other error and processed otherwise.
+func (r *ratingProcessor) ByFixture(ctx context.Context, f *statistico.Fixture) error {
+	err := r.processor.ByFixture(ctx, f)
+
+	outcome := OutcomeProcessed
+
+	switch err.(type) {
+	case nil:
+	case *app.DuplicationError:
+		outcome = OutcomeSkipped
+	default:
+		outcome = OutcomeFailed
+	}
+
+	r.metrics.Fixture(f.GetCompetition().GetId(), outcome)
+
+	return err
+}
+
+type ratingWriter struct {
+	writer  team.RatingWriter
+	metrics *Metrics
+}
+
+func (r *ratingWriter) Insert(ctx context.Context, x *team.Rating) error {
+	start := r.metrics.clock.Now()
+
+	err := r.writer.Insert(ctx, x)
+
+	r.metrics.RatingWrite(start, err)
+
+	return err
+}
+
+type statusChecker struct {
+	checker fixture.StatusChecker
+	metrics *Metrics
+}
+
+// Finished records a fixture as unfinished if the wrapped StatusChecker reports it has not finished, or failed if
+// the check returns an error. Finished fixtures are recorded once processed by the RatingProcessor.
+func (s *statusChecker) Finished(ctx context.Context, f *statistico.Fixture) (bool, string, error) {
+	finished, reason, err := s.checker.Finished(ctx, f)
+
+	if err != nil {
+		s.metrics.Fixture(f.GetCompetition().GetId(), OutcomeFailed)
+		return finished, reason, err
+	}
+
+	if !finished {
+		s.metrics.Fixture(f.GetCompetition().GetId(), OutcomeUnfinished)
+	}
+
+	return finished, reason, err
+}
+
+// NewRatingProcessor returns a team.RatingProcessor recording the outcome of each fixture processed by the wrapped
+// RatingProcessor by competition.
+func NewRatingProcessor(p team.RatingProcessor, m *Metrics) team.RatingProcessor {
+	return &ratingProcessor{processor: p, metrics: m}
+}
+
+// NewRatingWriter returns a team.RatingWriter recording the latency of each rating inserted by the wrapped
+// RatingWriter.
+func NewRatingWriter(w team.RatingWriter, m *Metrics) team.RatingWriter {
+	return &ratingWriter{writer: w, metrics: m}
+}
+
+// NewStatusChecker returns a fixture.StatusChecker recording fixtures the wrapped StatusChecker reports as unfinished
+// by competition.
+func NewStatusChecker(c fixture.StatusChecker, m *Metrics) fixture.StatusChecker {
+	return &statusChecker{checker: c, metrics: m}
+}
@@ -0,0 +1,163 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"github.com/jonboulle/clockwork"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app"
+	"github.com/statistico/statistico-ratings/internal/app/metrics"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestRatingProcessor_ByFixture(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("records the outcome of each fixture by competition", func(t *testing.T) {
+		t.Helper()
+
+		p := new(MockRatingProcessor)
+		m := metrics.NewMetrics(clockwork.NewFakeClock())
+
+		processor := metrics.NewRatingProcessor(p, m)
+
+		fix1 := statistico.Fixture{Id: 1, Competition: &statistico.Competition{Id: 8}}
+		fix2 := statistico.Fixture{Id: 2, Competition: &statistico.Competition{Id: 8}}
+		fix3 := statistico.Fixture{Id: 3, Competition: &statistico.Competition{Id: 9}}
+
+		p.On("ByFixture", ctx, &fix1).Return(nil)
+		p.On("ByFixture", ctx, &fix2).Return(&app.DuplicationError{FixtureID: 2})
+		p.On("ByFixture", ctx, &fix3).Return(errors.New("event client error"))
+
+		for _, f := range []*statistico.Fixture{&fix1, &fix2, &fix3} {
+			_ = processor.ByFixture(ctx, f)
+		}
+
+		body := scrape(t, m)
+
+		a := assert.New(t)
+		a.Contains(body, `statistico_ratings_fixtures_total{competition="8",outcome="processed"} 1`)
+		a.Contains(body, `statistico_ratings_fixtures_total{competition="8",outcome="skipped"} 1`)
+		a.Contains(body, `statistico_ratings_fixtures_total{competition="9",outcome="failed"} 1`)
+	})
+
+	t.Run("returns the error from the wrapped processor", func(t *testing.T) {
+		t.Helper()
+
+		p := new(MockRatingProcessor)
+		m := metrics.NewMetrics(clockwork.NewFakeClock())
+
+		processor := metrics.NewRatingProcessor(p, m)
+
+		fix := statistico.Fixture{Id: 1}
+
+		p.On("ByFixture", ctx, &fix).Return(errors.New("event client error"))
+
+		err := processor.ByFixture(ctx, &fix)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "event client error", err.Error())
+	})
+}
+
+func TestRatingWriter_Insert(t *testing.T) {
+	t.Run("records the latency of each rating inserted", func(t *testing.T) {
+		t.Helper()
+
+		w := new(MockRatingWriter)
+		clock := clockwork.NewFakeClockAt(time.Unix(1638820800, 0))
+		m := metrics.NewMetrics(clock)
+
+		writer := metrics.NewRatingWriter(w, m)
+
+		ctx := context.Background()
+		rating := team.Rating{TeamID: 1, FixtureID: 2}
+
+		w.On("Insert", ctx, &rating).
+			Run(func(args mock.Arguments) { clock.Advance(250 * time.Millisecond) }).
+			Return(nil)
+
+		if err := writer.Insert(ctx, &rating); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		body := scrape(t, m)
+
+		assert.Contains(t, body, `statistico_ratings_rating_write_duration_seconds_sum{result="success"} 0.25`)
+		assert.Contains(t, body, `statistico_ratings_rating_write_duration_seconds_count{result="success"} 1`)
+	})
+}
+
+func TestStatusChecker_Finished(t *testing.T) {
+	t.Run("records unfinished fixtures by competition", func(t *testing.T) {
+		t.Helper()
+
+		c := new(MockStatusChecker)
+		m := metrics.NewMetrics(clockwork.NewFakeClock())
+
+		checker := metrics.NewStatusChecker(c, m)
+
+		ctx := context.Background()
+		fix1 := statistico.Fixture{Id: 1, Competition: &statistico.Competition{Id: 8}}
+		fix2 := statistico.Fixture{Id: 2, Competition: &statistico.Competition{Id: 8}}
+
+		c.On("Finished", ctx, &fix1).Return(true, "", nil)
+		c.On("Finished", ctx, &fix2).Return(false, "result does not exist", nil)
+
+		finished, _, err := checker.Finished(ctx, &fix1)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.True(t, finished)
+
+		finished, reason, err := checker.Finished(ctx, &fix2)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		body := scrape(t, m)
+
+		a := assert.New(t)
+		a.False(finished)
+		a.Equal("result does not exist", reason)
+		a.Contains(body, `statistico_ratings_fixtures_total{competition="8",outcome="unfinished"} 1`)
+		a.NotContains(body, `outcome="processed"`)
+	})
+}
+
+type MockRatingProcessor struct {
+	mock.Mock
+}
+
+func (m *MockRatingProcessor) ByFixture(ctx context.Context, f *statistico.Fixture) error {
+	args := m.Called(ctx, f)
+	return args.Error(0)
+}
+
+type MockRatingWriter struct {
+	mock.Mock
+}
+
+func (m *MockRatingWriter) Insert(ctx context.Context, r *team.Rating) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+type MockStatusChecker struct {
+	mock.Mock
+}
+
+func (m *MockStatusChecker) Finished(ctx context.Context, f *statistico.Fixture) (bool, string, error) {
+	args := m.Called(ctx, f)
+	return args.Bool(0), args.String(1), args.Error(2)
+}
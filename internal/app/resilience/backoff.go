@@ -0,0 +1,73 @@
+package resilience
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Backoff configures the number of attempts made for a request and the exponentially increasing delay between
+// them, starting at Initial and multiplied by Multiplier after each attempt up to Max.
+type Backoff struct {
+	Attempts   int
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+// Delay returns the time to wait before retrying after the given failed attempt, counting from 1.
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := float64(b.Initial)
+
+	for i := 1; i < attempt; i++ {
+		delay *= b.Multiplier
+
+		if delay >= float64(b.Max) {
+			return b.Max
+		}
+	}
+
+	return time.Duration(delay)
+}
+
+// ServiceConfig returns a gRPC service config retrying calls to every method that fail with UNAVAILABLE using
+// the attempts and delays of b. FixtureEvents is called for every fixture rated so calls to it failing with INTERNAL
+// are retried too. gRPC makes at most 5 attempts and randomises each delay up to the backoff. An empty config is
+// returned if b allows a single attempt.
+func ServiceConfig(b Backoff) string {
+	if b.Attempts < 2 {
+		return "{}"
+	}
+
+	config := map[string]interface{}{
+		"methodConfig": []interface{}{
+			map[string]interface{}{
+				"name":        []interface{}{map[string]interface{}{"service": "statistico.EventService", "method": "FixtureEvents"}},
+				"retryPolicy": retryPolicy(b, "UNAVAILABLE", "INTERNAL"),
+			},
+			map[string]interface{}{
+				"name":        []interface{}{map[string]interface{}{}},
+				"retryPolicy": retryPolicy(b, "UNAVAILABLE"),
+			},
+		},
+	}
+
+	// The config only contains strings and numbers so cannot fail to marshal.
+	j, _ := json.Marshal(config)
+
+	return string(j)
+}
+
+func retryPolicy(b Backoff, codes ...string) map[string]interface{} {
+	return map[string]interface{}{
+		"maxAttempts":          b.Attempts,
+		"initialBackoff":       seconds(b.Initial),
+		"maxBackoff":           seconds(b.Max),
+		"backoffMultiplier":    b.Multiplier,
+		"retryableStatusCodes": codes,
+	}
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3fs", d.Seconds())
+}
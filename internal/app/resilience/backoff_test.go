@@ -0,0 +1,197 @@
+package resilience_test
+
+import (
+	"context"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/statistico/statistico-football-data-go-grpc-client"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app/resilience"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackoff_Delay(t *testing.T) {
+	t.Run("increases the delay exponentially up to the maximum", func(t *testing.T) {
+		t.Helper()
+
+		b := resilience.Backoff{Attempts: 5, Initial: 200 * time.Millisecond, Max: time.Second, Multiplier: 2}
+
+		assert.Equal(t, 200*time.Millisecond, b.Delay(1))
+		assert.Equal(t, 400*time.Millisecond, b.Delay(2))
+		assert.Equal(t, 800*time.Millisecond, b.Delay(3))
+		assert.Equal(t, time.Second, b.Delay(4))
+	})
+}
+
+func TestServiceConfig(t *testing.T) {
+	t.Run("returns a retry policy for all methods and FixtureEvents", func(t *testing.T) {
+		t.Helper()
+
+		b := resilience.Backoff{Attempts: 3, Initial: 200 * time.Millisecond, Max: 5 * time.Second, Multiplier: 2}
+
+		expected := `{"methodConfig":[{"name":[{"method":"FixtureEvents","service":"statistico.EventService"}],` +
+			`"retryPolicy":{"backoffMultiplier":2,"initialBackoff":"0.200s","maxAttempts":3,"maxBackoff":"5.000s",` +
+			`"retryableStatusCodes":["UNAVAILABLE","INTERNAL"]}},{"name":[{}],"retryPolicy":{"backoffMultiplier":2,` +
+			`"initialBackoff":"0.200s","maxAttempts":3,"maxBackoff":"5.000s","retryableStatusCodes":["UNAVAILABLE"]}}]}`
+
+		assert.Equal(t, expected, resilience.ServiceConfig(b))
+	})
+
+	t.Run("returns an empty config if a single attempt is allowed", func(t *testing.T) {
+		t.Helper()
+
+		assert.Equal(t, "{}", resilience.ServiceConfig(resilience.Backoff{Attempts: 1}))
+	})
+}
+
+func TestServiceConfig_Retries(t *testing.T) {
+	t.Run("retries calls failing with unavailable over a connection", func(t *testing.T) {
+		t.Helper()
+
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		service := &flakyHealthServer{failures: 2}
+		server := grpc.NewServer()
+		grpc_health_v1.RegisterHealthServer(server, service)
+
+		go server.Serve(lis)
+		defer server.Stop()
+
+		b := resilience.Backoff{Attempts: 3, Initial: 10 * time.Millisecond, Max: 50 * time.Millisecond, Multiplier: 2}
+
+		conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure(), grpc.WithDefaultServiceConfig(resilience.ServiceConfig(b)))
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		res, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, res.Status)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&service.calls))
+	})
+}
+
+func TestServiceConfig_Attempts(t *testing.T) {
+	b := resilience.Backoff{Attempts: 3, Initial: 10 * time.Millisecond, Max: 50 * time.Millisecond, Multiplier: 2}
+
+	t.Run("makes the configured attempts for FixtureEvents calls failing with a transient error", func(t *testing.T) {
+		t.Helper()
+
+		for _, code := range []codes.Code{codes.Unavailable, codes.Internal} {
+			service := &failingEventServer{code: code}
+
+			conn := dial(t, b, func(s *grpc.Server) {
+				statistico.RegisterEventServiceServer(s, service)
+			})
+
+			_, err := statisticodata.NewEventClient(statistico.NewEventServiceClient(conn)).FixtureEvents(context.Background(), 26)
+
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			assert.Equal(t, int32(3), atomic.LoadInt32(&service.calls), code.String())
+		}
+	})
+
+	t.Run("does not retry FixtureEvents calls failing with not found", func(t *testing.T) {
+		t.Helper()
+
+		service := &failingEventServer{code: codes.NotFound}
+
+		conn := dial(t, b, func(s *grpc.Server) {
+			statistico.RegisterEventServiceServer(s, service)
+		})
+
+		_, err := statisticodata.NewEventClient(statistico.NewEventServiceClient(conn)).FixtureEvents(context.Background(), 26)
+
+		assert.IsType(t, statisticodata.ErrorNotFound{}, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&service.calls))
+	})
+}
+
+// dial serves the services registered by register and returns a connection to them dialed with the service config
+// and interceptors the data service connection is built with.
+func dial(t *testing.T, b resilience.Backoff, register func(s *grpc.Server)) *grpc.ClientConn {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+
+	server := grpc.NewServer()
+	register(server)
+
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	logger, _ := test.NewNullLogger()
+
+	breaker := resilience.NewCircuitBreaker("statistico data service", 10, time.Minute, clockwork.NewRealClock(), logger)
+
+	conn, err := grpc.Dial(
+		lis.Addr().String(),
+		grpc.WithInsecure(),
+		grpc.WithDefaultServiceConfig(resilience.ServiceConfig(b)),
+		grpc.WithChainUnaryInterceptor(breaker.UnaryClientInterceptor(), resilience.TimeoutInterceptor(5*time.Second)),
+	)
+
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// failingEventServer fails every FixtureEvents call with code.
+type failingEventServer struct {
+	statistico.UnimplementedEventServiceServer
+	code  codes.Code
+	calls int32
+}
+
+func (f *failingEventServer) FixtureEvents(context.Context, *statistico.FixtureRequest) (*statistico.FixtureEventsResponse, error) {
+	atomic.AddInt32(&f.calls, 1)
+	return nil, status.Error(f.code, "data service error")
+}
+
+// flakyHealthServer fails the first failures calls with UNAVAILABLE.
+type flakyHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	failures int32
+	calls    int32
+}
+
+func (f *flakyHealthServer) Check(context.Context, *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if atomic.AddInt32(&f.calls, 1) <= f.failures {
+		return nil, status.Error(codes.Unavailable, "try again")
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
@@ -0,0 +1,125 @@
+package resilience
+
+import (
+	"context"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
+	"time"
+)
+
+type state int
+
+const (
+	closed state = iota
+	open
+	halfOpen
+)
+
+// CircuitBreaker stops calls being made to a service that is failing so requests fail fast rather than waiting on
+// timeouts and retries. The breaker opens after the configured number of consecutive calls fail with UNAVAILABLE
+// or DEADLINE_EXCEEDED, rejecting calls with UNAVAILABLE until the cooldown has passed. A single trial call is then
+// allowed, closing the breaker if it succeeds and reopening it if it fails.
+type CircuitBreaker struct {
+	name     string
+	failures int
+	cooldown time.Duration
+	clock    clockwork.Clock
+	logger   *logrus.Logger
+	mu       sync.Mutex
+	state    state
+	failed   int
+	opened   time.Time
+}
+
+// Allow reports whether a call can be made, moving an open breaker to half open once the cooldown has passed.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case closed:
+		return true
+	case open:
+		if b.clock.Since(b.opened) < b.cooldown {
+			return false
+		}
+
+		b.state = halfOpen
+
+		return true
+	default:
+		// A trial call is in progress.
+		return false
+	}
+}
+
+// Record records the result of a call allowed by Allow. Calls cancelled by the caller say nothing about the health
+// of the service so are ignored, other than to end a trial call.
+func (b *CircuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if status.Code(err) == codes.Canceled {
+		if b.state == halfOpen {
+			b.state = open
+		}
+
+		return
+	}
+
+	if !failure(err) {
+		if b.state != closed {
+			b.logger.Infof("%s circuit breaker closed", b.name)
+		}
+
+		b.state = closed
+		b.failed = 0
+
+		return
+	}
+
+	b.failed++
+
+	if b.state == halfOpen || b.failed >= b.failures {
+		if b.state != open {
+			b.logger.Warnf("%s circuit breaker opened after %d consecutive failures: %s", b.name, b.failed, err.Error())
+		}
+
+		b.state = open
+		b.opened = b.clock.Now()
+	}
+}
+
+// UnaryClientInterceptor returns a grpc.UnaryClientInterceptor rejecting calls while the breaker is open.
+func (b *CircuitBreaker) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !b.Allow() {
+			return status.Errorf(codes.Unavailable, "%s circuit breaker is open", b.name)
+		}
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		b.Record(err)
+
+		return err
+	}
+}
+
+func failure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewCircuitBreaker returns a closed CircuitBreaker for the named service, opening after failures consecutive
+// failed calls for cooldown.
+func NewCircuitBreaker(name string, failures int, cooldown time.Duration, c clockwork.Clock, l *logrus.Logger) *CircuitBreaker {
+	return &CircuitBreaker{name: name, failures: failures, cooldown: cooldown, clock: c, logger: l}
+}
@@ -0,0 +1,120 @@
+package resilience_test
+
+import (
+	"context"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/statistico/statistico-ratings/internal/app/resilience"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_Allow(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+
+	t.Run("opens after consecutive failures and allows a trial call after the cooldown", func(t *testing.T) {
+		t.Helper()
+
+		clock := clockwork.NewFakeClock()
+		logger, hook := test.NewNullLogger()
+		breaker := resilience.NewCircuitBreaker("data service", 2, 30*time.Second, clock, logger)
+
+		assert.True(t, breaker.Allow())
+		breaker.Record(unavailable)
+		assert.True(t, breaker.Allow())
+		breaker.Record(unavailable)
+
+		assert.False(t, breaker.Allow())
+		assert.Equal(t, "data service circuit breaker opened after 2 consecutive failures: rpc error: code = Unavailable desc = connection refused", hook.LastEntry().Message)
+
+		clock.Advance(30 * time.Second)
+
+		assert.True(t, breaker.Allow())
+		assert.False(t, breaker.Allow())
+
+		breaker.Record(nil)
+
+		assert.True(t, breaker.Allow())
+		assert.True(t, breaker.Allow())
+		assert.Equal(t, "data service circuit breaker closed", hook.LastEntry().Message)
+	})
+
+	t.Run("reopens if the trial call fails", func(t *testing.T) {
+		t.Helper()
+
+		clock := clockwork.NewFakeClock()
+		logger, _ := test.NewNullLogger()
+		breaker := resilience.NewCircuitBreaker("data service", 1, 30*time.Second, clock, logger)
+
+		breaker.Record(unavailable)
+		clock.Advance(30 * time.Second)
+
+		assert.True(t, breaker.Allow())
+		breaker.Record(status.Error(codes.DeadlineExceeded, "deadline exceeded"))
+
+		assert.False(t, breaker.Allow())
+	})
+
+	t.Run("does not count errors returned by a healthy service as failures", func(t *testing.T) {
+		t.Helper()
+
+		logger, _ := test.NewNullLogger()
+		breaker := resilience.NewCircuitBreaker("data service", 2, 30*time.Second, clockwork.NewFakeClock(), logger)
+
+		breaker.Record(unavailable)
+		breaker.Record(status.Error(codes.NotFound, "not found"))
+		breaker.Record(unavailable)
+
+		assert.True(t, breaker.Allow())
+	})
+
+	t.Run("ends a cancelled trial call without closing the breaker", func(t *testing.T) {
+		t.Helper()
+
+		clock := clockwork.NewFakeClock()
+		logger, _ := test.NewNullLogger()
+		breaker := resilience.NewCircuitBreaker("data service", 1, 30*time.Second, clock, logger)
+
+		breaker.Record(unavailable)
+		clock.Advance(30 * time.Second)
+
+		assert.True(t, breaker.Allow())
+		breaker.Record(status.Error(codes.Canceled, "context canceled"))
+
+		assert.True(t, breaker.Allow())
+		assert.False(t, breaker.Allow())
+	})
+}
+
+func TestCircuitBreaker_UnaryClientInterceptor(t *testing.T) {
+	t.Run("rejects calls without invoking them while open", func(t *testing.T) {
+		t.Helper()
+
+		logger, _ := test.NewNullLogger()
+		interceptor := resilience.NewCircuitBreaker("data service", 1, 30*time.Second, clockwork.NewFakeClock(), logger).UnaryClientInterceptor()
+
+		calls := 0
+
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			calls++
+			return status.Error(codes.Unavailable, "connection refused")
+		}
+
+		err := interceptor(context.Background(), "/statistico.EventService/FixtureEvents", nil, nil, nil, invoker)
+
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+
+		err = interceptor(context.Background(), "/statistico.EventService/FixtureEvents", nil, nil, nil, invoker)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "rpc error: code = Unavailable desc = data service circuit breaker is open", err.Error())
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,19 @@
+package resilience
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"time"
+)
+
+// TimeoutInterceptor returns a grpc.UnaryClientInterceptor applying timeout to calls made without a deadline, or
+// with a later deadline, so a slow service cannot hold up a run indefinitely. The timeout covers all retry
+// attempts of the call.
+func TimeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
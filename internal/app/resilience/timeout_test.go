@@ -0,0 +1,61 @@
+package resilience_test
+
+import (
+	"context"
+	"github.com/statistico/statistico-ratings/internal/app/resilience"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"testing"
+	"time"
+)
+
+func TestTimeoutInterceptor(t *testing.T) {
+	interceptor := resilience.TimeoutInterceptor(time.Second)
+
+	deadline := func(ctx context.Context) time.Duration {
+		d, ok := ctx.Deadline()
+
+		if !ok {
+			t.Fatal("Expected deadline, got none")
+		}
+
+		return time.Until(d)
+	}
+
+	t.Run("applies the timeout to calls made without a deadline", func(t *testing.T) {
+		t.Helper()
+
+		var remaining time.Duration
+
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			remaining = deadline(ctx)
+			return nil
+		}
+
+		if err := interceptor(context.Background(), "/statistico.EventService/FixtureEvents", nil, nil, nil, invoker); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.True(t, remaining > 900*time.Millisecond && remaining <= time.Second)
+	})
+
+	t.Run("keeps an earlier deadline set by the caller", func(t *testing.T) {
+		t.Helper()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		var remaining time.Duration
+
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			remaining = deadline(ctx)
+			return nil
+		}
+
+		if err := interceptor(ctx, "/statistico.EventService/FixtureEvents", nil, nil, nil, invoker); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.True(t, remaining <= 100*time.Millisecond)
+	})
+}
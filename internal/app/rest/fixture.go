@@ -0,0 +1,122 @@
+package rest
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type snapshotPoints struct {
+	Points     float64 `json:"points"`
+	Difference float64 `json:"difference"`
+	PreMatch   float64 `json:"preMatch"`
+}
+
+type calculation struct {
+	Goals           uint32  `json:"goals"`
+	AdjustedGoals   float64 `json:"adjustedGoals"`
+	KFactor         float64 `json:"kFactor"`
+	OpponentAttack  float64 `json:"opponentAttack"`
+	OpponentDefence float64 `json:"opponentDefence"`
+	RuleVersion     uint32  `json:"ruleVersion"`
+}
+
+type fixtureRating struct {
+	TeamID        uint64         `json:"teamId"`
+	FixtureID     uint64         `json:"fixtureId"`
+	SeasonID      uint64         `json:"seasonId"`
+	CompetitionID uint64         `json:"competitionId"`
+	Attack        snapshotPoints `json:"attack"`
+	Defence       snapshotPoints `json:"defence"`
+	FixtureDate   time.Time      `json:"fixtureDate"`
+	Timestamp     time.Time      `json:"timestamp"`
+	Calculation   *calculation   `json:"calculation"`
+}
+
+type fixtureRatingResponse struct {
+	Ratings []fixtureRating `json:"ratings"`
+}
+
+// FixtureRatingHandler serves the rating snapshot of a fixture at GET /fixtures/{id}/ratings: the pre and post
+// match points of both teams along with the inputs used to calculate them. Calculation is null for ratings
+// calculated before the inputs were persisted.
+type FixtureRatingHandler struct {
+	reader team.RatingReader
+	logger *logrus.Logger
+}
+
+func (f *FixtureRatingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	if len(parts) != 3 || parts[0] != "fixtures" || parts[2] != "ratings" {
+		writeError(w, http.StatusNotFound, "resource does not exist")
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	fixtureID, err := strconv.ParseUint(parts[1], 10, 64)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("fixture id %q is not a valid id", parts[1]))
+		return
+	}
+
+	ratings, err := f.reader.ByFixture(r.Context(), fixtureID)
+
+	if err != nil {
+		f.logger.Errorf("Error fetching fixture ratings: %s", err.Error())
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	res := fixtureRatingResponse{Ratings: []fixtureRating{}}
+
+	for _, rt := range ratings {
+		x := fixtureRating{
+			TeamID:        rt.TeamID,
+			FixtureID:     rt.FixtureID,
+			SeasonID:      rt.SeasonID,
+			CompetitionID: rt.CompetitionID,
+			Attack: snapshotPoints{
+				Points:     rt.Attack.Total,
+				Difference: rt.Attack.Difference,
+				PreMatch:   rt.Attack.PreMatch(),
+			},
+			Defence: snapshotPoints{
+				Points:     rt.Defence.Total,
+				Difference: rt.Defence.Difference,
+				PreMatch:   rt.Defence.PreMatch(),
+			},
+			FixtureDate: rt.FixtureDate.UTC(),
+			Timestamp:   rt.Timestamp.UTC(),
+		}
+
+		if c := rt.Calculation; c != nil {
+			x.Calculation = &calculation{
+				Goals:           c.Goals,
+				AdjustedGoals:   c.AdjustedGoals,
+				KFactor:         c.KFactor,
+				OpponentAttack:  c.OpponentAttack,
+				OpponentDefence: c.OpponentDefence,
+				RuleVersion:     c.RuleVersion,
+			}
+		}
+
+		res.Ratings = append(res.Ratings, x)
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func NewFixtureRatingHandler(r team.RatingReader, l *logrus.Logger) *FixtureRatingHandler {
+	return &FixtureRatingHandler{reader: r, logger: l}
+}
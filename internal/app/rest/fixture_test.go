@@ -0,0 +1,129 @@
+package rest_test
+
+import (
+	"errors"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/statistico/statistico-ratings/internal/app/rest"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFixtureRatingHandler_ServeHTTP(t *testing.T) {
+	t.Run("returns pre and post match ratings and calculation inputs for a fixture as json", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, _ := test.NewNullLogger()
+		handler := rest.NewFixtureRatingHandler(reader, logger)
+
+		ratings := []*team.Rating{
+			{
+				TeamID:        3,
+				FixtureID:     66,
+				SeasonID:      9,
+				CompetitionID: 8,
+				Attack:        team.Points{Total: 1245.5, Difference: 19.5},
+				Defence:       team.Points{Total: 1240.25, Difference: -12.25},
+				FixtureDate:   time.Unix(1627226510, 0),
+				Timestamp:     time.Unix(1627226520, 0),
+				Calculation: &team.Calculation{
+					Goals:           3,
+					AdjustedGoals:   2.66,
+					KFactor:         5,
+					OpponentAttack:  1518.33,
+					OpponentDefence: 790.72,
+					RuleVersion:     1,
+				},
+			},
+			{
+				TeamID:        4,
+				FixtureID:     66,
+				SeasonID:      9,
+				CompetitionID: 8,
+				Attack:        team.Points{Total: 1500, Difference: 0},
+				Defence:       team.Points{Total: 1500, Difference: 0},
+				FixtureDate:   time.Unix(1627226510, 0),
+				Timestamp:     time.Unix(1627226520, 0),
+			},
+		}
+
+		reader.On("ByFixture", mock.Anything, uint64(66)).Return(ratings, nil)
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fixtures/66/ratings", nil))
+
+		expected := `{"ratings":[` +
+			`{"teamId":3,"fixtureId":66,"seasonId":9,"competitionId":8,` +
+			`"attack":{"points":1245.5,"difference":19.5,"preMatch":1226},` +
+			`"defence":{"points":1240.25,"difference":-12.25,"preMatch":1252.5},` +
+			`"fixtureDate":"2021-07-25T15:21:50Z","timestamp":"2021-07-25T15:22:00Z",` +
+			`"calculation":{"goals":3,"adjustedGoals":2.66,"kFactor":5,"opponentAttack":1518.33,"opponentDefence":790.72,"ruleVersion":1}},` +
+			`{"teamId":4,"fixtureId":66,"seasonId":9,"competitionId":8,` +
+			`"attack":{"points":1500,"difference":0,"preMatch":1500},` +
+			`"defence":{"points":1500,"difference":0,"preMatch":1500},` +
+			`"fixtureDate":"2021-07-25T15:21:50Z","timestamp":"2021-07-25T15:22:00Z","calculation":null}]}`
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, expected, rec.Body.String())
+		reader.AssertExpectations(t)
+	})
+
+	t.Run("returns bad request if fixture id is invalid", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, _ := test.NewNullLogger()
+		handler := rest.NewFixtureRatingHandler(reader, logger)
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fixtures/abc/ratings", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.JSONEq(t, `{"error":"fixture id \"abc\" is not a valid id"}`, rec.Body.String())
+		reader.AssertNotCalled(t, "ByFixture", mock.Anything, mock.Anything)
+	})
+
+	t.Run("returns not found for unknown paths and method not allowed for other methods", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, _ := test.NewNullLogger()
+		handler := rest.NewFixtureRatingHandler(reader, logger)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fixtures/66/events", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/fixtures/66/ratings", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Equal(t, http.MethodGet, rec.Header().Get("Allow"))
+	})
+
+	t.Run("logs error and returns internal server error if error returned by team rating reader", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, hook := test.NewNullLogger()
+		handler := rest.NewFixtureRatingHandler(reader, logger)
+
+		reader.On("ByFixture", mock.Anything, uint64(66)).Return([]*team.Rating{}, errors.New("oh no"))
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fixtures/66/ratings", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.JSONEq(t, `{"error":"internal server error"}`, rec.Body.String())
+		assert.Equal(t, "Error fetching fixture ratings: oh no", hook.LastEntry().Message)
+	})
+}
@@ -0,0 +1,95 @@
+package rest
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLatestTeams limits the teams requested from LatestRatingHandler so a single request cannot scan the whole
+// team_rating table.
+const maxLatestTeams = 100
+
+// LatestRatingHandler serves the latest rating of each of a batch of teams at GET /ratings/latest, looked up in a
+// single query. Teams are provided as a comma separated teams query parameter and ratings are optionally limited
+// to those calculated on or before the before query parameter. Teams without a rating are omitted.
+type LatestRatingHandler struct {
+	reader team.RatingReader
+	logger *logrus.Logger
+}
+
+func (l *LatestRatingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/ratings/latest" {
+		writeError(w, http.StatusNotFound, "resource does not exist")
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	teamIDs, before, err := latestQuery(r)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ratings, err := l.reader.LatestByTeams(r.Context(), teamIDs, before)
+
+	if err != nil {
+		l.logger.Errorf("Error fetching latest team ratings: %s", err.Error())
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newTeamRatingResponse(ratings))
+}
+
+func latestQuery(r *http.Request) ([]uint64, *time.Time, error) {
+	params := r.URL.Query()
+
+	if params.Get("teams") == "" {
+		return nil, nil, fmt.Errorf("teams is required")
+	}
+
+	ids := strings.Split(params.Get("teams"), ",")
+
+	if len(ids) > maxLatestTeams {
+		return nil, nil, fmt.Errorf("at most %d teams can be requested", maxLatestTeams)
+	}
+
+	var teamIDs []uint64
+
+	for _, id := range ids {
+		teamID, err := strconv.ParseUint(strings.TrimSpace(id), 10, 64)
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("team id %q is not a valid id", id)
+		}
+
+		teamIDs = append(teamIDs, teamID)
+	}
+
+	if b := params.Get("before"); b != "" {
+		before, err := time.Parse(time.RFC3339, b)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return teamIDs, &before, nil
+	}
+
+	return teamIDs, nil, nil
+}
+
+func NewLatestRatingHandler(r team.RatingReader, l *logrus.Logger) *LatestRatingHandler {
+	return &LatestRatingHandler{reader: r, logger: l}
+}
@@ -0,0 +1,134 @@
+package rest_test
+
+import (
+	"errors"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/statistico/statistico-ratings/internal/app/rest"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLatestRatingHandler_ServeHTTP(t *testing.T) {
+	t.Run("returns the latest rating for each team as json", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, _ := test.NewNullLogger()
+		handler := rest.NewLatestRatingHandler(reader, logger)
+
+		ratings := []*team.Rating{
+			{
+				TeamID:        5,
+				FixtureID:     10,
+				SeasonID:      99,
+				CompetitionID: 8,
+				Attack:        team.Points{Total: 1432.12, Difference: 10},
+				Defence:       team.Points{Total: 234, Difference: -23},
+				FixtureDate:   time.Unix(1627226510, 0),
+				Timestamp:     time.Unix(1627226520, 0),
+			},
+		}
+
+		before := mock.MatchedBy(func(b *time.Time) bool {
+			return b.Equal(time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC))
+		})
+
+		reader.On("LatestByTeams", mock.Anything, []uint64{5, 6}, before).Return(ratings, nil)
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ratings/latest?teams=5,6&before=2021-08-01T00:00:00Z", nil))
+
+		expected := `{"ratings":[{"teamId":5,"fixtureId":10,"seasonId":99,"competitionId":8,` +
+			`"attack":{"points":1432.12,"difference":10},"defence":{"points":234,"difference":-23},` +
+			`"fixtureDate":"2021-07-25T15:21:50Z","timestamp":"2021-07-25T15:22:00Z"}]}`
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, expected, rec.Body.String())
+		reader.AssertExpectations(t)
+	})
+
+	t.Run("does not limit ratings by date if before is not provided", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, _ := test.NewNullLogger()
+		handler := rest.NewLatestRatingHandler(reader, logger)
+
+		reader.On("LatestByTeams", mock.Anything, []uint64{5}, (*time.Time)(nil)).Return([]*team.Rating{}, nil)
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ratings/latest?teams=5", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"ratings":[]}`, rec.Body.String())
+		reader.AssertExpectations(t)
+	})
+
+	t.Run("returns bad request if the query parameters are invalid", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, _ := test.NewNullLogger()
+		handler := rest.NewLatestRatingHandler(reader, logger)
+
+		many := strings.TrimSuffix(strings.Repeat("1,", 101), ",")
+
+		paths := map[string]string{
+			"/ratings/latest":                          `{"error":"teams is required"}`,
+			"/ratings/latest?teams=5,abc":              `{"error":"team id \"abc\" is not a valid id"}`,
+			"/ratings/latest?teams=" + many:            `{"error":"at most 100 teams can be requested"}`,
+			"/ratings/latest?teams=5&before=yesterday": `{"error":"parsing time \"yesterday\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"yesterday\" as \"2006\""}`,
+		}
+
+		for path, expected := range paths {
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+			assert.JSONEq(t, expected, rec.Body.String(), path)
+		}
+
+		reader.AssertNotCalled(t, "LatestByTeams", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("returns method not allowed for other methods", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, _ := test.NewNullLogger()
+		handler := rest.NewLatestRatingHandler(reader, logger)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/ratings/latest?teams=5", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Equal(t, http.MethodGet, rec.Header().Get("Allow"))
+	})
+
+	t.Run("logs error and returns internal server error if error returned by team rating reader", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, hook := test.NewNullLogger()
+		handler := rest.NewLatestRatingHandler(reader, logger)
+
+		reader.On("LatestByTeams", mock.Anything, []uint64{5}, (*time.Time)(nil)).Return([]*team.Rating{}, errors.New("oh no"))
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ratings/latest?teams=5", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.JSONEq(t, `{"error":"internal server error"}`, rec.Body.String())
+		assert.Equal(t, "Error fetching latest team ratings: oh no", hook.LastEntry().Message)
+	})
+}
@@ -0,0 +1,139 @@
+package rest
+
+import (
+	"context"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus"
+	"github.com/statistico/statistico-ratings/internal/app/grpc"
+	"google.golang.org/grpc/metadata"
+	"net"
+	"net/http"
+)
+
+type clientKey struct{}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// RequestLogger logs the method, path, status and duration of each request handled by h. Server errors are logged
+// at warning level as handlers log their own errors.
+func RequestLogger(h http.Handler, l *logrus.Logger, c clockwork.Clock) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := c.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		h.ServeHTTP(rec, r)
+
+		entry := l.WithFields(logrus.Fields{
+			"method":      r.Method,
+			"path":        r.URL.Path,
+			"status":      rec.status,
+			"duration_ms": c.Since(start).Milliseconds(),
+		})
+
+		if rec.status >= http.StatusInternalServerError {
+			entry.Warn("http request failed")
+			return
+		}
+
+		entry.Info("http request handled")
+	})
+}
+
+// CORS allows browsers to make GET requests to h from the origins provided. A "*" origin allows all origins.
+func CORS(h http.Handler, origins []string) http.Handler {
+	allowed := map[string]bool{}
+
+	for _, o := range origins {
+		allowed[o] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+
+		if origin != "" && (allowed["*"] || allowed[origin]) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", r.Header.Get("Access-Control-Request-Headers"))
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Authenticate rejects requests to h that fail authentication with 401 Unauthorized. Request headers are passed to
+// the Authenticator as incoming gRPC metadata, so the REST gateway accepts the same API keys and JWTs as the gRPC
+// server. Failed attempts count against the rate limit of the remote IP address, and addresses over the limit are
+// rejected with 429 Too Many Requests without being authenticated. Failed attempts are not limited if l is nil.
+func Authenticate(h http.Handler, a grpc.Authenticator, l *grpc.RateLimiter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addr := clientID(r)
+
+		if l != nil && l.Limited(addr) {
+			writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+
+		md := metadata.MD{}
+
+		for key, values := range r.Header {
+			md.Append(key, values...)
+		}
+
+		client, err := a.Authenticate(metadata.NewIncomingContext(r.Context(), md))
+
+		if err != nil {
+			if l != nil {
+				l.Allow(addr)
+			}
+
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientKey{}, client)))
+	})
+}
+
+// RateLimit rejects requests to h over the rate limit of the client making them with 429 Too Many Requests.
+// Clients are identified by the client authenticated by Authenticate, or the remote IP address for unauthenticated
+// requests, so RateLimit must be chained after Authenticate.
+func RateLimit(h http.Handler, l *grpc.RateLimiter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.Allow(clientID(r)) {
+			writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// clientID returns the ID of the authenticated client making a request, falling back to the host of the remote
+// address for unauthenticated requests.
+func clientID(r *http.Request) string {
+	if client, ok := r.Context().Value(clientKey{}).(string); ok {
+		return client
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,213 @@
+package rest_test
+
+import (
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/statistico/statistico-ratings/internal/app/grpc"
+	"github.com/statistico/statistico-ratings/internal/app/rest"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestLogger(t *testing.T) {
+	t.Run("logs the method, path, status and duration of each request", func(t *testing.T) {
+		t.Helper()
+
+		logger, hook := test.NewNullLogger()
+		clock := clockwork.NewFakeClock()
+
+		h := rest.RequestLogger(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clock.Advance(40 * time.Millisecond)
+			w.WriteHeader(http.StatusBadRequest)
+		}), logger, clock)
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/teams/abc/ratings", nil))
+
+		assert.Equal(t, 1, len(hook.AllEntries()))
+		assert.Equal(t, logrus.InfoLevel, hook.LastEntry().Level)
+		assert.Equal(t, "http request handled", hook.LastEntry().Message)
+		assert.Equal(t, "GET", hook.LastEntry().Data["method"])
+		assert.Equal(t, "/teams/abc/ratings", hook.LastEntry().Data["path"])
+		assert.Equal(t, http.StatusBadRequest, hook.LastEntry().Data["status"])
+		assert.Equal(t, int64(40), hook.LastEntry().Data["duration_ms"])
+	})
+
+	t.Run("logs server errors as warnings", func(t *testing.T) {
+		t.Helper()
+
+		logger, hook := test.NewNullLogger()
+
+		h := rest.RequestLogger(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}), logger, clockwork.NewFakeClock())
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/teams/5/ratings", nil))
+
+		assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+		assert.Equal(t, "http request failed", hook.LastEntry().Message)
+	})
+}
+
+func TestCORS(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("allows requests from configured origins", func(t *testing.T) {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, "/teams/5/ratings", nil)
+		req.Header.Set("Origin", "https://app.statistico.io")
+		rec := httptest.NewRecorder()
+
+		rest.CORS(ok, []string{"https://app.statistico.io"}).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "https://app.statistico.io", rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("answers preflight requests from configured origins", func(t *testing.T) {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodOptions, "/teams/5/ratings", nil)
+		req.Header.Set("Origin", "https://app.statistico.io")
+		req.Header.Set("Access-Control-Request-Method", "GET")
+		rec := httptest.NewRecorder()
+
+		rest.CORS(ok, []string{"*"}).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "GET, OPTIONS", rec.Header().Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("does not allow requests from other origins", func(t *testing.T) {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, "/teams/5/ratings", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		rec := httptest.NewRecorder()
+
+		rest.CORS(ok, []string{"https://app.statistico.io"}).ServeHTTP(rec, req)
+
+		assert.Equal(t, "", rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	auth := grpc.NewAPIKeyAuthenticator(map[string]string{"odds-service": "abc123"})
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("serves requests with valid credentials", func(t *testing.T) {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, "/teams/5/ratings", nil)
+		req.Header.Set("X-Api-Key", "abc123")
+
+		rec := httptest.NewRecorder()
+
+		rest.Authenticate(ok, auth, nil).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("returns unauthorized without calling the handler if authentication fails", func(t *testing.T) {
+		t.Helper()
+
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("Expected handler not to be called")
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/teams/5/ratings", nil)
+		req.Header.Set("X-Api-Key", "xyz")
+
+		rec := httptest.NewRecorder()
+
+		rest.Authenticate(h, auth, nil).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.JSONEq(t, `{"error":"api key is invalid"}`, rec.Body.String())
+	})
+
+	t.Run("rejects remote addresses without authenticating once failed attempts exceed the rate limit", func(t *testing.T) {
+		t.Helper()
+
+		h := rest.Authenticate(ok, auth, grpc.NewRateLimiter(1, 1, clockwork.NewFakeClock()))
+
+		codes := []int{}
+
+		for _, r := range []struct{ addr, key string }{
+			{"10.0.0.2:5000", "abc123"},
+			{"10.0.0.2:5001", "abc123"},
+			{"10.0.0.1:5000", "xyz"},
+			{"10.0.0.1:5001", "abc123"},
+		} {
+			req := httptest.NewRequest(http.MethodGet, "/teams/5/ratings", nil)
+			req.RemoteAddr = r.addr
+			req.Header.Set("X-Api-Key", r.key)
+
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			codes = append(codes, rec.Code)
+		}
+
+		assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusUnauthorized, http.StatusTooManyRequests}, codes)
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("limits requests by authenticated client", func(t *testing.T) {
+		t.Helper()
+
+		auth := grpc.NewAPIKeyAuthenticator(map[string]string{"odds-service": "abc123", "web": "def456"})
+		h := rest.Authenticate(rest.RateLimit(ok, grpc.NewRateLimiter(1, 1, clockwork.NewFakeClock())), auth, nil)
+
+		codes := []int{}
+
+		for _, key := range []string{"abc123", "abc123", "def456"} {
+			req := httptest.NewRequest(http.MethodGet, "/teams/5/ratings", nil)
+			req.Header.Set("X-Api-Key", key)
+
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			codes = append(codes, rec.Code)
+		}
+
+		assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests, http.StatusOK}, codes)
+	})
+
+	t.Run("limits unauthenticated requests by remote address", func(t *testing.T) {
+		t.Helper()
+
+		h := rest.RateLimit(ok, grpc.NewRateLimiter(1, 1, clockwork.NewFakeClock()))
+
+		codes := []int{}
+
+		for _, addr := range []string{"10.0.0.1:5000", "10.0.0.1:5001", "10.0.0.2:5000"} {
+			req := httptest.NewRequest(http.MethodGet, "/teams/5/ratings", nil)
+			req.RemoteAddr = addr
+
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			codes = append(codes, rec.Code)
+		}
+
+		assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests, http.StatusOK}, codes)
+	})
+}
@@ -0,0 +1,22 @@
+package rest
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"net/http"
+)
+
+// NewServeMux returns a http.ServeMux routing each REST gateway resource to its handler and returning a JSON not
+// found response for any other path.
+func NewServeMux(r team.RatingReader, l *logrus.Logger) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.Handle("/teams/", NewTeamRatingHandler(r, l))
+	mux.Handle("/ratings/latest", NewLatestRatingHandler(r, l))
+	mux.Handle("/fixtures/", NewFixtureRatingHandler(r, l))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "resource does not exist")
+	})
+
+	return mux
+}
@@ -0,0 +1,54 @@
+package rest_test
+
+import (
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/statistico/statistico-ratings/internal/app/rest"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewServeMux(t *testing.T) {
+	t.Run("routes each resource to its handler", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, _ := test.NewNullLogger()
+		mux := rest.NewServeMux(reader, logger)
+
+		reader.On("Get", mock.Anything, mock.AnythingOfType("*team.ReaderQuery")).Return([]*team.Rating{}, nil)
+		reader.On("LatestByTeams", mock.Anything, []uint64{5}, (*time.Time)(nil)).Return([]*team.Rating{}, nil)
+		reader.On("ByFixture", mock.Anything, uint64(10)).Return([]*team.Rating{}, nil)
+
+		for _, path := range []string{"/teams/5/ratings", "/ratings/latest?teams=5", "/fixtures/10/ratings"} {
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+			assert.Equal(t, http.StatusOK, rec.Code, path)
+		}
+
+		reader.AssertExpectations(t)
+	})
+
+	t.Run("returns json not found response for unknown paths", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, _ := test.NewNullLogger()
+		mux := rest.NewServeMux(reader, logger)
+
+		for _, path := range []string{"/", "/players/5", "/ratings/latest/5"} {
+			rec := httptest.NewRecorder()
+
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+			assert.Equal(t, http.StatusNotFound, rec.Code, path)
+			assert.JSONEq(t, `{"error":"resource does not exist"}`, rec.Body.String(), path)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package rest
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type points struct {
+	Points     float64 `json:"points"`
+	Difference float64 `json:"difference"`
+}
+
+type teamRating struct {
+	TeamID        uint64    `json:"teamId"`
+	FixtureID     uint64    `json:"fixtureId"`
+	SeasonID      uint64    `json:"seasonId"`
+	CompetitionID uint64    `json:"competitionId"`
+	Attack        points    `json:"attack"`
+	Defence       points    `json:"defence"`
+	FixtureDate   time.Time `json:"fixtureDate"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+type teamRatingResponse struct {
+	Ratings []teamRating `json:"ratings"`
+}
+
+// TeamRatingHandler serves the ratings of a team at GET /teams/{id}/ratings, the JSON equivalent of
+// TeamRatingService.GetTeamRatings. Ratings are filtered by the optional season and before query parameters and
+// sorted by the optional sort query parameter.
+type TeamRatingHandler struct {
+	reader team.RatingReader
+	logger *logrus.Logger
+}
+
+func (t *TeamRatingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	if len(parts) != 3 || parts[0] != "teams" || parts[2] != "ratings" {
+		writeError(w, http.StatusNotFound, "resource does not exist")
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q, err := teamReaderQuery(parts[1], r)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ratings, err := t.reader.Get(r.Context(), q)
+
+	if err != nil {
+		t.logger.Errorf("Error fetching team ratings: %s", err.Error())
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newTeamRatingResponse(ratings))
+}
+
+func newTeamRatingResponse(ratings []*team.Rating) teamRatingResponse {
+	res := teamRatingResponse{Ratings: []teamRating{}}
+
+	for _, rt := range ratings {
+		res.Ratings = append(res.Ratings, teamRating{
+			TeamID:        rt.TeamID,
+			FixtureID:     rt.FixtureID,
+			SeasonID:      rt.SeasonID,
+			CompetitionID: rt.CompetitionID,
+			Attack: points{
+				Points:     rt.Attack.Total,
+				Difference: rt.Attack.Difference,
+			},
+			Defence: points{
+				Points:     rt.Defence.Total,
+				Difference: rt.Defence.Difference,
+			},
+			FixtureDate: rt.FixtureDate.UTC(),
+			Timestamp:   rt.Timestamp.UTC(),
+		})
+	}
+
+	return res
+}
+
+func teamReaderQuery(id string, r *http.Request) (*team.ReaderQuery, error) {
+	teamID, err := strconv.ParseUint(id, 10, 64)
+
+	if err != nil {
+		return nil, fmt.Errorf("team id %q is not a valid id", id)
+	}
+
+	params := r.URL.Query()
+
+	var seasonID *uint64
+	var before *string
+
+	if s := params.Get("season"); s != "" {
+		id, err := strconv.ParseUint(s, 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("season %q is not a valid id", s)
+		}
+
+		seasonID = &id
+	}
+
+	if b := params.Get("before"); b != "" {
+		before = &b
+	}
+
+	return team.NewReaderQuery(teamID, seasonID, before, params.Get("sort"))
+}
+
+func NewTeamRatingHandler(r team.RatingReader, l *logrus.Logger) *TeamRatingHandler {
+	return &TeamRatingHandler{reader: r, logger: l}
+}
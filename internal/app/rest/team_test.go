@@ -0,0 +1,166 @@
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/statistico/statistico-ratings/internal/app/rest"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTeamRatingHandler_ServeHTTP(t *testing.T) {
+	t.Run("returns team ratings filtered by the query parameters as json", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, _ := test.NewNullLogger()
+		handler := rest.NewTeamRatingHandler(reader, logger)
+
+		ratings := []*team.Rating{
+			{
+				TeamID:        5,
+				FixtureID:     10,
+				SeasonID:      99,
+				CompetitionID: 8,
+				Attack:        team.Points{Total: 1432.12, Difference: 10},
+				Defence:       team.Points{Total: 234, Difference: -23},
+				FixtureDate:   time.Unix(1627226510, 0),
+				Timestamp:     time.Unix(1627226520, 0),
+			},
+		}
+
+		query := mock.MatchedBy(func(q *team.ReaderQuery) bool {
+			assert.Equal(t, uint64(5), *q.TeamID)
+			assert.Equal(t, uint64(99), *q.SeasonID)
+			assert.Equal(t, time.Date(2021, 3, 12, 12, 0, 0, 0, time.UTC).Unix(), q.Before.Unix())
+			assert.Equal(t, "timestamp_desc", q.Sort)
+			return true
+		})
+
+		reader.On("Get", mock.Anything, query).Return(ratings, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/teams/5/ratings?season=99&before=2021-03-12T12:00:00Z&sort=timestamp_desc", nil)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		expected := `{"ratings":[{"teamId":5,"fixtureId":10,"seasonId":99,"competitionId":8,` +
+			`"attack":{"points":1432.12,"difference":10},"defence":{"points":234,"difference":-23},` +
+			`"fixtureDate":"2021-07-25T15:21:50Z","timestamp":"2021-07-25T15:22:00Z"}]}`
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.JSONEq(t, expected, rec.Body.String())
+		reader.AssertExpectations(t)
+	})
+
+	t.Run("returns an empty list if the team has no ratings", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, _ := test.NewNullLogger()
+		handler := rest.NewTeamRatingHandler(reader, logger)
+
+		reader.On("Get", mock.Anything, mock.AnythingOfType("*team.ReaderQuery")).Return([]*team.Rating{}, nil)
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/teams/5/ratings", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"ratings":[]}`, rec.Body.String())
+	})
+
+	t.Run("returns bad request if the query parameters are invalid", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, _ := test.NewNullLogger()
+		handler := rest.NewTeamRatingHandler(reader, logger)
+
+		paths := map[string]string{
+			"/teams/abc/ratings":                `{"error":"team id \"abc\" is not a valid id"}`,
+			"/teams/5/ratings?season=current":   `{"error":"season \"current\" is not a valid id"}`,
+			"/teams/5/ratings?sort=points_desc": `{"error":"sort \"points_desc\" is not supported, use timestamp_asc or timestamp_desc"}`,
+			"/teams/5/ratings?before=yesterday": `{"error":"parsing time \"yesterday\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"yesterday\" as \"2006\""}`,
+		}
+
+		for path, expected := range paths {
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+			assert.JSONEq(t, expected, rec.Body.String(), path)
+		}
+
+		reader.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	})
+
+	t.Run("returns not found for unknown paths and method not allowed for other methods", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, _ := test.NewNullLogger()
+		handler := rest.NewTeamRatingHandler(reader, logger)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/teams/5/fixtures", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/teams/5/ratings", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Equal(t, http.MethodGet, rec.Header().Get("Allow"))
+	})
+
+	t.Run("logs error and returns internal server error if error returned by team rating reader", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockTeamRatingReader)
+		logger, hook := test.NewNullLogger()
+		handler := rest.NewTeamRatingHandler(reader, logger)
+
+		reader.On("Get", mock.Anything, mock.AnythingOfType("*team.ReaderQuery")).Return([]*team.Rating{}, errors.New("oh no"))
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/teams/5/ratings", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.JSONEq(t, `{"error":"internal server error"}`, rec.Body.String())
+		assert.Equal(t, "Error fetching team ratings: oh no", hook.LastEntry().Message)
+	})
+}
+
+type MockTeamRatingReader struct {
+	mock.Mock
+}
+
+func (m *MockTeamRatingReader) LatestByTeams(ctx context.Context, teamIDs []uint64, before *time.Time) ([]*team.Rating, error) {
+	args := m.Called(ctx, teamIDs, before)
+	return args.Get(0).([]*team.Rating), args.Error(1)
+}
+
+func (m *MockTeamRatingReader) ByFixture(ctx context.Context, fixtureID uint64) ([]*team.Rating, error) {
+	args := m.Called(ctx, fixtureID)
+	return args.Get(0).([]*team.Rating), args.Error(1)
+}
+
+func (m *MockTeamRatingReader) Get(ctx context.Context, q *team.ReaderQuery) ([]*team.Rating, error) {
+	args := m.Called(ctx, q)
+	return args.Get(0).([]*team.Rating), args.Error(1)
+}
+
+func (m *MockTeamRatingReader) Latest(ctx context.Context, teamID uint64) (*team.Rating, error) {
+	args := m.Called(ctx, teamID)
+	return args.Get(0).(*team.Rating), args.Error(1)
+}
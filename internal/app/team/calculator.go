@@ -29,6 +29,7 @@ func (r *ratingCalculator) ForFixture(ctx context.Context, f *statistico.Fixture
 	}
 
 	hg, ag := calculate.AdjustedGoals(f.HomeTeam.Id, f.AwayTeam.Id, events.Goals, events.Cards)
+	homeGoals, awayGoals := calculate.Goals(f.HomeTeam.Id, f.AwayTeam.Id, events.Goals)
 
 	k := r.kFactorMapping[f.Competition.Id]
 
@@ -36,16 +37,34 @@ func (r *ratingCalculator) ForFixture(ctx context.Context, f *statistico.Fixture
 	ap := calculate.PointsValue(away.Attack.Total, home.Defence.Total, k, ag)
 
 	newHome := r.applyRating(home, f, f.Season.Id, hp, ap)
+	newHome.Calculation = &Calculation{
+		Goals:           homeGoals,
+		AdjustedGoals:   hg,
+		KFactor:         k,
+		OpponentAttack:  away.Attack.Total,
+		OpponentDefence: away.Defence.Total,
+		RuleVersion:     calculate.RuleVersion,
+	}
+
 	newAway := r.applyRating(away, f, f.Season.Id, ap, hp)
+	newAway.Calculation = &Calculation{
+		Goals:           awayGoals,
+		AdjustedGoals:   ag,
+		KFactor:         k,
+		OpponentAttack:  home.Attack.Total,
+		OpponentDefence: home.Defence.Total,
+		RuleVersion:     calculate.RuleVersion,
+	}
 
 	return newHome, newAway, nil
 }
 
 func (r *ratingCalculator) applyRating(rt *Rating, fixture *statistico.Fixture, seasonID uint64, attack, defence float64) *Rating {
 	return &Rating{
-		TeamID:    rt.TeamID,
-		FixtureID: uint64(fixture.Id),
-		SeasonID:  seasonID,
+		TeamID:        rt.TeamID,
+		FixtureID:     uint64(fixture.Id),
+		SeasonID:      seasonID,
+		CompetitionID: fixture.Competition.Id,
 		Attack: Points{
 			Total:      rt.Attack.Total + attack,
 			Difference: attack,
@@ -114,22 +114,40 @@ func TestRatingCalculator_ForFixture(t *testing.T) {
 		a.Equal(uint64(1), newHome.TeamID)
 		a.Equal(uint64(26), newHome.FixtureID)
 		a.Equal(uint64(17462), newHome.SeasonID)
+		a.Equal(uint64(8), newHome.CompetitionID)
 		a.Equal(1245.04, newHome.Attack.Total)
 		a.Equal(19.37, newHome.Attack.Difference)
 		a.Equal(1240.82, newHome.Defence.Total)
 		a.Equal(12.35, newHome.Defence.Difference)
 		a.Equal(time.Unix(1630343736, 0), newHome.FixtureDate)
 		a.Equal(time.Date(1984, time.April, 4, 0, 0, 0, 0, time.UTC), newHome.Timestamp)
+		a.Equal(&team.Calculation{
+			Goals:           3,
+			AdjustedGoals:   2.5,
+			KFactor:         5,
+			OpponentAttack:  1518.33,
+			OpponentDefence: 790.72,
+			RuleVersion:     1,
+		}, newHome.Calculation)
 
 		a.Equal(uint64(8), newAway.TeamID)
 		a.Equal(uint64(26), newAway.FixtureID)
 		a.Equal(uint64(17462), newAway.SeasonID)
+		a.Equal(uint64(8), newAway.CompetitionID)
 		a.Equal(1530.6799999999998, newAway.Attack.Total)
 		a.Equal(12.35, newAway.Attack.Difference)
 		a.Equal(810.09, newAway.Defence.Total)
 		a.Equal(19.37, newAway.Defence.Difference)
 		a.Equal(time.Unix(1630343736, 0), newAway.FixtureDate)
 		a.Equal(time.Date(1984, time.April, 4, 0, 0, 0, 0, time.UTC), newHome.Timestamp)
+		a.Equal(&team.Calculation{
+			Goals:           2,
+			AdjustedGoals:   2,
+			KFactor:         5,
+			OpponentAttack:  1225.67,
+			OpponentDefence: 1228.47,
+			RuleVersion:     1,
+		}, newAway.Calculation)
 	})
 
 	t.Run("returns an error if returned by event client", func(t *testing.T) {
@@ -0,0 +1,168 @@
+package team
+
+import (
+	"context"
+	"fmt"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app"
+	"sync"
+	"time"
+)
+
+// DaemonConfig configures how often and for which fixtures a Daemon processes team ratings.
+type DaemonConfig struct {
+	// Interval is the time waited between polls for fixtures.
+	Interval time.Duration
+	// Window is how far before the current time to look for fixtures that have not been rated.
+	Window time.Duration
+	// FullTime is the time after kick off a fixture is treated as finished and rated.
+	FullTime time.Duration
+	// MaxBackoff is the longest time waited between polls after consecutive failed polls.
+	MaxBackoff time.Duration
+	// ShutdownTimeout is the time a poll in progress when the Daemon is stopped is given to complete before
+	// requests it has in flight are cancelled.
+	ShutdownTimeout time.Duration
+}
+
+// Daemon polls for fixtures that have reached full time and processes team ratings for them.
+type Daemon struct {
+	handler RatingHandler
+	config  DaemonConfig
+	rated   map[int64]time.Time
+}
+
+// Run polls for and processes fixtures every config interval until ctx is cancelled. A poll fails if fixtures
+// cannot be fetched or any fixture fails to process, and consecutive failed polls double the time waited before the
+// next poll up to the config max backoff. A poll in progress when ctx is cancelled is given the config shutdown
+// timeout to complete before it is cancelled.
+func (d *Daemon) Run(ctx context.Context) {
+	pollCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stopped := make(chan struct{})
+	defer close(stopped)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stopped:
+			return
+		}
+
+		select {
+		case <-d.handler.clock.After(d.config.ShutdownTimeout):
+			d.handler.logger.Warn("team rating daemon shutdown timeout reached, cancelling poll in progress")
+			cancel()
+		case <-stopped:
+		}
+	}()
+
+	failures := 0
+
+	for {
+		wait := d.config.Interval
+
+		if err := d.poll(pollCtx); err != nil {
+			failures++
+			wait = d.backoff(failures)
+
+			d.handler.logger.Errorf("error polling fixtures in team rating daemon, retrying in %s: %s", wait, err.Error())
+		} else {
+			failures = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			d.handler.logger.Info("team rating daemon stopped")
+			return
+		case <-d.handler.clock.After(wait):
+		}
+	}
+}
+
+// poll processes fixtures kicking off within the config window that have reached full time and have not already
+// been rated by the Daemon. Fixtures rated in previous runs are skipped by the processor. An error is returned if
+// fixtures cannot be fetched or the status of any fixture cannot be checked, so data service outages back off
+// polling. Other failures are logged and the fixture retried on the next poll without backing off, as a fixture that
+// cannot be rated, such as one out of date order, would otherwise hold back every other fixture in the window.
+func (d *Daemon) poll(ctx context.Context) error {
+	now := d.handler.clock.Now()
+
+	fixtures, err := d.handler.fetcher.ByDate(ctx, now.Add(-d.config.Window), now.Add(-d.config.FullTime))
+
+	if err != nil {
+		return err
+	}
+
+	for id, kickOff := range d.rated {
+		if kickOff.Before(now.Add(-d.config.Window)) {
+			delete(d.rated, id)
+		}
+	}
+
+	var pending []*statistico.Fixture
+
+	for _, f := range fixtures {
+		if _, ok := d.rated[f.GetId()]; !ok {
+			pending = append(pending, f)
+		}
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	var lock sync.Mutex
+	var unchecked int
+
+	report := d.handler.process(ctx, pending, true, func(f *statistico.Fixture, err error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		switch err.(type) {
+		case nil, *app.DuplicationError:
+			d.rated[f.GetId()] = time.Unix(f.GetDateTime().GetUtc(), 0)
+		case *app.StatusCheckError:
+			unchecked++
+		}
+	})
+
+	d.handler.logger.Infof(
+		"team rating daemon processed %d, skipped %d and failed %d fixtures",
+		report.Processed,
+		report.Skipped,
+		report.Failed,
+	)
+
+	if unchecked > 0 {
+		return fmt.Errorf("status of %d of %d fixtures could not be checked", unchecked, len(pending))
+	}
+
+	return nil
+}
+
+func (d *Daemon) backoff(failures int) time.Duration {
+	wait := d.config.Interval
+
+	for i := 0; i < failures && wait < d.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+
+	if wait > d.config.MaxBackoff {
+		return d.config.MaxBackoff
+	}
+
+	return wait
+}
+
+func NewDaemon(h RatingHandler, c DaemonConfig) *Daemon {
+	if c.MaxBackoff < c.Interval {
+		c.MaxBackoff = c.Interval
+	}
+
+	return &Daemon{
+		handler: h,
+		config:  c,
+		rated:   map[int64]time.Time{},
+	}
+}
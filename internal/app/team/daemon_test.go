@@ -0,0 +1,316 @@
+package team_test
+
+import (
+	"context"
+	"errors"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app/fixture"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDaemon_Run(t *testing.T) {
+	config := team.DaemonConfig{
+		Interval:        5 * time.Minute,
+		Window:          24 * time.Hour,
+		FullTime:        2 * time.Hour,
+		MaxBackoff:      15 * time.Minute,
+		ShutdownTimeout: 30 * time.Second,
+	}
+
+	t.Run("processes fixtures that have reached full time once only", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		now := time.Date(2021, 3, 13, 20, 0, 0, 0, time.UTC)
+		clock := clockwork.NewFakeClockAt(now)
+		logger, _ := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		daemon := team.NewDaemon(handler, config)
+
+		fix1 := statistico.Fixture{Id: 1, DateTime: &statistico.Date{Utc: 1615636800}}
+		fix2 := statistico.Fixture{Id: 2, DateTime: &statistico.Date{Utc: 1615640400}}
+		fix3 := statistico.Fixture{Id: 3, DateTime: &statistico.Date{Utc: 1615640700}}
+
+		fetcher.On("ByDate", mock.Anything, now.Add(-24*time.Hour), now.Add(-2*time.Hour)).
+			Return([]*statistico.Fixture{&fix1, &fix2}, nil)
+
+		next := now.Add(5 * time.Minute)
+
+		fetcher.On("ByDate", mock.Anything, next.Add(-24*time.Hour), next.Add(-2*time.Hour)).
+			Return([]*statistico.Fixture{&fix1, &fix2, &fix3}, nil)
+
+		prefetcher.On("Prefetch", mock.Anything, mock.Anything)
+		prefetcher.On("Discard", mock.Anything)
+
+		processor.On("ByFixture", mock.Anything, &fix1).Once().Return(nil)
+		processor.On("ByFixture", mock.Anything, &fix2).Once().Return(nil)
+		processor.On("ByFixture", mock.Anything, &fix3).Once().Return(nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+
+		go func() {
+			daemon.Run(ctx)
+			close(done)
+		}()
+
+		clock.BlockUntil(1)
+		clock.Advance(5 * time.Minute)
+		clock.BlockUntil(1)
+
+		cancel()
+		<-done
+
+		fetcher.AssertExpectations(t)
+		processor.AssertExpectations(t)
+	})
+
+	t.Run("fetches events for a fixture once it has finished on a later poll", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		status := new(MockStatusChecker)
+		processor := new(MockTeamRatingProcessor)
+		events := new(MockEventClient)
+		jobs := new(MockJobRepository)
+		now := time.Date(2021, 3, 13, 20, 0, 0, 0, time.UTC)
+		clock := clockwork.NewFakeClockAt(now)
+		logger, _ := test.NewNullLogger()
+
+		prefetcher := team.NewEventPrefetcher(events, 1)
+
+		handler := team.NewHandler(fetcher, status, processor, prefetcher, jobs, 1, clock, logger)
+
+		daemon := team.NewDaemon(handler, config)
+
+		fix := statistico.Fixture{Id: 1, DateTime: &statistico.Date{Utc: 1615636800}}
+		res := statistico.FixtureEventsResponse{FixtureId: 1, Goals: []*statistico.GoalEvent{{TeamId: 1, Minute: 88}}}
+
+		var finished int32
+
+		fetcher.On("ByDate", mock.Anything, mock.Anything, mock.Anything).Return([]*statistico.Fixture{&fix}, nil)
+
+		status.On("Finished", mock.Anything, &fix).Once().Return(false, "result does not have a full time score", nil)
+		status.On("Finished", mock.Anything, &fix).Once().Return(true, "", nil).Run(func(args mock.Arguments) {
+			atomic.StoreInt32(&finished, 1)
+		})
+
+		events.On("FixtureEvents", mock.Anything, uint64(1)).Once().Return(&res, nil).Run(func(args mock.Arguments) {
+			assert.Equal(t, int32(1), atomic.LoadInt32(&finished), "events fetched before the fixture finished")
+		})
+
+		processor.On("ByFixture", mock.Anything, &fix).Once().Return(nil).Run(func(args mock.Arguments) {
+			fetched, err := prefetcher.FixtureEvents(args.Get(0).(context.Context), 1)
+
+			if err != nil {
+				t.Fatalf("Expected nil, got %s", err.Error())
+			}
+
+			assert.Equal(t, &res, fetched)
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+
+		go func() {
+			daemon.Run(ctx)
+			close(done)
+		}()
+
+		clock.BlockUntil(1)
+		clock.Advance(5 * time.Minute)
+		clock.BlockUntil(1)
+
+		cancel()
+		<-done
+
+		status.AssertExpectations(t)
+		events.AssertExpectations(t)
+		processor.AssertExpectations(t)
+	})
+
+	t.Run("backs off polling after errors fetching fixtures", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Date(2021, 3, 13, 20, 0, 0, 0, time.UTC))
+		logger, hook := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		daemon := team.NewDaemon(handler, config)
+
+		fetcher.On("ByDate", mock.Anything, mock.Anything, mock.Anything).
+			Return([]*statistico.Fixture{}, errors.New("fixture fetcher error"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+
+		go func() {
+			daemon.Run(ctx)
+			close(done)
+		}()
+
+		clock.BlockUntil(1)
+		clock.Advance(5 * time.Minute)
+		clock.BlockUntil(1)
+
+		fetcher.AssertNumberOfCalls(t, "ByDate", 1)
+
+		clock.Advance(5 * time.Minute)
+		clock.BlockUntil(1)
+
+		fetcher.AssertNumberOfCalls(t, "ByDate", 2)
+		assert.Equal(t, "error polling fixtures in team rating daemon, retrying in 15m0s: fixture fetcher error", hook.LastEntry().Message)
+
+		cancel()
+		<-done
+
+		assert.Equal(t, "team rating daemon stopped", hook.LastEntry().Message)
+		processor.AssertNotCalled(t, "ByFixture", mock.Anything, mock.Anything)
+	})
+
+	t.Run("backs off polling if the status of fixtures cannot be checked", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		status := new(MockStatusChecker)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Date(2021, 3, 13, 20, 0, 0, 0, time.UTC))
+		logger, hook := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, status, processor, prefetcher, jobs, 1, clock, logger)
+
+		daemon := team.NewDaemon(handler, config)
+
+		fix := statistico.Fixture{Id: 1, DateTime: &statistico.Date{Utc: 1615636800}}
+
+		fetcher.On("ByDate", mock.Anything, mock.Anything, mock.Anything).Return([]*statistico.Fixture{&fix}, nil)
+		status.On("Finished", mock.Anything, &fix).Return(false, "", errors.New("result client error"))
+		prefetcher.On("Prefetch", mock.Anything, mock.Anything)
+		prefetcher.On("Discard", mock.Anything)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+
+		go func() {
+			daemon.Run(ctx)
+			close(done)
+		}()
+
+		clock.BlockUntil(1)
+
+		assert.Equal(t, "error polling fixtures in team rating daemon, retrying in 10m0s: status of 1 of 1 fixtures could not be checked", hook.LastEntry().Message)
+		processor.AssertNotCalled(t, "ByFixture", mock.Anything, mock.Anything)
+
+		cancel()
+		<-done
+	})
+
+	t.Run("does not back off polling after fixtures fail to process", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Date(2021, 3, 13, 20, 0, 0, 0, time.UTC))
+		logger, hook := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		daemon := team.NewDaemon(handler, config)
+
+		fix := statistico.Fixture{Id: 1, DateTime: &statistico.Date{Utc: 1615636800}}
+
+		fetcher.On("ByDate", mock.Anything, mock.Anything, mock.Anything).Return([]*statistico.Fixture{&fix}, nil)
+		prefetcher.On("Prefetch", mock.Anything, mock.Anything)
+		prefetcher.On("Discard", mock.Anything)
+		processor.On("ByFixture", mock.Anything, &fix).Return(errors.New("team 1 has a rating for a later fixture"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+
+		go func() {
+			daemon.Run(ctx)
+			close(done)
+		}()
+
+		clock.BlockUntil(1)
+
+		assert.Equal(t, "team rating daemon processed 0, skipped 0 and failed 1 fixtures", hook.LastEntry().Message)
+
+		clock.Advance(5 * time.Minute)
+		clock.BlockUntil(1)
+
+		fetcher.AssertNumberOfCalls(t, "ByDate", 2)
+		processor.AssertNumberOfCalls(t, "ByFixture", 2)
+
+		cancel()
+		<-done
+	})
+
+	t.Run("cancels a poll in progress once the shutdown timeout is reached", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Date(2021, 3, 13, 20, 0, 0, 0, time.UTC))
+		logger, hook := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		daemon := team.NewDaemon(handler, config)
+
+		fix := statistico.Fixture{Id: 1, DateTime: &statistico.Date{Utc: 1615636800}}
+
+		started := make(chan struct{})
+
+		fetcher.On("ByDate", mock.Anything, mock.Anything, mock.Anything).Return([]*statistico.Fixture{&fix}, nil)
+		prefetcher.On("Prefetch", mock.Anything, mock.Anything)
+		prefetcher.On("Discard", mock.Anything)
+		processor.On("ByFixture", mock.Anything, &fix).Return(context.Canceled).Run(func(args mock.Arguments) {
+			close(started)
+			<-args.Get(0).(context.Context).Done()
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+
+		go func() {
+			daemon.Run(ctx)
+			close(done)
+		}()
+
+		<-started
+
+		cancel()
+
+		clock.BlockUntil(1)
+		clock.Advance(30 * time.Second)
+
+		<-done
+
+		assert.Equal(t, "team rating daemon stopped", hook.LastEntry().Message)
+		processor.AssertNumberOfCalls(t, "ByFixture", 1)
+	})
+}
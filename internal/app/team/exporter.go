@@ -0,0 +1,86 @@
+package team
+
+import (
+	"context"
+	"database/sql"
+	sq "github.com/Masterminds/squirrel"
+)
+
+type RatingExporter interface {
+	// Export calls fn for each Rating matching the query in order of fixture date, stopping at the first error
+	// returned. Ratings are read from the database as fn is called so exports are not held in memory.
+	Export(ctx context.Context, q *ExportQuery, fn func(r *Rating) error) error
+	// Unassigned returns the number of Ratings matching the query, ignoring its competition, that have no
+	// competition recorded and are therefore never exported when filtering by competition.
+	Unassigned(ctx context.Context, q *ExportQuery) (uint64, error)
+}
+
+type ratingExporter struct {
+	connection *sql.DB
+}
+
+func (r *ratingExporter) Export(ctx context.Context, q *ExportQuery, fn func(r *Rating) error) error {
+	query := selectRatings(r.connection).OrderBy("fixture_date ASC", "fixture_id ASC", "team_id ASC")
+
+	if q.CompetitionID != nil {
+		query = query.Where(sq.Eq{"competition_id": q.CompetitionID})
+	}
+
+	rows, err := filterExport(query, q).QueryContext(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		rating, err := scanRating(rows)
+
+		if err != nil {
+			return err
+		}
+
+		if err := fn(rating); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *ratingExporter) Unassigned(ctx context.Context, q *ExportQuery) (uint64, error) {
+	query := queryBuilder(r.connection).
+		Select("COUNT(*)").
+		From("team_rating").
+		Where(sq.Eq{"competition_id": 0})
+
+	var count uint64
+
+	if err := filterExport(query, q).QueryRowContext(ctx).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// filterExport applies the season and fixture date filters of the query, leaving the competition to the caller.
+func filterExport(query sq.SelectBuilder, q *ExportQuery) sq.SelectBuilder {
+	if q.SeasonID != nil {
+		query = query.Where(sq.Eq{"season_id": q.SeasonID})
+	}
+
+	if q.From != nil {
+		query = query.Where(sq.GtOrEq{"fixture_date": q.From.Unix()})
+	}
+
+	if q.To != nil {
+		query = query.Where(sq.Lt{"fixture_date": q.To.Unix()})
+	}
+
+	return query
+}
+
+func NewRatingExporter(c *sql.DB) RatingExporter {
+	return &ratingExporter{connection: c}
+}
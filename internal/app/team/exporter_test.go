@@ -0,0 +1,164 @@
+package team_test
+
+import (
+	"context"
+	"errors"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"github.com/statistico/statistico-ratings/internal/app/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRatingExporter_Export(t *testing.T) {
+	conn, cleanUp := test.GetConnection(t, []string{"team_rating"})
+	ctx := context.Background()
+	writer := team.NewRatingWriter(conn)
+	exporter := team.NewRatingExporter(conn)
+
+	t.Run("exports all ratings ordered by fixture date", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		insertExportRatings(t, writer)
+
+		ratings := export(t, exporter, &team.ExportQuery{})
+
+		a := assert.New(t)
+
+		a.Equal(4, len(ratings))
+		a.Equal([]uint64{10, 11, 11, 12}, fixtureIDs(ratings))
+		a.Equal(uint64(1), ratings[1].TeamID)
+		a.Equal(uint64(2), ratings[2].TeamID)
+	})
+
+	t.Run("exports ratings filtered by competition and season", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		insertExportRatings(t, writer)
+
+		competition := uint64(8)
+		season := uint64(17420)
+
+		ratings := export(t, exporter, &team.ExportQuery{CompetitionID: &competition, SeasonID: &season})
+
+		assert.Equal(t, []uint64{11, 11}, fixtureIDs(ratings))
+	})
+
+	t.Run("exports ratings for fixtures played on or after from and before to", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		insertExportRatings(t, writer)
+
+		from := time.Unix(1625163423, 0)
+		to := time.Unix(1625164423, 0)
+
+		ratings := export(t, exporter, &team.ExportQuery{From: &from, To: &to})
+
+		assert.Equal(t, []uint64{11, 11}, fixtureIDs(ratings))
+	})
+
+	t.Run("counts ratings matching the query other than competition that have no competition", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		insertExportRatings(t, writer)
+
+		ratings := []*team.Rating{
+			{TeamID: 5, FixtureID: 9, SeasonID: 17420, FixtureDate: time.Unix(1625161423, 0)},
+			{TeamID: 6, FixtureID: 8, SeasonID: 16036, FixtureDate: time.Unix(1625160423, 0)},
+		}
+
+		for _, r := range ratings {
+			r.Timestamp = r.FixtureDate
+
+			if err := writer.Insert(ctx, r); err != nil {
+				t.Fatalf("Error inserting team rating: %s", err.Error())
+			}
+		}
+
+		competition := uint64(8)
+		season := uint64(17420)
+
+		count, err := exporter.Unassigned(ctx, &team.ExportQuery{CompetitionID: &competition, SeasonID: &season})
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, uint64(1), count)
+
+		count, err = exporter.Unassigned(ctx, &team.ExportQuery{CompetitionID: &competition})
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, uint64(2), count)
+	})
+
+	t.Run("stops exporting and returns error returned by fn", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		insertExportRatings(t, writer)
+
+		calls := 0
+
+		err := exporter.Export(ctx, &team.ExportQuery{}, func(r *team.Rating) error {
+			calls++
+			return errors.New("disk full")
+		})
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "disk full", err.Error())
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func export(t *testing.T, e team.RatingExporter, q *team.ExportQuery) []*team.Rating {
+	var ratings []*team.Rating
+
+	err := e.Export(context.Background(), q, func(r *team.Rating) error {
+		ratings = append(ratings, r)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Expected nil, got %s", err.Error())
+	}
+
+	return ratings
+}
+
+func fixtureIDs(ratings []*team.Rating) []uint64 {
+	var ids []uint64
+
+	for _, r := range ratings {
+		ids = append(ids, r.FixtureID)
+	}
+
+	return ids
+}
+
+func insertExportRatings(t *testing.T, w team.RatingWriter) {
+	ratings := []*team.Rating{
+		{TeamID: 2, FixtureID: 11, SeasonID: 17420, CompetitionID: 8, FixtureDate: time.Unix(1625163423, 0)},
+		{TeamID: 1, FixtureID: 11, SeasonID: 17420, CompetitionID: 8, FixtureDate: time.Unix(1625163423, 0)},
+		{TeamID: 3, FixtureID: 12, SeasonID: 17420, CompetitionID: 9, FixtureDate: time.Unix(1625164423, 0)},
+		{TeamID: 4, FixtureID: 10, SeasonID: 16036, CompetitionID: 8, FixtureDate: time.Unix(1625162423, 0)},
+	}
+
+	for _, r := range ratings {
+		r.Timestamp = r.FixtureDate
+
+		if err := w.Insert(context.Background(), r); err != nil {
+			t.Fatalf("Error inserting team rating: %s", err.Error())
+		}
+	}
+}
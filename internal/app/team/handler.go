@@ -3,77 +3,531 @@ package team
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/jonboulle/clockwork"
 	"github.com/sirupsen/logrus"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app"
 	"github.com/statistico/statistico-ratings/internal/app/fixture"
+	"github.com/statistico/statistico-ratings/internal/app/job"
+	"sort"
+	"sync"
 	"time"
 )
 
 type RatingHandler struct {
-	fetcher   fixture.Fetcher
-	processor RatingProcessor
-	clock     clockwork.Clock
-	logger    *logrus.Logger
+	fetcher     fixture.Fetcher
+	status      fixture.StatusChecker
+	processor   RatingProcessor
+	prefetcher  EventPrefetcher
+	jobs        job.Repository
+	concurrency int
+	clock       clockwork.Clock
+	logger      *logrus.Logger
 }
 
-func (r *RatingHandler) ByCompetition(ctx context.Context, competitionID, seasonID uint64) {
+// ByCompetition processes team ratings for a competition and season, recording the status of each fixture against
+// a job. If an incomplete job exists for the competition and season it is resumed and fixtures already processed
+// by the job are not processed again. An error is returned if fixtures cannot be fetched or the job started.
+func (r *RatingHandler) ByCompetition(ctx context.Context, competitionID, seasonID uint64) (*Report, error) {
 	fixtures, err := r.fetcher.ByCompetition(ctx, competitionID, seasonID)
 
 	if err != nil {
 		r.logger.Errorf("error fetching fixtures in team rating handler: %s", err.Error())
-		return
+		return nil, err
+	}
+
+	j, processed, err := r.startJob(ctx, competitionID, seasonID, fixtures)
+
+	if err != nil {
+		r.logger.Errorf("error starting job in team rating handler: %s", err.Error())
+		return nil, err
+	}
+
+	var remaining []*statistico.Fixture
+
+	for _, f := range fixtures {
+		if !processed[uint64(f.Id)] {
+			remaining = append(remaining, f)
+		}
+	}
+
+	report := r.process(ctx, remaining, true, func(f *statistico.Fixture, err error) {
+		r.record(ctx, j.ID, f, err)
+	})
+
+	r.completeJob(ctx, j.ID)
+
+	return report, nil
+}
+
+// Competition processes team ratings for the current season and the seasons before it for a competition, up to
+// seasons in total, starting with the oldest season. Errors processing a season are recorded in the returned Report
+// and the remaining seasons are still processed.
+func (r *RatingHandler) Competition(ctx context.Context, competitionID uint64, seasons int) (*Report, error) {
+	ids, err := r.fetcher.Seasons(ctx, competitionID, seasons)
+
+	if err != nil {
+		r.logger.Errorf("error fetching seasons in team rating handler: %s", err.Error())
+		return nil, err
 	}
 
-	for _, fix := range fixtures {
-		err := r.processor.ByFixture(ctx, fix)
+	report := NewReport()
+
+	for _, id := range ids {
+		season, err := r.ByCompetition(ctx, competitionID, id)
 
 		if err != nil {
-			r.logger.Errorf("error processing fixtures in team rating handler: %s", err.Error())
-			return
+			report.AddError(fmt.Errorf("competition %d and season %d: %s", competitionID, id, err.Error()))
+			continue
 		}
+
+		report.Merge(season)
 	}
 
-	return
+	return report, nil
 }
 
-// Today processes team ratings for the days fixtures. The hour argument is used to determine what hour of the
-// day the ratings are to be processed i.e. hour 20 means process all fixture ratings for fixtures before 8pm
-func (r *RatingHandler) Today(ctx context.Context, hour int) error {
-	now := r.clock.Now()
+// RetryFailed processes each fixture that failed in a previous job, completing any job that has no remaining
+// pending or failed fixtures once retried.
+func (r *RatingHandler) RetryFailed(ctx context.Context) (*Report, error) {
+	failed, err := r.jobs.Failed(ctx)
+
+	if err != nil {
+		r.logger.Errorf("error fetching failed fixtures in team rating handler: %s", err.Error())
+		return nil, err
+	}
+
+	report := NewReport()
+
+	var fixtures []*statistico.Fixture
+	var jobIDs []uint64
+
+	jobs := map[uint64][]uint64{}
+
+	for _, f := range failed {
+		if _, ok := jobs[f.FixtureID]; !ok {
+			fix, err := r.fetcher.ByID(ctx, f.FixtureID)
+
+			if err != nil {
+				r.logger.Errorf("error fetching fixture %d in team rating handler: %s", f.FixtureID, err.Error())
+				report.Failed++
+				report.Errors = append(report.Errors, ReportError{FixtureID: f.FixtureID, Error: err.Error()})
+				continue
+			}
+
+			fixtures = append(fixtures, fix)
+		}
+
+		if !containsID(jobIDs, f.JobID) {
+			jobIDs = append(jobIDs, f.JobID)
+		}
 
-	if hour > now.Hour() {
-		return errors.New("hour provided is greater than the current time hour")
+		jobs[f.FixtureID] = append(jobs[f.FixtureID], f.JobID)
 	}
 
+	sort.SliceStable(fixtures, func(i, j int) bool {
+		return fixtures[i].GetDateTime().GetUtc() < fixtures[j].GetDateTime().GetUtc()
+	})
+
+	report.Merge(r.process(ctx, fixtures, true, func(f *statistico.Fixture, err error) {
+		for _, id := range jobs[uint64(f.Id)] {
+			r.record(ctx, id, f, err)
+		}
+	}))
+
+	for _, id := range jobIDs {
+		r.completeJob(ctx, id)
+	}
+
+	return report, nil
+}
+
+// Today processes team ratings for fixtures played between the start of the day and the hour provided by the query,
+// both evaluated in the query location so the window follows daylight saving changes. If the hour has not yet been
+// reached today the previous day is processed, so a run shortly after midnight completes the previous day.
+func (r *RatingHandler) Today(ctx context.Context, q *TodayQuery) (*Report, error) {
+	if q.Hour < 0 || q.Hour > 23 {
+		return nil, errors.New("hour provided must be between 0 and 23")
+	}
+
+	loc := q.Location
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	now := r.clock.Now().In(loc)
+
 	year, month, day := now.Date()
 
-	start := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
-	end := time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	if q.Hour > now.Hour() {
+		year, month, day = now.AddDate(0, 0, -1).Date()
+	}
+
+	start := time.Date(year, month, day, 0, 0, 0, 0, loc).Add(-q.Lookback).UTC()
+	end := time.Date(year, month, day, q.Hour, 0, 0, 0, loc).UTC()
 
 	fixtures, err := r.fetcher.ByDate(ctx, start, end)
 
 	if err != nil {
 		r.logger.Errorf("error fetching fixtures in team rating handler: %s", err.Error())
-		return err
+		return nil, err
+	}
+
+	return r.process(ctx, fixtures, false, nil), nil
+}
+
+// LastHours processes team ratings for fixtures played in the number of hours up to the current time.
+func (r *RatingHandler) LastHours(ctx context.Context, hours int) (*Report, error) {
+	if hours < 1 {
+		return nil, errors.New("hours provided must be greater than 0")
+	}
+
+	now := r.clock.Now().UTC()
+
+	return r.ByDateRange(ctx, now.Add(-time.Duration(hours)*time.Hour), now)
+}
+
+// ByDateRange processes team ratings for supported competition fixtures played between from and to. Windows spanning
+// multiple days are fetched a day at a time and fixtures are processed in date order across the whole window. A to
+// date in the future is limited to the current time. Fixtures with existing ratings are skipped, so the same window
+// can be processed more than once.
+func (r *RatingHandler) ByDateRange(ctx context.Context, from, to time.Time) (*Report, error) {
+	if now := r.clock.Now(); to.After(now) {
+		to = now
+	}
+
+	if !from.Before(to) {
+		return nil, errors.New("from date provided must be before the to date and current time")
 	}
 
-	for _, fix := range fixtures {
-		err := r.processor.ByFixture(ctx, fix)
+	var fixtures []*statistico.Fixture
+
+	seen := map[int64]bool{}
+
+	for start := from; start.Before(to); start = start.Add(24 * time.Hour) {
+		end := start.Add(24 * time.Hour)
+
+		if end.After(to) {
+			end = to
+		}
+
+		day, err := r.fetcher.ByDate(ctx, start, end)
 
 		if err != nil {
-			r.logger.Errorf("error processing fixtures in team rating handler: %s", err.Error())
-			continue
+			r.logger.Errorf("error fetching fixtures in team rating handler: %s", err.Error())
+			return nil, err
+		}
+
+		for _, f := range day {
+			if !seen[f.GetId()] {
+				seen[f.GetId()] = true
+				fixtures = append(fixtures, f)
+			}
 		}
 	}
 
+	sort.SliceStable(fixtures, func(i, j int) bool {
+		return fixtures[i].GetDateTime().GetUtc() < fixtures[j].GetDateTime().GetUtc()
+	})
+
+	return r.process(ctx, fixtures, true, nil), nil
+}
+
+// process rates fixtures using up to r.concurrency workers. Fixtures are provided in date order and a fixture is
+// only processed once every earlier fixture involving either of its teams has been processed, so each team's
+// ratings are always calculated in date order. If blockOnError is true a fixture is not processed if an earlier
+// fixture involving either of its teams failed or was itself not processed. If provided, record is called with the
+// outcome of each fixture processed. Events are prefetched for finished fixtures only and events prefetched for
+// fixtures left unprocessed are discarded before returning.
+func (r *RatingHandler) process(ctx context.Context, fixtures []*statistico.Fixture, blockOnError bool, record func(f *statistico.Fixture, err error)) *Report {
+	finished, statuses := r.checkStatus(ctx, fixtures)
+
+	r.prefetcher.Prefetch(ctx, finished)
+	defer r.prefetcher.Discard(finished)
+
+	report := NewReport()
+
+	type task struct {
+		done chan struct{}
+		ok   bool
+	}
+
+	workers := make(chan struct{}, r.concurrency)
+	latest := map[uint64]*task{}
+
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+
+	for i, fix := range fixtures {
+		deps := []*task{latest[fix.GetHomeTeam().GetId()], latest[fix.GetAwayTeam().GetId()]}
+
+		t := &task{done: make(chan struct{})}
+
+		latest[fix.GetHomeTeam().GetId()] = t
+		latest[fix.GetAwayTeam().GetId()] = t
+
+		wg.Add(1)
+
+		go func(fix *statistico.Fixture, status error, deps []*task, t *task) {
+			defer wg.Done()
+			defer close(t.done)
+
+			for _, d := range deps {
+				if d == nil {
+					continue
+				}
+
+				<-d.done
+
+				if !d.ok && blockOnError {
+					lock.Lock()
+					report.Pending++
+					lock.Unlock()
+					return
+				}
+			}
+
+			workers <- struct{}{}
+			defer func() { <-workers }()
+
+			// Fixtures not yet started when ctx is cancelled are left pending so they are processed by a later run.
+			if ctx.Err() != nil {
+				lock.Lock()
+				report.Pending++
+				lock.Unlock()
+				return
+			}
+
+			err := status
+
+			if err == nil {
+				err = r.processor.ByFixture(ctx, fix)
+			}
+
+			lock.Lock()
+
+			switch e := err.(type) {
+			case nil:
+				t.ok = true
+				report.Processed++
+			case *app.DuplicationError:
+				r.logger.Warnf("fixture %d skipped in team rating handler: %s", fix.GetId(), err.Error())
+				t.ok = true
+				report.Skipped++
+			case *app.UnfinishedError:
+				r.logger.Infof("fixture %d skipped in team rating handler: %s", fix.GetId(), e.Reason)
+				t.ok = true
+				report.Unfinished++
+				report.Skips = append(report.Skips, ReportSkip{FixtureID: e.FixtureID, Reason: e.Reason})
+			default:
+				r.logger.Errorf("error processing fixtures in team rating handler: %s", err.Error())
+				report.Failed++
+				report.Errors = append(report.Errors, ReportError{FixtureID: uint64(fix.GetId()), Error: err.Error()})
+			}
+
+			lock.Unlock()
+
+			if record != nil {
+				record(fix, err)
+			}
+		}(fix, statuses[i], deps, t)
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		report.AddError(fmt.Errorf("processing stopped before all fixtures were processed: %s", err.Error()))
+	}
+
+	return report
+}
+
+// checkStatus checks whether each fixture has finished using up to r.concurrency workers, returning the finished
+// fixtures in the order provided along with the result of the check for each fixture: nil if the fixture has
+// finished, an app.UnfinishedError if not or an app.StatusCheckError if the check failed. Unfinished fixtures do not
+// hold back later fixtures for their teams as a postponed or abandoned fixture is played on a later date. Fixtures
+// are not checked once ctx is cancelled.
+func (r *RatingHandler) checkStatus(ctx context.Context, fixtures []*statistico.Fixture) ([]*statistico.Fixture, []error) {
+	statuses := make([]error, len(fixtures))
+	workers := make(chan struct{}, r.concurrency)
+
+	var wg sync.WaitGroup
+
+	for i, fix := range fixtures {
+		wg.Add(1)
+
+		go func(i int, f *statistico.Fixture) {
+			defer wg.Done()
+
+			workers <- struct{}{}
+			defer func() { <-workers }()
+
+			if err := ctx.Err(); err != nil {
+				statuses[i] = err
+				return
+			}
+
+			finished, reason, err := r.status.Finished(ctx, f)
+
+			switch {
+			case err != nil:
+				statuses[i] = &app.StatusCheckError{FixtureID: uint64(f.GetId()), Err: err}
+			case !finished:
+				statuses[i] = &app.UnfinishedError{FixtureID: uint64(f.GetId()), Reason: reason}
+			}
+		}(i, fix)
+	}
+
+	wg.Wait()
+
+	var finished []*statistico.Fixture
+
+	for i, f := range fixtures {
+		if statuses[i] == nil {
+			finished = append(finished, f)
+		}
+	}
+
+	return finished, statuses
+}
+
+// startJob returns the incomplete job for the competition and season, creating one if it does not exist, along with
+// the IDs of fixtures the job has already processed. Fixtures skipped as unfinished are checked again.
+func (r *RatingHandler) startJob(ctx context.Context, competitionID, seasonID uint64, fixtures []*statistico.Fixture) (*job.Job, map[uint64]bool, error) {
+	processed := map[uint64]bool{}
+
+	j, err := r.jobs.Incomplete(ctx, competitionID, seasonID)
+
+	if _, ok := err.(*app.JobNotFoundError); ok {
+		var ids []uint64
+
+		for _, f := range fixtures {
+			ids = append(ids, uint64(f.Id))
+		}
+
+		j, err = r.jobs.Create(ctx, competitionID, seasonID, ids)
+
+		return j, processed, err
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	statuses, err := r.jobs.Fixtures(ctx, j.ID)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, f := range statuses {
+		if f.Status == job.FixtureDone || (f.Status == job.FixtureSkipped && f.Reason == job.ReasonRated) {
+			processed[f.FixtureID] = true
+		}
+	}
+
+	r.logger.Infof("resuming job %d for competition %d and season %d", j.ID, competitionID, seasonID)
+
+	return j, processed, nil
+}
+
+// record updates the status of a fixture processed by a job. The status is recorded even if ctx has been cancelled
+// so fixtures aborted by an interrupt are not left pending.
+func (r *RatingHandler) record(ctx context.Context, jobID uint64, f *statistico.Fixture, err error) {
+	update := job.Fixture{JobID: jobID, FixtureID: uint64(f.Id), Status: job.FixtureDone}
+
+	switch e := err.(type) {
+	case nil:
+	case *app.DuplicationError:
+		update.Status = job.FixtureSkipped
+		update.Reason = job.ReasonRated
+	case *app.UnfinishedError:
+		update.Status = job.FixtureSkipped
+		update.Reason = e.Reason
+	default:
+		update.Status = job.FixtureFailed
+		update.Error = err.Error()
+	}
+
+	if err := r.jobs.Update(detach(ctx), &update); err != nil {
+		r.logger.Errorf("error updating job %d fixture %d in team rating handler: %s", jobID, f.Id, err.Error())
+	}
+}
+
+// completeJob marks a job as complete if every fixture recorded against it has been processed or skipped, whether
+// or not ctx has been cancelled.
+func (r *RatingHandler) completeJob(ctx context.Context, jobID uint64) {
+	ctx = detach(ctx)
+
+	fixtures, err := r.jobs.Fixtures(ctx, jobID)
+
+	if err != nil {
+		r.logger.Errorf("error fetching job %d fixtures in team rating handler: %s", jobID, err.Error())
+		return
+	}
+
+	for _, f := range fixtures {
+		if f.Status != job.FixtureDone && f.Status != job.FixtureSkipped {
+			return
+		}
+	}
+
+	if err := r.jobs.Complete(ctx, jobID); err != nil {
+		r.logger.Errorf("error completing job %d in team rating handler: %s", jobID, err.Error())
+	}
+}
+
+// detachedContext carries the values of a parent context, such as the trace span, without its cancellation.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (d detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (d detachedContext) Done() <-chan struct{} {
 	return nil
 }
 
-func NewHandler(f fixture.Fetcher, p RatingProcessor, c clockwork.Clock, l *logrus.Logger) RatingHandler {
+func (d detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
+
+// detach returns a context that is never cancelled for writes that must complete once ctx has been cancelled.
+func detach(ctx context.Context) context.Context {
+	return detachedContext{parent: ctx}
+}
+
+func containsID(ids []uint64, id uint64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func NewHandler(f fixture.Fetcher, s fixture.StatusChecker, p RatingProcessor, e EventPrefetcher, j job.Repository, concurrency int, c clockwork.Clock, l *logrus.Logger) RatingHandler {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
 	return RatingHandler{
-		fetcher:   f,
-		processor: p,
-		clock:     c,
-		logger:    l,
+		fetcher:     f,
+		status:      s,
+		processor:   p,
+		prefetcher:  e,
+		jobs:        j,
+		concurrency: concurrency,
+		clock:       c,
+		logger:      l,
 	}
 }
@@ -3,13 +3,18 @@ package team_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/jonboulle/clockwork"
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app"
+	"github.com/statistico/statistico-ratings/internal/app/fixture"
+	"github.com/statistico/statistico-ratings/internal/app/job"
 	"github.com/statistico/statistico-ratings/internal/app/team"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"sync"
 	"testing"
 	"time"
 )
@@ -20,14 +25,340 @@ func TestRatingHandler_ByCompetition(t *testing.T) {
 
 		fetcher := new(MockFixtureFetcher)
 		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
 		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
 		logger, _ := test.NewNullLogger()
 
-		handler := team.NewHandler(fetcher, processor, clock, logger)
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
 
-		fix1 := statistico.Fixture{}
-		fix2 := statistico.Fixture{}
-		fix3 := statistico.Fixture{}
+		fix1 := statistico.Fixture{Id: 1}
+		fix2 := statistico.Fixture{Id: 2}
+		fix3 := statistico.Fixture{Id: 3}
+
+		fixtures := []*statistico.Fixture{
+			&fix1,
+			&fix2,
+			&fix3,
+		}
+
+		ctx := context.Background()
+
+		fetcher.On("ByCompetition", ctx, uint64(8), uint64(4)).Return(fixtures, nil)
+		prefetcher.On("Prefetch", ctx, fixtures).Once()
+		prefetcher.On("Discard", fixtures).Once()
+
+		jobs.On("Incomplete", mock.Anything, uint64(8), uint64(4)).Return(nil, &app.JobNotFoundError{CompetitionID: 8, SeasonID: 4})
+		jobs.On("Create", mock.Anything, uint64(8), uint64(4), []uint64{1, 2, 3}).Return(&job.Job{ID: 5}, nil)
+
+		processor.On("ByFixture", ctx, &fix1).Once().Return(nil)
+		processor.On("ByFixture", ctx, &fix2).Once().Return(nil)
+		processor.On("ByFixture", ctx, &fix3).Once().Return(nil)
+
+		jobs.On("Update", mock.Anything, &job.Fixture{JobID: 5, FixtureID: 1, Status: job.FixtureDone}).Once().Return(nil)
+		jobs.On("Update", mock.Anything, &job.Fixture{JobID: 5, FixtureID: 2, Status: job.FixtureDone}).Once().Return(nil)
+		jobs.On("Update", mock.Anything, &job.Fixture{JobID: 5, FixtureID: 3, Status: job.FixtureDone}).Once().Return(nil)
+		jobs.On("Fixtures", mock.Anything, uint64(5)).Return(jobFixtures(5, job.FixtureDone, job.FixtureDone, job.FixtureDone), nil)
+		jobs.On("Complete", mock.Anything, uint64(5)).Once().Return(nil)
+
+		report, err := handler.ByCompetition(ctx, uint64(8), uint64(4))
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &team.Report{Processed: 3, Errors: []team.ReportError{}}, report)
+		fetcher.AssertExpectations(t)
+		processor.AssertExpectations(t)
+		jobs.AssertExpectations(t)
+	})
+
+	t.Run("records the status of fixtures once the context is cancelled", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
+		logger, _ := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		fix1 := statistico.Fixture{Id: 1, HomeTeam: &statistico.Team{Id: 1}, AwayTeam: &statistico.Team{Id: 2}}
+		fix2 := statistico.Fixture{Id: 2, HomeTeam: &statistico.Team{Id: 2}, AwayTeam: &statistico.Team{Id: 3}}
+
+		fixtures := []*statistico.Fixture{&fix1, &fix2}
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		live := mock.MatchedBy(func(ctx context.Context) bool {
+			return ctx.Err() == nil
+		})
+
+		fetcher.On("ByCompetition", ctx, uint64(8), uint64(4)).Return(fixtures, nil)
+		prefetcher.On("Prefetch", ctx, fixtures).Once()
+		prefetcher.On("Discard", fixtures).Once()
+
+		jobs.On("Incomplete", ctx, uint64(8), uint64(4)).Return(nil, &app.JobNotFoundError{CompetitionID: 8, SeasonID: 4})
+		jobs.On("Create", ctx, uint64(8), uint64(4), []uint64{1, 2}).Return(&job.Job{ID: 5}, nil)
+
+		processor.On("ByFixture", ctx, &fix1).Once().Return(context.Canceled).Run(func(args mock.Arguments) {
+			cancel()
+		})
+
+		jobs.On("Update", live, &job.Fixture{JobID: 5, FixtureID: 1, Status: job.FixtureFailed, Error: "context canceled"}).Once().Return(nil)
+		jobs.On("Fixtures", live, uint64(5)).Return(jobFixtures(5, job.FixtureFailed, job.FixturePending), nil)
+
+		report, err := handler.ByCompetition(ctx, uint64(8), uint64(4))
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, 1, report.Failed)
+		assert.Equal(t, 1, report.Pending)
+		jobs.AssertExpectations(t)
+		jobs.AssertNotCalled(t, "Complete", mock.Anything, uint64(5))
+	})
+
+	t.Run("resumes an incomplete job without processing fixtures already processed or rated", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
+		logger, _ := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		fix1 := statistico.Fixture{Id: 1}
+		fix2 := statistico.Fixture{Id: 2}
+		fix3 := statistico.Fixture{Id: 3}
+		fix4 := statistico.Fixture{Id: 4}
+
+		ctx := context.Background()
+
+		fetcher.On("ByCompetition", ctx, uint64(8), uint64(4)).Return([]*statistico.Fixture{&fix1, &fix2, &fix3, &fix4}, nil)
+		prefetcher.On("Prefetch", ctx, []*statistico.Fixture{&fix3, &fix4}).Once()
+		prefetcher.On("Discard", []*statistico.Fixture{&fix3, &fix4}).Once()
+
+		jobs.On("Incomplete", mock.Anything, uint64(8), uint64(4)).Return(&job.Job{ID: 5}, nil)
+		jobs.On("Fixtures", mock.Anything, uint64(5)).Once().Return([]*job.Fixture{
+			{JobID: 5, FixtureID: 1, Status: job.FixtureDone},
+			{JobID: 5, FixtureID: 2, Status: job.FixtureSkipped, Reason: job.ReasonRated},
+			{JobID: 5, FixtureID: 3, Status: job.FixtureFailed},
+			{JobID: 5, FixtureID: 4, Status: job.FixtureSkipped, Reason: "result does not exist"},
+		}, nil)
+
+		processor.On("ByFixture", ctx, &fix3).Once().Return(nil)
+		processor.On("ByFixture", ctx, &fix4).Once().Return(nil)
+
+		jobs.On("Update", mock.Anything, &job.Fixture{JobID: 5, FixtureID: 3, Status: job.FixtureDone}).Once().Return(nil)
+		jobs.On("Update", mock.Anything, &job.Fixture{JobID: 5, FixtureID: 4, Status: job.FixtureDone}).Once().Return(nil)
+		jobs.On("Fixtures", mock.Anything, uint64(5)).Once().Return(jobFixtures(5, job.FixtureDone, job.FixtureSkipped, job.FixtureDone, job.FixtureDone), nil)
+		jobs.On("Complete", mock.Anything, uint64(5)).Once().Return(nil)
+
+		report, err := handler.ByCompetition(ctx, uint64(8), uint64(4))
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &team.Report{Processed: 2, Errors: []team.ReportError{}}, report)
+		processor.AssertNotCalled(t, "ByFixture", ctx, &fix1)
+		processor.AssertNotCalled(t, "ByFixture", ctx, &fix2)
+		fetcher.AssertExpectations(t)
+		processor.AssertExpectations(t)
+		jobs.AssertExpectations(t)
+	})
+
+	t.Run("marks fixture as skipped and continues execution if ratings exist for fixture", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
+		logger, hook := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		fix1 := statistico.Fixture{Id: 1}
+		fix2 := statistico.Fixture{Id: 2}
+
+		fixtures := []*statistico.Fixture{&fix1, &fix2}
+
+		ctx := context.Background()
+
+		fetcher.On("ByCompetition", ctx, uint64(8), uint64(4)).Return(fixtures, nil)
+		prefetcher.On("Prefetch", ctx, fixtures).Once()
+		prefetcher.On("Discard", fixtures).Once()
+
+		jobs.On("Incomplete", mock.Anything, uint64(8), uint64(4)).Return(nil, &app.JobNotFoundError{CompetitionID: 8, SeasonID: 4})
+		jobs.On("Create", mock.Anything, uint64(8), uint64(4), []uint64{1, 2}).Return(&job.Job{ID: 5}, nil)
+
+		processor.On("ByFixture", ctx, &fix1).Once().Return(&app.DuplicationError{TeamID: 1, FixtureID: 1, SeasonID: 4})
+		processor.On("ByFixture", ctx, &fix2).Once().Return(nil)
+
+		jobs.On("Update", mock.Anything, &job.Fixture{JobID: 5, FixtureID: 1, Status: job.FixtureSkipped, Reason: job.ReasonRated}).Once().Return(nil)
+		jobs.On("Update", mock.Anything, &job.Fixture{JobID: 5, FixtureID: 2, Status: job.FixtureDone}).Once().Return(nil)
+		jobs.On("Fixtures", mock.Anything, uint64(5)).Return(jobFixtures(5, job.FixtureSkipped, job.FixtureDone), nil)
+		jobs.On("Complete", mock.Anything, uint64(5)).Once().Return(nil)
+
+		report, err := handler.ByCompetition(ctx, uint64(8), uint64(4))
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &team.Report{Processed: 1, Skipped: 1, Errors: []team.ReportError{}}, report)
+		assert.Equal(t, "fixture 1 skipped in team rating handler: team rating exists for team 1, fixture 1 and season 4", hook.Entries[0].Message)
+		assert.Equal(t, logrus.WarnLevel, hook.Entries[0].Level)
+		processor.AssertExpectations(t)
+		jobs.AssertExpectations(t)
+	})
+
+	t.Run("skips unfinished fixtures with a reason without holding back later fixtures or prefetching their events", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		status := new(MockStatusChecker)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
+		logger, _ := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, status, processor, prefetcher, jobs, 1, clock, logger)
+
+		fix1 := statistico.Fixture{Id: 1, HomeTeam: &statistico.Team{Id: 1}, AwayTeam: &statistico.Team{Id: 2}}
+		fix2 := statistico.Fixture{Id: 2, HomeTeam: &statistico.Team{Id: 1}, AwayTeam: &statistico.Team{Id: 3}}
+		fix3 := statistico.Fixture{Id: 3, HomeTeam: &statistico.Team{Id: 2}, AwayTeam: &statistico.Team{Id: 3}}
+
+		fixtures := []*statistico.Fixture{&fix1, &fix2, &fix3}
+
+		ctx := context.Background()
+
+		fetcher.On("ByCompetition", ctx, uint64(8), uint64(4)).Return(fixtures, nil)
+		prefetcher.On("Prefetch", ctx, []*statistico.Fixture{&fix2}).Once()
+		prefetcher.On("Discard", []*statistico.Fixture{&fix2}).Once()
+
+		jobs.On("Incomplete", mock.Anything, uint64(8), uint64(4)).Return(nil, &app.JobNotFoundError{CompetitionID: 8, SeasonID: 4})
+		jobs.On("Create", mock.Anything, uint64(8), uint64(4), []uint64{1, 2, 3}).Return(&job.Job{ID: 5}, nil)
+
+		status.On("Finished", ctx, &fix1).Return(false, "result does not have a full time score", nil)
+		status.On("Finished", ctx, &fix2).Return(true, "", nil)
+		status.On("Finished", ctx, &fix3).Return(false, "", errors.New("result client error"))
+
+		processor.On("ByFixture", ctx, &fix2).Once().Return(nil)
+
+		jobs.On("Update", mock.Anything, &job.Fixture{JobID: 5, FixtureID: 1, Status: job.FixtureSkipped, Reason: "result does not have a full time score"}).Once().Return(nil)
+		jobs.On("Update", mock.Anything, &job.Fixture{JobID: 5, FixtureID: 2, Status: job.FixtureDone}).Once().Return(nil)
+		jobs.On("Update", mock.Anything, &job.Fixture{JobID: 5, FixtureID: 3, Status: job.FixtureFailed, Error: "result client error"}).Once().Return(nil)
+		jobs.On("Fixtures", mock.Anything, uint64(5)).Return(jobFixtures(5, job.FixtureSkipped, job.FixtureDone, job.FixtureFailed), nil)
+
+		report, err := handler.ByCompetition(ctx, uint64(8), uint64(4))
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		expected := team.Report{
+			Processed:  1,
+			Failed:     1,
+			Unfinished: 1,
+			Errors:     []team.ReportError{{FixtureID: 3, Error: "result client error"}},
+			Skips:      []team.ReportSkip{{FixtureID: 1, Reason: "result does not have a full time score"}},
+		}
+
+		assert.Equal(t, &expected, report)
+		processor.AssertNotCalled(t, "ByFixture", ctx, &fix1)
+		processor.AssertNotCalled(t, "ByFixture", ctx, &fix3)
+		processor.AssertExpectations(t)
+		jobs.AssertExpectations(t)
+	})
+
+	t.Run("logs an error if returned by job repository", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
+		logger, hook := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		ctx := context.Background()
+
+		fetcher.On("ByCompetition", ctx, uint64(8), uint64(4)).Return([]*statistico.Fixture{{Id: 1}}, nil)
+		jobs.On("Incomplete", mock.Anything, uint64(8), uint64(4)).Return(nil, errors.New("job repository error"))
+
+		_, err := handler.ByCompetition(ctx, uint64(8), uint64(4))
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "job repository error", err.Error())
+		assert.Equal(t, "error starting job in team rating handler: job repository error", hook.LastEntry().Message)
+		assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+		processor.AssertNotCalled(t, "ByFixture", mock.Anything, mock.Anything)
+		prefetcher.AssertNotCalled(t, "Prefetch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("logs an error if returned by fixture client", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
+		logger, hook := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		e := errors.New("fixture fetcher error")
+
+		ctx := context.Background()
+
+		fetcher.On("ByCompetition", ctx, uint64(8), uint64(4)).Return([]*statistico.Fixture{}, e)
+
+		processor.AssertNotCalled(t, "ByFixture")
+
+		_, err := handler.ByCompetition(ctx, uint64(8), uint64(4))
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "fixture fetcher error", err.Error())
+		assert.Equal(t, "error fetching fixtures in team rating handler: fixture fetcher error", hook.LastEntry().Message)
+		assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+		fetcher.AssertExpectations(t)
+	})
+
+	t.Run("logs error and exits execution if error returned by processor", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
+		logger, hook := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		fix1 := statistico.Fixture{Id: 1}
+		fix2 := statistico.Fixture{Id: 2}
+		fix3 := statistico.Fixture{Id: 3}
 
 		fixtures := []*statistico.Fixture{
 			&fix1,
@@ -37,80 +368,486 @@ func TestRatingHandler_ByCompetition(t *testing.T) {
 
 		ctx := context.Background()
 
-		fetcher.On("ByCompetition", ctx, uint64(8), uint64(4)).Return(fixtures, nil)
+		fetcher.On("ByCompetition", ctx, uint64(8), uint64(4)).Return(fixtures, nil)
+		prefetcher.On("Prefetch", ctx, fixtures).Once()
+		prefetcher.On("Discard", fixtures).Once()
+
+		jobs.On("Incomplete", mock.Anything, uint64(8), uint64(4)).Return(nil, &app.JobNotFoundError{CompetitionID: 8, SeasonID: 4})
+		jobs.On("Create", mock.Anything, uint64(8), uint64(4), []uint64{1, 2, 3}).Return(&job.Job{ID: 5}, nil)
+
+		e := errors.New("team rating processing error")
+
+		processor.On("ByFixture", ctx, &fix1).Once().Return(nil)
+		processor.On("ByFixture", ctx, &fix2).Once().Return(e)
+
+		jobs.On("Update", mock.Anything, &job.Fixture{JobID: 5, FixtureID: 1, Status: job.FixtureDone}).Once().Return(nil)
+		jobs.On("Update", mock.Anything, &job.Fixture{JobID: 5, FixtureID: 2, Status: job.FixtureFailed, Error: "team rating processing error"}).Once().Return(nil)
+		jobs.On("Fixtures", mock.Anything, uint64(5)).Return(jobFixtures(5, job.FixtureDone, job.FixtureFailed, job.FixturePending), nil)
+
+		processor.AssertNotCalled(t, "ByFixture", ctx, &fix3)
+
+		report, err := handler.ByCompetition(ctx, uint64(8), uint64(4))
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &team.Report{Processed: 1, Failed: 1, Pending: 1, Errors: []team.ReportError{{FixtureID: 2, Error: "team rating processing error"}}}, report)
+		assert.Equal(t, "error processing fixtures in team rating handler: team rating processing error", hook.LastEntry().Message)
+		assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+		jobs.AssertNotCalled(t, "Complete", mock.Anything, uint64(5))
+		fetcher.AssertExpectations(t)
+		processor.AssertExpectations(t)
+		jobs.AssertExpectations(t)
+	})
+
+	t.Run("continues processing fixtures for teams not involved in a failed fixture", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
+		logger, _ := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 2, clock, logger)
+
+		fix1 := statistico.Fixture{Id: 1, HomeTeam: &statistico.Team{Id: 1}, AwayTeam: &statistico.Team{Id: 2}}
+		fix2 := statistico.Fixture{Id: 2, HomeTeam: &statistico.Team{Id: 3}, AwayTeam: &statistico.Team{Id: 4}}
+		fix3 := statistico.Fixture{Id: 3, HomeTeam: &statistico.Team{Id: 4}, AwayTeam: &statistico.Team{Id: 1}}
+		fix4 := statistico.Fixture{Id: 4, HomeTeam: &statistico.Team{Id: 3}, AwayTeam: &statistico.Team{Id: 5}}
+
+		fixtures := []*statistico.Fixture{&fix1, &fix2, &fix3, &fix4}
+
+		ctx := context.Background()
+
+		fetcher.On("ByCompetition", ctx, uint64(8), uint64(4)).Return(fixtures, nil)
+		prefetcher.On("Prefetch", ctx, fixtures).Once()
+		prefetcher.On("Discard", fixtures).Once()
+
+		jobs.On("Incomplete", mock.Anything, uint64(8), uint64(4)).Return(nil, &app.JobNotFoundError{CompetitionID: 8, SeasonID: 4})
+		jobs.On("Create", mock.Anything, uint64(8), uint64(4), []uint64{1, 2, 3, 4}).Return(&job.Job{ID: 5}, nil)
+
+		processor.On("ByFixture", ctx, &fix1).Once().Return(errors.New("team rating processing error"))
+		processor.On("ByFixture", ctx, &fix2).Once().Return(nil)
+		processor.On("ByFixture", ctx, &fix4).Once().Return(nil)
+
+		jobs.On("Update", mock.Anything, mock.AnythingOfType("*job.Fixture")).Times(3).Return(nil)
+		jobs.On("Fixtures", mock.Anything, uint64(5)).Return(jobFixtures(5, job.FixtureFailed, job.FixtureDone, job.FixturePending, job.FixtureDone), nil)
+
+		report, err := handler.ByCompetition(ctx, uint64(8), uint64(4))
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &team.Report{Processed: 2, Failed: 1, Pending: 1, Errors: []team.ReportError{{FixtureID: 1, Error: "team rating processing error"}}}, report)
+		processor.AssertNotCalled(t, "ByFixture", ctx, &fix3)
+		processor.AssertExpectations(t)
+		jobs.AssertExpectations(t)
+	})
+}
+
+func TestRatingHandler_Concurrency(t *testing.T) {
+	t.Run("processes fixtures concurrently while keeping each team's fixtures in date order", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		processor := &orderingProcessor{inFlight: map[uint64]bool{}}
+		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
+		logger, _ := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 4, clock, logger)
+
+		var fixtures []*statistico.Fixture
+
+		// Six rounds of fixtures between eight teams where every team plays once per round
+		for round := 0; round < 6; round++ {
+			for i := 0; i < 4; i++ {
+				home := uint64((i + round) % 8)
+				away := uint64((7 - i + round) % 8)
+
+				fixtures = append(fixtures, &statistico.Fixture{
+					Id:       int64(len(fixtures) + 1),
+					HomeTeam: &statistico.Team{Id: home},
+					AwayTeam: &statistico.Team{Id: away},
+				})
+			}
+		}
+
+		ctx := context.Background()
+
+		fetcher.On("ByCompetition", ctx, uint64(8), uint64(4)).Return(fixtures, nil)
+		prefetcher.On("Prefetch", ctx, fixtures).Once()
+		prefetcher.On("Discard", fixtures).Once()
+
+		jobs.On("Incomplete", mock.Anything, uint64(8), uint64(4)).Return(nil, &app.JobNotFoundError{CompetitionID: 8, SeasonID: 4})
+		jobs.On("Create", mock.Anything, uint64(8), uint64(4), mock.Anything).Return(&job.Job{ID: 5}, nil)
+		jobs.On("Update", mock.Anything, mock.AnythingOfType("*job.Fixture")).Return(nil)
+		jobs.On("Fixtures", mock.Anything, uint64(5)).Return([]*job.Fixture{}, nil)
+		jobs.On("Complete", mock.Anything, uint64(5)).Return(nil)
+
+		report, err := handler.ByCompetition(ctx, uint64(8), uint64(4))
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		a := assert.New(t)
+
+		a.Equal(len(fixtures), len(processor.processed))
+		a.Equal(len(fixtures), report.Processed)
+		a.Empty(processor.errors)
+		a.Greater(processor.maxInFlight, 1)
+
+		last := map[uint64]int64{}
+
+		for _, f := range processor.processed {
+			for _, id := range []uint64{f.HomeTeam.Id, f.AwayTeam.Id} {
+				a.Less(last[id], f.Id, "fixture %d for team %d processed out of order", f.Id, id)
+				last[id] = f.Id
+			}
+		}
+	})
+}
+
+func TestRatingHandler_Competition(t *testing.T) {
+	t.Run("processes each season for a competition oldest first", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
+		logger, _ := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		fix1 := statistico.Fixture{Id: 1}
+		fix2 := statistico.Fixture{Id: 2}
+
+		ctx := context.Background()
+
+		fetcher.On("Seasons", ctx, uint64(8), 3).Return([]uint64{16, 17, 18}, nil)
+		fetcher.On("ByCompetition", ctx, uint64(8), uint64(16)).Return([]*statistico.Fixture{&fix1}, nil)
+		fetcher.On("ByCompetition", ctx, uint64(8), uint64(17)).Return([]*statistico.Fixture{}, errors.New("fixture fetcher error"))
+		fetcher.On("ByCompetition", ctx, uint64(8), uint64(18)).Return([]*statistico.Fixture{&fix2}, nil)
+		prefetcher.On("Prefetch", ctx, mock.Anything)
+		prefetcher.On("Discard", mock.Anything)
+
+		jobs.On("Incomplete", mock.Anything, uint64(8), mock.Anything).Return(nil, &app.JobNotFoundError{})
+		jobs.On("Create", mock.Anything, uint64(8), uint64(16), []uint64{1}).Return(&job.Job{ID: 5}, nil)
+		jobs.On("Create", mock.Anything, uint64(8), uint64(18), []uint64{2}).Return(&job.Job{ID: 6}, nil)
+		jobs.On("Update", mock.Anything, mock.AnythingOfType("*job.Fixture")).Return(nil)
+		jobs.On("Fixtures", mock.Anything, mock.Anything).Return([]*job.Fixture{}, nil)
+		jobs.On("Complete", mock.Anything, mock.Anything).Return(nil)
+
+		processor.On("ByFixture", ctx, &fix1).Once().Return(nil)
+		processor.On("ByFixture", ctx, &fix2).Once().Return(nil)
+
+		report, err := handler.Competition(ctx, 8, 3)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		expected := team.Report{
+			Processed: 2,
+			Errors:    []team.ReportError{{Error: "competition 8 and season 17: fixture fetcher error"}},
+		}
+
+		assert.Equal(t, &expected, report)
+		fetcher.AssertExpectations(t)
+		processor.AssertExpectations(t)
+	})
+
+	t.Run("returns an error if seasons cannot be fetched", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
+		logger, hook := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		ctx := context.Background()
+
+		fetcher.On("Seasons", ctx, uint64(8), 1).Return([]uint64{}, errors.New("competition 8 does not have a current season"))
+
+		_, err := handler.Competition(ctx, 8, 1)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "competition 8 does not have a current season", err.Error())
+		assert.Equal(t, "error fetching seasons in team rating handler: competition 8 does not have a current season", hook.LastEntry().Message)
+		fetcher.AssertNotCalled(t, "ByCompetition", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRatingHandler_RetryFailed(t *testing.T) {
+	t.Run("processes failed fixtures in date order and completes jobs", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
+		logger, _ := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		fix1 := statistico.Fixture{Id: 1, DateTime: &statistico.Date{Utc: 1615500000}}
+		fix2 := statistico.Fixture{Id: 2, DateTime: &statistico.Date{Utc: 1615400000}}
+
+		ctx := context.Background()
+
+		jobs.On("Failed", mock.Anything).Return([]*job.Fixture{
+			{JobID: 5, FixtureID: 1, Status: job.FixtureFailed},
+			{JobID: 6, FixtureID: 2, Status: job.FixtureFailed},
+		}, nil)
+
+		fetcher.On("ByID", ctx, uint64(1)).Return(&fix1, nil)
+		fetcher.On("ByID", ctx, uint64(2)).Return(&fix2, nil)
+		prefetcher.On("Prefetch", ctx, []*statistico.Fixture{&fix2, &fix1}).Once()
+		prefetcher.On("Discard", []*statistico.Fixture{&fix2, &fix1}).Once()
+
+		processor.On("ByFixture", ctx, &fix2).Once().Return(nil)
+		processor.On("ByFixture", ctx, &fix1).Once().Return(nil)
+
+		jobs.On("Update", mock.Anything, &job.Fixture{JobID: 5, FixtureID: 1, Status: job.FixtureDone}).Once().Return(nil)
+		jobs.On("Update", mock.Anything, &job.Fixture{JobID: 6, FixtureID: 2, Status: job.FixtureDone}).Once().Return(nil)
+		jobs.On("Fixtures", mock.Anything, uint64(5)).Return(jobFixtures(5, job.FixtureDone), nil)
+		jobs.On("Fixtures", mock.Anything, uint64(6)).Return(jobFixtures(6, job.FixtureDone, job.FixturePending), nil)
+		jobs.On("Complete", mock.Anything, uint64(5)).Once().Return(nil)
+
+		report, err := handler.RetryFailed(ctx)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &team.Report{Processed: 2, Errors: []team.ReportError{}}, report)
+		jobs.AssertNotCalled(t, "Complete", mock.Anything, uint64(6))
+		fetcher.AssertExpectations(t)
+		processor.AssertExpectations(t)
+		jobs.AssertExpectations(t)
+	})
+
+	t.Run("logs an error and continues execution if error returned by fixture fetcher", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
+		logger, hook := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		fix2 := statistico.Fixture{Id: 2}
+
+		ctx := context.Background()
+
+		jobs.On("Failed", mock.Anything).Return([]*job.Fixture{
+			{JobID: 5, FixtureID: 1, Status: job.FixtureFailed},
+			{JobID: 5, FixtureID: 2, Status: job.FixtureFailed},
+		}, nil)
+
+		fetcher.On("ByID", ctx, uint64(1)).Return(&statistico.Fixture{}, errors.New("fixture fetcher error"))
+		fetcher.On("ByID", ctx, uint64(2)).Return(&fix2, nil)
+		prefetcher.On("Prefetch", ctx, []*statistico.Fixture{&fix2}).Once()
+		prefetcher.On("Discard", []*statistico.Fixture{&fix2}).Once()
+
+		processor.On("ByFixture", ctx, &fix2).Once().Return(nil)
+
+		jobs.On("Update", mock.Anything, &job.Fixture{JobID: 5, FixtureID: 2, Status: job.FixtureDone}).Once().Return(nil)
+		jobs.On("Fixtures", mock.Anything, uint64(5)).Return(jobFixtures(5, job.FixtureFailed, job.FixtureDone), nil)
+
+		report, err := handler.RetryFailed(ctx)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &team.Report{Processed: 1, Failed: 1, Errors: []team.ReportError{{FixtureID: 1, Error: "fixture fetcher error"}}}, report)
+		assert.Equal(t, "error fetching fixture 1 in team rating handler: fixture fetcher error", hook.Entries[0].Message)
+		jobs.AssertNotCalled(t, "Complete", mock.Anything, uint64(5))
+		processor.AssertExpectations(t)
+		jobs.AssertExpectations(t)
+	})
+
+	t.Run("returns an error if returned by job repository", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
+		logger, hook := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		jobs.On("Failed", mock.Anything).Return([]*job.Fixture{}, errors.New("job repository error"))
+
+		_, err := handler.RetryFailed(context.Background())
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "job repository error", err.Error())
+		assert.Equal(t, "error fetching failed fixtures in team rating handler: job repository error", hook.LastEntry().Message)
+		processor.AssertNotCalled(t, "ByFixture", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRatingHandler_ByDateRange(t *testing.T) {
+	t.Run("fetches fixtures a day at a time and processes them in date order", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615629600, 0))
+		logger, _ := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		fix1 := statistico.Fixture{Id: 1, DateTime: &statistico.Date{Utc: 1615334400}}
+		fix2 := statistico.Fixture{Id: 2, DateTime: &statistico.Date{Utc: 1615420800}}
+		fix3 := statistico.Fixture{Id: 3, DateTime: &statistico.Date{Utc: 1615507200}}
+		fix4 := statistico.Fixture{Id: 4, DateTime: &statistico.Date{Utc: 1615500000}}
+
+		ctx := context.Background()
+
+		day1 := time.Date(2021, 3, 10, 0, 0, 0, 0, time.UTC)
+		day2 := time.Date(2021, 3, 11, 0, 0, 0, 0, time.UTC)
+		day3 := time.Date(2021, 3, 12, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2021, 3, 12, 12, 0, 0, 0, time.UTC)
 
-		processor.On("ByFixture", ctx, &fix1).Once().Return(nil)
-		processor.On("ByFixture", ctx, &fix2).Once().Return(nil)
-		processor.On("ByFixture", ctx, &fix3).Once().Return(nil)
+		fetcher.On("ByDate", ctx, day1, day2).Return([]*statistico.Fixture{&fix1}, nil)
+		fetcher.On("ByDate", ctx, day2, day3).Return([]*statistico.Fixture{&fix2, &fix4, &fix3}, nil)
+		fetcher.On("ByDate", ctx, day3, to).Return([]*statistico.Fixture{&fix3}, nil)
+
+		fixtures := []*statistico.Fixture{&fix1, &fix2, &fix4, &fix3}
+
+		prefetcher.On("Prefetch", ctx, fixtures).Once()
+		prefetcher.On("Discard", fixtures).Once()
+
+		var processed []*statistico.Fixture
+
+		processor.On("ByFixture", ctx, mock.AnythingOfType("*statistico.Fixture")).Times(4).Return(nil).Run(func(args mock.Arguments) {
+			processed = append(processed, args.Get(1).(*statistico.Fixture))
+		})
+
+		report, err := handler.ByDateRange(ctx, day1, to)
 
-		handler.ByCompetition(ctx, uint64(8), uint64(4))
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
 
+		assert.Equal(t, fixtures, processed)
+		assert.Equal(t, &team.Report{Processed: 4, Errors: []team.ReportError{}}, report)
 		fetcher.AssertExpectations(t)
 		processor.AssertExpectations(t)
 	})
 
-	t.Run("logs an error if returned by fixture client", func(t *testing.T) {
+	t.Run("limits the to date to the current time", func(t *testing.T) {
 		t.Helper()
 
 		fetcher := new(MockFixtureFetcher)
 		processor := new(MockTeamRatingProcessor)
-		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
-		logger, hook := test.NewNullLogger()
-
-		handler := team.NewHandler(fetcher, processor, clock, logger)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615611600, 0).UTC())
+		logger, _ := test.NewNullLogger()
 
-		e := errors.New("fixture fetcher error")
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
 
 		ctx := context.Background()
 
-		fetcher.On("ByCompetition", ctx, uint64(8), uint64(4)).Return([]*statistico.Fixture{}, e)
+		from := time.Date(2021, 3, 13, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC)
 
-		processor.AssertNotCalled(t, "ByFixture")
+		fetcher.On("ByDate", ctx, from, time.Unix(1615611600, 0).UTC()).Return([]*statistico.Fixture{}, nil)
+		prefetcher.On("Prefetch", ctx, []*statistico.Fixture(nil)).Once()
+		prefetcher.On("Discard", []*statistico.Fixture(nil)).Once()
 
-		handler.ByCompetition(ctx, uint64(8), uint64(4))
+		report, err := handler.ByDateRange(ctx, from, to)
 
-		assert.Equal(t, "error fetching fixtures in team rating handler: fixture fetcher error", hook.LastEntry().Message)
-		assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &team.Report{Errors: []team.ReportError{}}, report)
 		fetcher.AssertExpectations(t)
 	})
 
-	t.Run("logs error and exits execution if error returned by processor", func(t *testing.T) {
+	t.Run("returns an error if from date is not before to date", func(t *testing.T) {
 		t.Helper()
 
 		fetcher := new(MockFixtureFetcher)
 		processor := new(MockTeamRatingProcessor)
-		clock := clockwork.NewFakeClockAt(time.Unix(1615593600, 0))
-		logger, hook := test.NewNullLogger()
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615629600, 0))
+		logger, _ := test.NewNullLogger()
 
-		handler := team.NewHandler(fetcher, processor, clock, logger)
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
 
-		fix1 := statistico.Fixture{}
-		fix2 := statistico.Fixture{}
-		fix3 := statistico.Fixture{}
+		from := time.Date(2021, 3, 12, 0, 0, 0, 0, time.UTC)
 
-		fixtures := []*statistico.Fixture{
-			&fix1,
-			&fix2,
-			&fix3,
+		_, err := handler.ByDateRange(context.Background(), from, from.Add(-time.Hour))
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
 		}
 
-		ctx := context.Background()
+		assert.Equal(t, "from date provided must be before the to date and current time", err.Error())
+		fetcher.AssertNotCalled(t, "ByDate", mock.Anything, mock.Anything, mock.Anything)
+	})
 
-		fetcher.On("ByCompetition", ctx, uint64(8), uint64(4)).Return(fixtures, nil)
+	t.Run("returns an error if returned by fixture fetcher", func(t *testing.T) {
+		t.Helper()
 
-		e := errors.New("team rating processing error")
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615629600, 0))
+		logger, hook := test.NewNullLogger()
 
-		processor.On("ByFixture", ctx, &fix1).Once().Return(nil)
-		processor.On("ByFixture", ctx, &fix2).Once().Return(e)
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
 
-		processor.AssertNotCalled(t, "ByFixture", ctx, &fix3)
+		ctx := context.Background()
 
-		handler.ByCompetition(ctx, uint64(8), uint64(4))
+		from := time.Date(2021, 3, 11, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2021, 3, 12, 0, 0, 0, 0, time.UTC)
 
-		assert.Equal(t, "error processing fixtures in team rating handler: team rating processing error", hook.LastEntry().Message)
-		assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
-		fetcher.AssertExpectations(t)
-		processor.AssertExpectations(t)
+		fetcher.On("ByDate", ctx, from, to).Return([]*statistico.Fixture{}, errors.New("fixture fetcher error"))
+
+		_, err := handler.ByDateRange(ctx, from, to)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "fixture fetcher error", err.Error())
+		assert.Equal(t, "error fetching fixtures in team rating handler: fixture fetcher error", hook.LastEntry().Message)
+		processor.AssertNotCalled(t, "ByFixture", mock.Anything, mock.Anything)
 	})
 }
 
@@ -120,10 +857,12 @@ func TestRatingHandler_Today(t *testing.T) {
 
 		fetcher := new(MockFixtureFetcher)
 		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
 		clock := clockwork.NewFakeClockAt(time.Unix(1615629600, 0))
 		logger, _ := test.NewNullLogger()
 
-		handler := team.NewHandler(fetcher, processor, clock, logger)
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
 
 		fix1 := statistico.Fixture{}
 		fix2 := statistico.Fixture{}
@@ -140,30 +879,84 @@ func TestRatingHandler_Today(t *testing.T) {
 		end := time.Date(2021, 03, 13, 5, 0, 0, 0, time.UTC)
 
 		fetcher.On("ByDate", ctx, start, end).Return(fixtures, nil)
+		prefetcher.On("Prefetch", ctx, fixtures).Once()
+		prefetcher.On("Discard", fixtures).Once()
 
 		processor.On("ByFixture", ctx, &fix1).Once().Return(nil)
 		processor.On("ByFixture", ctx, &fix2).Once().Return(nil)
 		processor.On("ByFixture", ctx, &fix3).Once().Return(nil)
 
-		err := handler.Today(ctx, 5)
+		report, err := handler.Today(ctx, &team.TodayQuery{Hour: 5})
 
 		if err != nil {
 			t.Fatalf("Expected nil, got %s", err.Error())
 		}
 
+		assert.Equal(t, &team.Report{Processed: 3, Errors: []team.ReportError{}}, report)
 		fetcher.AssertExpectations(t)
 		processor.AssertExpectations(t)
 	})
 
+	t.Run("leaves fixtures pending once the context is cancelled", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615629600, 0))
+		logger, _ := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		fix1 := statistico.Fixture{Id: 1, HomeTeam: &statistico.Team{Id: 1}, AwayTeam: &statistico.Team{Id: 2}}
+		fix2 := statistico.Fixture{Id: 2, HomeTeam: &statistico.Team{Id: 2}, AwayTeam: &statistico.Team{Id: 3}}
+		fix3 := statistico.Fixture{Id: 3, HomeTeam: &statistico.Team{Id: 3}, AwayTeam: &statistico.Team{Id: 1}}
+
+		fixtures := []*statistico.Fixture{&fix1, &fix2, &fix3}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		start := time.Date(2021, 03, 13, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2021, 03, 13, 5, 0, 0, 0, time.UTC)
+
+		fetcher.On("ByDate", ctx, start, end).Return(fixtures, nil)
+		prefetcher.On("Prefetch", ctx, fixtures).Once()
+		prefetcher.On("Discard", fixtures).Once()
+
+		processor.On("ByFixture", ctx, &fix1).Once().Return(context.Canceled).Run(func(args mock.Arguments) {
+			cancel()
+		})
+
+		report, err := handler.Today(ctx, &team.TodayQuery{Hour: 5})
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		expected := team.Report{
+			Failed:  1,
+			Pending: 2,
+			Errors: []team.ReportError{
+				{FixtureID: 1, Error: "context canceled"},
+				{Error: "processing stopped before all fixtures were processed: context canceled"},
+			},
+		}
+
+		assert.Equal(t, &expected, report)
+		processor.AssertNumberOfCalls(t, "ByFixture", 1)
+	})
+
 	t.Run("logs an error if returned by fixture client", func(t *testing.T) {
 		t.Helper()
 
 		fetcher := new(MockFixtureFetcher)
 		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
 		clock := clockwork.NewFakeClockAt(time.Unix(1615629600, 0))
 		logger, hook := test.NewNullLogger()
 
-		handler := team.NewHandler(fetcher, processor, clock, logger)
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
 
 		e := errors.New("fixture fetcher error")
 
@@ -175,7 +968,7 @@ func TestRatingHandler_Today(t *testing.T) {
 
 		processor.AssertNotCalled(t, "ByFixture")
 
-		err := handler.Today(ctx, 5)
+		_, err := handler.Today(ctx, &team.TodayQuery{Hour: 5})
 
 		if err == nil {
 			t.Fatal("Expected error, got nil")
@@ -191,10 +984,12 @@ func TestRatingHandler_Today(t *testing.T) {
 
 		fetcher := new(MockFixtureFetcher)
 		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
 		clock := clockwork.NewFakeClockAt(time.Unix(1615629600, 0))
 		logger, hook := test.NewNullLogger()
 
-		handler := team.NewHandler(fetcher, processor, clock, logger)
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
 
 		fix1 := statistico.Fixture{}
 		fix2 := statistico.Fixture{}
@@ -211,6 +1006,8 @@ func TestRatingHandler_Today(t *testing.T) {
 		end := time.Date(2021, 03, 13, 5, 0, 0, 0, time.UTC)
 
 		fetcher.On("ByDate", ctx, start, end).Return(fixtures, nil)
+		prefetcher.On("Prefetch", ctx, fixtures).Once()
+		prefetcher.On("Discard", fixtures).Once()
 
 		e := errors.New("team rating processing error")
 
@@ -218,43 +1015,235 @@ func TestRatingHandler_Today(t *testing.T) {
 		processor.On("ByFixture", ctx, &fix2).Once().Return(e)
 		processor.On("ByFixture", ctx, &fix3).Once().Return(nil)
 
-		err := handler.Today(ctx, 5)
+		report, err := handler.Today(ctx, &team.TodayQuery{Hour: 5})
 
 		if err != nil {
 			t.Fatalf("Expected nil, got %s", err.Error())
 		}
 
+		assert.Equal(t, &team.Report{Processed: 2, Failed: 1, Errors: []team.ReportError{{Error: "team rating processing error"}}}, report)
 		assert.Equal(t, "error processing fixtures in team rating handler: team rating processing error", hook.LastEntry().Message)
 		assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
 		fetcher.AssertExpectations(t)
 		processor.AssertExpectations(t)
 	})
 
-	t.Run("returns an error if hour provided is greater than current time hour", func(t *testing.T) {
+	t.Run("processes the previous day if hour provided has not been reached today", func(t *testing.T) {
 		t.Helper()
 
 		fetcher := new(MockFixtureFetcher)
 		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
 		clock := clockwork.NewFakeClockAt(time.Unix(1615629600, 0))
 		logger, _ := test.NewNullLogger()
 
-		handler := team.NewHandler(fetcher, processor, clock, logger)
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
 
 		ctx := context.Background()
+		start := time.Date(2021, 03, 12, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2021, 03, 12, 23, 0, 0, 0, time.UTC)
 
-		fetcher.AssertNotCalled(t, "ByDate")
-		processor.AssertNotCalled(t, "ByFixture")
+		fetcher.On("ByDate", ctx, start, end).Return([]*statistico.Fixture{}, nil)
+		prefetcher.On("Prefetch", ctx, []*statistico.Fixture(nil)).Once()
+		prefetcher.On("Discard", []*statistico.Fixture(nil)).Once()
+
+		_, err := handler.Today(ctx, &team.TodayQuery{Hour: 23})
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		fetcher.AssertExpectations(t)
+	})
+
+	t.Run("evaluates the window in the location provided across daylight saving changes", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Date(2021, 03, 28, 21, 30, 0, 0, time.UTC))
+		logger, _ := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		london, err := time.LoadLocation("Europe/London")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		ctx := context.Background()
+		start := time.Date(2021, 03, 28, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2021, 03, 28, 21, 0, 0, 0, time.UTC)
 
-		err := handler.Today(ctx, 23)
+		fetcher.On("ByDate", ctx, start, end).Return([]*statistico.Fixture{}, nil)
+		prefetcher.On("Prefetch", ctx, []*statistico.Fixture(nil)).Once()
+		prefetcher.On("Discard", []*statistico.Fixture(nil)).Once()
+
+		_, err = handler.Today(ctx, &team.TodayQuery{Hour: 22, Location: london})
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		fetcher.AssertExpectations(t)
+	})
+
+	t.Run("extends the window before midnight by the lookback provided", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Date(2021, 03, 14, 0, 30, 0, 0, time.UTC))
+		logger, _ := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		ctx := context.Background()
+		start := time.Date(2021, 03, 12, 21, 0, 0, 0, time.UTC)
+		end := time.Date(2021, 03, 13, 23, 0, 0, 0, time.UTC)
+
+		fetcher.On("ByDate", ctx, start, end).Return([]*statistico.Fixture{}, nil)
+		prefetcher.On("Prefetch", ctx, []*statistico.Fixture(nil)).Once()
+		prefetcher.On("Discard", []*statistico.Fixture(nil)).Once()
+
+		_, err := handler.Today(ctx, &team.TodayQuery{Hour: 23, Lookback: 3 * time.Hour})
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		fetcher.AssertExpectations(t)
+	})
+
+	t.Run("returns an error if hour provided is not a valid hour of the day", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Unix(1615629600, 0))
+		logger, _ := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		_, err := handler.Today(context.Background(), &team.TodayQuery{Hour: 24})
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "hour provided must be between 0 and 23", err.Error())
+		fetcher.AssertNotCalled(t, "ByDate", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRatingHandler_LastHours(t *testing.T) {
+	t.Run("processes fixtures played in the hours up to the current time", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClockAt(time.Date(2021, 03, 14, 0, 30, 0, 0, time.UTC))
+		logger, _ := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		fix := statistico.Fixture{Id: 1}
+
+		ctx := context.Background()
+		start := time.Date(2021, 03, 13, 18, 30, 0, 0, time.UTC)
+		end := time.Date(2021, 03, 14, 0, 30, 0, 0, time.UTC)
+
+		fetcher.On("ByDate", ctx, start, end).Return([]*statistico.Fixture{&fix}, nil)
+		prefetcher.On("Prefetch", ctx, []*statistico.Fixture{&fix}).Once()
+		prefetcher.On("Discard", []*statistico.Fixture{&fix}).Once()
+		processor.On("ByFixture", ctx, &fix).Once().Return(nil)
+
+		report, err := handler.LastHours(ctx, 6)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &team.Report{Processed: 1, Errors: []team.ReportError{}}, report)
+		fetcher.AssertExpectations(t)
+		processor.AssertExpectations(t)
+	})
+
+	t.Run("returns an error if hours provided is less than one", func(t *testing.T) {
+		t.Helper()
+
+		fetcher := new(MockFixtureFetcher)
+		processor := new(MockTeamRatingProcessor)
+		prefetcher := new(MockEventPrefetcher)
+		jobs := new(MockJobRepository)
+		clock := clockwork.NewFakeClock()
+		logger, _ := test.NewNullLogger()
+
+		handler := team.NewHandler(fetcher, fixture.NewFileStatusChecker(), processor, prefetcher, jobs, 1, clock, logger)
+
+		_, err := handler.LastHours(context.Background(), 0)
 
 		if err == nil {
 			t.Fatal("Expected error, got nil")
 		}
 
-		assert.Equal(t, "hour provided is greater than the current time hour", err.Error())
+		assert.Equal(t, "hours provided must be greater than 0", err.Error())
 	})
 }
 
+// orderingProcessor records the order fixtures are processed in and any fixture processed while another fixture
+// for the same team is in flight.
+type orderingProcessor struct {
+	inFlight    map[uint64]bool
+	processed   []*statistico.Fixture
+	errors      []string
+	running     int
+	maxInFlight int
+	lock        sync.Mutex
+}
+
+func (o *orderingProcessor) ByFixture(ctx context.Context, f *statistico.Fixture) error {
+	o.lock.Lock()
+
+	for _, id := range []uint64{f.HomeTeam.Id, f.AwayTeam.Id} {
+		if o.inFlight[id] {
+			o.errors = append(o.errors, fmt.Sprintf("team %d processed concurrently", id))
+		}
+
+		o.inFlight[id] = true
+	}
+
+	o.running++
+
+	if o.running > o.maxInFlight {
+		o.maxInFlight = o.running
+	}
+
+	o.lock.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.inFlight[f.HomeTeam.Id] = false
+	o.inFlight[f.AwayTeam.Id] = false
+	o.running--
+	o.processed = append(o.processed, f)
+
+	return nil
+}
+
 type MockFixtureFetcher struct {
 	mock.Mock
 }
@@ -269,6 +1258,25 @@ func (m *MockFixtureFetcher) ByDate(ctx context.Context, from, to time.Time) ([]
 	return args.Get(0).([]*statistico.Fixture), args.Error(1)
 }
 
+func (m *MockFixtureFetcher) ByID(ctx context.Context, fixtureID uint64) (*statistico.Fixture, error) {
+	args := m.Called(ctx, fixtureID)
+	return args.Get(0).(*statistico.Fixture), args.Error(1)
+}
+
+type MockStatusChecker struct {
+	mock.Mock
+}
+
+func (m *MockStatusChecker) Finished(ctx context.Context, f *statistico.Fixture) (bool, string, error) {
+	args := m.Called(ctx, f)
+	return args.Bool(0), args.String(1), args.Error(2)
+}
+
+func (m *MockFixtureFetcher) Seasons(ctx context.Context, competitionID uint64, last int) ([]uint64, error) {
+	args := m.Called(ctx, competitionID, last)
+	return args.Get(0).([]uint64), args.Error(1)
+}
+
 type MockTeamRatingProcessor struct {
 	mock.Mock
 }
@@ -277,3 +1285,70 @@ func (m *MockTeamRatingProcessor) ByFixture(ctx context.Context, f *statistico.F
 	args := m.Called(ctx, f)
 	return args.Error(0)
 }
+
+type MockEventPrefetcher struct {
+	mock.Mock
+}
+
+func (m *MockEventPrefetcher) Prefetch(ctx context.Context, fixtures []*statistico.Fixture) {
+	m.Called(ctx, fixtures)
+}
+
+func (m *MockEventPrefetcher) Discard(fixtures []*statistico.Fixture) {
+	m.Called(fixtures)
+}
+
+func (m *MockEventPrefetcher) FixtureEvents(ctx context.Context, fixtureID uint64) (*statistico.FixtureEventsResponse, error) {
+	args := m.Called(ctx, fixtureID)
+	return args.Get(0).(*statistico.FixtureEventsResponse), args.Error(1)
+}
+
+type MockJobRepository struct {
+	mock.Mock
+}
+
+func (m *MockJobRepository) Create(ctx context.Context, competitionID, seasonID uint64, fixtureIDs []uint64) (*job.Job, error) {
+	args := m.Called(ctx, competitionID, seasonID, fixtureIDs)
+	return args.Get(0).(*job.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) Incomplete(ctx context.Context, competitionID, seasonID uint64) (*job.Job, error) {
+	args := m.Called(ctx, competitionID, seasonID)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*job.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) Fixtures(ctx context.Context, jobID uint64) ([]*job.Fixture, error) {
+	args := m.Called(ctx, jobID)
+	return args.Get(0).([]*job.Fixture), args.Error(1)
+}
+
+func (m *MockJobRepository) Update(ctx context.Context, f *job.Fixture) error {
+	args := m.Called(ctx, f)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) Complete(ctx context.Context, jobID uint64) error {
+	args := m.Called(ctx, jobID)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) Failed(ctx context.Context) ([]*job.Fixture, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*job.Fixture), args.Error(1)
+}
+
+// jobFixtures returns job fixtures with IDs starting at 1 with the statuses provided.
+func jobFixtures(jobID uint64, statuses ...string) []*job.Fixture {
+	var fixtures []*job.Fixture
+
+	for i, status := range statuses {
+		fixtures = append(fixtures, &job.Fixture{JobID: jobID, FixtureID: uint64(i + 1), Status: status})
+	}
+
+	return fixtures
+}
@@ -0,0 +1,115 @@
+package team
+
+import (
+	"context"
+	"github.com/statistico/statistico-football-data-go-grpc-client"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app/fixture"
+	"sync"
+)
+
+// EventPrefetcher fetches events for fixtures ahead of them being processed. It also satisfies
+// statisticodata.EventClient, returning prefetched events where available.
+type EventPrefetcher interface {
+	statisticodata.EventClient
+	// Prefetch concurrently fetches events for the fixtures provided, which must have finished, without blocking
+	// the caller. The fixtures are recorded as finished in the context events are fetched with, so their events
+	// can be cached.
+	Prefetch(ctx context.Context, fixtures []*statistico.Fixture)
+	// Discard drops events prefetched for the fixtures provided that have not been returned by FixtureEvents, so
+	// events of fixtures left unprocessed are fetched again when next requested.
+	Discard(fixtures []*statistico.Fixture)
+}
+
+type prefetch struct {
+	done   chan struct{}
+	events *statistico.FixtureEventsResponse
+	err    error
+}
+
+type eventPrefetcher struct {
+	client  statisticodata.EventClient
+	workers chan struct{}
+	pending map[uint64]*prefetch
+	lock    sync.Mutex
+}
+
+func (e *eventPrefetcher) Prefetch(ctx context.Context, fixtures []*statistico.Fixture) {
+	ctx = fixture.WithFinished(ctx, fixtures)
+
+	for _, f := range fixtures {
+		id := uint64(f.Id)
+
+		e.lock.Lock()
+
+		if _, ok := e.pending[id]; ok {
+			e.lock.Unlock()
+			continue
+		}
+
+		p := &prefetch{done: make(chan struct{})}
+		e.pending[id] = p
+
+		e.lock.Unlock()
+
+		go func() {
+			defer close(p.done)
+
+			select {
+			case e.workers <- struct{}{}:
+			case <-ctx.Done():
+				p.err = ctx.Err()
+				return
+			}
+
+			p.events, p.err = e.client.FixtureEvents(ctx, id)
+
+			<-e.workers
+		}()
+	}
+}
+
+// FixtureEvents returns events prefetched for the fixture, waiting for an in-flight fetch to complete, or fetches
+// them from the wrapped client if the fixture has not been prefetched. Prefetched events are returned once only.
+func (e *eventPrefetcher) FixtureEvents(ctx context.Context, fixtureID uint64) (*statistico.FixtureEventsResponse, error) {
+	e.lock.Lock()
+
+	p, ok := e.pending[fixtureID]
+	delete(e.pending, fixtureID)
+
+	e.lock.Unlock()
+
+	if !ok {
+		return e.client.FixtureEvents(ctx, fixtureID)
+	}
+
+	select {
+	case <-p.done:
+		return p.events, p.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (e *eventPrefetcher) Discard(fixtures []*statistico.Fixture) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for _, f := range fixtures {
+		delete(e.pending, uint64(f.Id))
+	}
+}
+
+// NewEventPrefetcher returns an EventPrefetcher fetching events from the client provided with at most concurrency
+// requests in flight at once.
+func NewEventPrefetcher(c statisticodata.EventClient, concurrency int) EventPrefetcher {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &eventPrefetcher{
+		client:  c,
+		workers: make(chan struct{}, concurrency),
+		pending: map[uint64]*prefetch{},
+	}
+}
@@ -0,0 +1,128 @@
+package team_test
+
+import (
+	"context"
+	"errors"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app/fixture"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestEventPrefetcher_FixtureEvents(t *testing.T) {
+	ctx := context.Background()
+
+	fixtures := []*statistico.Fixture{{Id: 26}, {Id: 27}}
+
+	t.Run("returns prefetched events once only", func(t *testing.T) {
+		t.Helper()
+
+		events := new(MockEventClient)
+
+		prefetcher := team.NewEventPrefetcher(events, 2)
+
+		one := statistico.FixtureEventsResponse{FixtureId: 26}
+		two := statistico.FixtureEventsResponse{FixtureId: 27}
+
+		events.On("FixtureEvents", finishedContext(26), uint64(26)).Once().Return(&one, nil)
+		events.On("FixtureEvents", finishedContext(27), uint64(27)).Once().Return(&two, nil)
+		events.On("FixtureEvents", ctx, uint64(26)).Once().Return(&one, nil)
+
+		prefetcher.Prefetch(ctx, fixtures)
+
+		for _, expected := range []*statistico.FixtureEventsResponse{&one, &two, &one} {
+			res, err := prefetcher.FixtureEvents(ctx, expected.FixtureId)
+
+			if err != nil {
+				t.Fatalf("Expected nil, got %s", err.Error())
+			}
+
+			assert.Equal(t, expected, res)
+		}
+
+		events.AssertExpectations(t)
+	})
+
+	t.Run("fetches events for fixtures that have not been prefetched", func(t *testing.T) {
+		t.Helper()
+
+		events := new(MockEventClient)
+
+		prefetcher := team.NewEventPrefetcher(events, 2)
+
+		res := statistico.FixtureEventsResponse{FixtureId: 28}
+
+		events.On("FixtureEvents", ctx, uint64(28)).Once().Return(&res, nil)
+
+		fetched, err := prefetcher.FixtureEvents(ctx, 28)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &res, fetched)
+		events.AssertExpectations(t)
+	})
+
+	t.Run("fetches events again for fixtures discarded before their events were returned", func(t *testing.T) {
+		t.Helper()
+
+		events := new(MockEventClient)
+
+		prefetcher := team.NewEventPrefetcher(events, 1)
+
+		partial := statistico.FixtureEventsResponse{FixtureId: 26}
+		final := statistico.FixtureEventsResponse{FixtureId: 26, Goals: []*statistico.GoalEvent{{TeamId: 1, Minute: 88}}}
+
+		fetched := make(chan struct{})
+
+		events.On("FixtureEvents", finishedContext(26), uint64(26)).Once().Return(&partial, nil).Run(func(args mock.Arguments) {
+			close(fetched)
+		})
+		events.On("FixtureEvents", ctx, uint64(26)).Once().Return(&final, nil)
+
+		prefetcher.Prefetch(ctx, fixtures[:1])
+
+		<-fetched
+
+		prefetcher.Discard(fixtures[:1])
+
+		res, err := prefetcher.FixtureEvents(ctx, 26)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, &final, res)
+		events.AssertExpectations(t)
+	})
+
+	t.Run("returns error returned when prefetching events", func(t *testing.T) {
+		t.Helper()
+
+		events := new(MockEventClient)
+
+		prefetcher := team.NewEventPrefetcher(events, 1)
+
+		events.On("FixtureEvents", finishedContext(26), uint64(26)).Return(&statistico.FixtureEventsResponse{}, errors.New("event client error"))
+
+		prefetcher.Prefetch(ctx, fixtures[:1])
+
+		_, err := prefetcher.FixtureEvents(ctx, 26)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "event client error", err.Error())
+	})
+}
+
+// finishedContext matches a context recording the fixture provided as finished.
+func finishedContext(fixtureID uint64) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		return fixture.FinishedFromContext(ctx, fixtureID)
+	})
+}
@@ -16,32 +16,60 @@ type ratingProcessor struct {
 	calculator         RatingCalculator
 }
 
+// ByFixture calculates and persists ratings for the home and away teams of the fixture provided. An
+// app.DuplicationError is returned without calculating ratings if ratings already exist for the fixture, so fixtures
+// can safely be processed more than once. An app.OutOfOrderError is returned without calculating ratings if either
+// team has a rating for a fixture played after the fixture provided.
 func (r *ratingProcessor) ByFixture(ctx context.Context, f *statistico.Fixture) error {
-	home, err := r.fetchRating(f.HomeTeam.Id)
+	existing, err := r.reader.ByFixture(ctx, uint64(f.Id))
 
 	if err != nil {
 		return err
 	}
 
-	away, err := r.fetchRating(f.AwayTeam.Id)
+	if len(existing) > 0 {
+		return &app.DuplicationError{
+			TeamID:    existing[0].TeamID,
+			FixtureID: existing[0].FixtureID,
+			SeasonID:  existing[0].SeasonID,
+		}
+	}
+
+	home, err := r.fetchRating(ctx, f.HomeTeam.Id)
 
 	if err != nil {
 		return err
 	}
 
+	away, err := r.fetchRating(ctx, f.AwayTeam.Id)
+
+	if err != nil {
+		return err
+	}
+
+	for _, rating := range []*Rating{home, away} {
+		if rating.FixtureDate.Unix() > f.GetDateTime().GetUtc() {
+			return &app.OutOfOrderError{
+				TeamID:          rating.TeamID,
+				FixtureID:       uint64(f.GetId()),
+				LatestFixtureID: rating.FixtureID,
+			}
+		}
+	}
+
 	newHome, newAway, err := r.calculator.ForFixture(ctx, f, home, away)
 
 	if err != nil {
 		return err
 	}
 
-	err = r.writer.Insert(newHome)
+	err = r.writer.Insert(ctx, newHome)
 
 	if err != nil {
 		return err
 	}
 
-	err = r.writer.Insert(newAway)
+	err = r.writer.Insert(ctx, newAway)
 
 	if err != nil {
 		return err
@@ -50,8 +78,8 @@ func (r *ratingProcessor) ByFixture(ctx context.Context, f *statistico.Fixture)
 	return nil
 }
 
-func (r *ratingProcessor) fetchRating(teamID uint64) (*Rating, error) {
-	rating, err := r.reader.Latest(teamID)
+func (r *ratingProcessor) fetchRating(ctx context.Context, teamID uint64) (*Rating, error) {
+	rating, err := r.reader.Latest(ctx, teamID)
 
 	switch err.(type) {
 	case *app.NotFoundError:
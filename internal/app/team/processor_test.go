@@ -4,14 +4,17 @@ import (
 	"context"
 	"errors"
 	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app"
 	"github.com/statistico/statistico-ratings/internal/app/team"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
+	"time"
 )
 
 func TestRatingProcessor_ByFixture(t *testing.T) {
 	fixture := statistico.Fixture{
+		Id:          26,
 		HomeTeam:    &statistico.Team{Id: 5},
 		AwayTeam:    &statistico.Team{Id: 6},
 		Competition: &statistico.Competition{Id: 8},
@@ -31,16 +34,17 @@ func TestRatingProcessor_ByFixture(t *testing.T) {
 		home := team.Rating{}
 		away := team.Rating{}
 
-		reader.On("Latest", uint64(5)).Return(&home, nil)
-		reader.On("Latest", uint64(6)).Return(&away, nil)
+		reader.On("ByFixture", ctx, uint64(26)).Return([]*team.Rating{}, nil)
+		reader.On("Latest", ctx, uint64(5)).Return(&home, nil)
+		reader.On("Latest", ctx, uint64(6)).Return(&away, nil)
 
 		newHome := team.Rating{}
 		newAway := team.Rating{}
 
 		calc.On("ForFixture", ctx, &fixture, &home, &away).Return(&newHome, &newAway, nil)
 
-		writer.On("Insert", &newHome).Return(nil)
-		writer.On("Insert", &newAway).Return(nil)
+		writer.On("Insert", ctx, &newHome).Return(nil)
+		writer.On("Insert", ctx, &newAway).Return(nil)
 
 		err := processor.ByFixture(ctx, &fixture)
 
@@ -64,7 +68,8 @@ func TestRatingProcessor_ByFixture(t *testing.T) {
 
 		e := errors.New("rating reader error")
 
-		reader.On("Latest", uint64(5)).Return(&team.Rating{}, e)
+		reader.On("ByFixture", ctx, uint64(26)).Return([]*team.Rating{}, nil)
+		reader.On("Latest", ctx, uint64(5)).Return(&team.Rating{}, e)
 
 		reader.AssertNotCalled(t, "Latest", uint64(6))
 		calc.AssertNotCalled(t, "ForFixture")
@@ -93,8 +98,9 @@ func TestRatingProcessor_ByFixture(t *testing.T) {
 		home := team.Rating{}
 		away := team.Rating{}
 
-		reader.On("Latest", uint64(5)).Return(&home, nil)
-		reader.On("Latest", uint64(6)).Return(&away, nil)
+		reader.On("ByFixture", ctx, uint64(26)).Return([]*team.Rating{}, nil)
+		reader.On("Latest", ctx, uint64(5)).Return(&home, nil)
+		reader.On("Latest", ctx, uint64(6)).Return(&away, nil)
 
 		calc.On("ForFixture", ctx, &fixture, &home, &away).Return(&team.Rating{}, &team.Rating{}, e)
 
@@ -126,15 +132,16 @@ func TestRatingProcessor_ByFixture(t *testing.T) {
 		home := team.Rating{}
 		away := team.Rating{}
 
-		reader.On("Latest", uint64(5)).Return(&home, nil)
-		reader.On("Latest", uint64(6)).Return(&away, nil)
+		reader.On("ByFixture", ctx, uint64(26)).Return([]*team.Rating{}, nil)
+		reader.On("Latest", ctx, uint64(5)).Return(&home, nil)
+		reader.On("Latest", ctx, uint64(6)).Return(&away, nil)
 
 		newHome := team.Rating{}
 		newAway := team.Rating{}
 
 		calc.On("ForFixture", ctx, &fixture, &home, &away).Return(&newHome, &newAway, nil)
 
-		writer.On("Insert", &newHome).Return(e)
+		writer.On("Insert", ctx, &newHome).Return(e)
 
 		err := processor.ByFixture(ctx, &fixture)
 
@@ -147,19 +154,108 @@ func TestRatingProcessor_ByFixture(t *testing.T) {
 
 		assert.Equal(t, "rating writer error", e.Error())
 	})
+
+	t.Run("returns DuplicationError without calculating ratings if ratings exist for fixture", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockRatingReader)
+		writer := new(MockRatingWriter)
+		calc := new(MockRatingCalculator)
+
+		processor := team.NewRatingProcessor(reader, writer, calc)
+
+		reader.On("ByFixture", ctx, uint64(26)).Return([]*team.Rating{{TeamID: 5, FixtureID: 26, SeasonID: 17420}}, nil)
+
+		err := processor.ByFixture(ctx, &fixture)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.IsType(t, &app.DuplicationError{}, err)
+		assert.Equal(t, "team rating exists for team 5, fixture 26 and season 17420", err.Error())
+		calc.AssertNotCalled(t, "ForFixture", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		writer.AssertNotCalled(t, "Insert", mock.Anything)
+	})
+
+	t.Run("returns OutOfOrderError without calculating ratings if a team has a rating for a later fixture", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockRatingReader)
+		writer := new(MockRatingWriter)
+		calc := new(MockRatingCalculator)
+
+		processor := team.NewRatingProcessor(reader, writer, calc)
+
+		f := statistico.Fixture{
+			Id:       26,
+			HomeTeam: &statistico.Team{Id: 5},
+			AwayTeam: &statistico.Team{Id: 6},
+			DateTime: &statistico.Date{Utc: 1615636800},
+		}
+
+		home := team.Rating{TeamID: 5, FixtureID: 20, FixtureDate: time.Unix(1615550400, 0)}
+		away := team.Rating{TeamID: 6, FixtureID: 30, FixtureDate: time.Unix(1615723200, 0)}
+
+		reader.On("ByFixture", ctx, uint64(26)).Return([]*team.Rating{}, nil)
+		reader.On("Latest", ctx, uint64(5)).Return(&home, nil)
+		reader.On("Latest", ctx, uint64(6)).Return(&away, nil)
+
+		err := processor.ByFixture(ctx, &f)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.IsType(t, &app.OutOfOrderError{}, err)
+		assert.Equal(t, "team 6 has a rating for fixture 30 played after fixture 26, ratings must be calculated in date order", err.Error())
+		calc.AssertNotCalled(t, "ForFixture", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		writer.AssertNotCalled(t, "Insert", mock.Anything)
+	})
+
+	t.Run("returns error if returned when reading ratings for fixture", func(t *testing.T) {
+		t.Helper()
+
+		reader := new(MockRatingReader)
+		writer := new(MockRatingWriter)
+		calc := new(MockRatingCalculator)
+
+		processor := team.NewRatingProcessor(reader, writer, calc)
+
+		reader.On("ByFixture", ctx, uint64(26)).Return([]*team.Rating{}, errors.New("rating reader error"))
+
+		err := processor.ByFixture(ctx, &fixture)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "rating reader error", err.Error())
+		reader.AssertNotCalled(t, "Latest", mock.Anything)
+	})
 }
 
 type MockRatingReader struct {
 	mock.Mock
 }
 
-func (m *MockRatingReader) Latest(teamID uint64) (*team.Rating, error) {
-	args := m.Called(teamID)
+func (m *MockRatingReader) Latest(ctx context.Context, teamID uint64) (*team.Rating, error) {
+	args := m.Called(ctx, teamID)
 	return args.Get(0).(*team.Rating), args.Error(1)
 }
 
-func (m *MockRatingReader) Get(q *team.ReaderQuery) ([]*team.Rating, error) {
-	args := m.Called(q)
+func (m *MockRatingReader) LatestByTeams(ctx context.Context, teamIDs []uint64, before *time.Time) ([]*team.Rating, error) {
+	args := m.Called(ctx, teamIDs, before)
+	return args.Get(0).([]*team.Rating), args.Error(1)
+}
+
+func (m *MockRatingReader) ByFixture(ctx context.Context, fixtureID uint64) ([]*team.Rating, error) {
+	args := m.Called(ctx, fixtureID)
+	return args.Get(0).([]*team.Rating), args.Error(1)
+}
+
+func (m *MockRatingReader) Get(ctx context.Context, q *team.ReaderQuery) ([]*team.Rating, error) {
+	args := m.Called(ctx, q)
 	return args.Get(0).([]*team.Rating), args.Error(1)
 }
 
@@ -167,8 +263,8 @@ type MockRatingWriter struct {
 	mock.Mock
 }
 
-func (m *MockRatingWriter) Insert(r *team.Rating) error {
-	args := m.Called(r)
+func (m *MockRatingWriter) Insert(ctx context.Context, r *team.Rating) error {
+	args := m.Called(ctx, r)
 	return args.Error(0)
 }
 
@@ -0,0 +1,33 @@
+package team
+
+import (
+	"fmt"
+	"time"
+)
+
+// NewReaderQuery returns a ReaderQuery for the ratings of a team, optionally for a season, created on or before
+// the RFC3339 formatted before date and sorted by timestamp_asc or timestamp_desc. An error is returned if before
+// or sort are invalid.
+func NewReaderQuery(teamID uint64, seasonID *uint64, before *string, sort string) (*ReaderQuery, error) {
+	q := ReaderQuery{
+		TeamID:   &teamID,
+		SeasonID: seasonID,
+		Sort:     sort,
+	}
+
+	if sort != "" && sort != "timestamp_asc" && sort != "timestamp_desc" {
+		return nil, fmt.Errorf("sort %q is not supported, use timestamp_asc or timestamp_desc", sort)
+	}
+
+	if before != nil {
+		t, err := time.Parse(time.RFC3339, *before)
+
+		if err != nil {
+			return nil, err
+		}
+
+		q.Before = &t
+	}
+
+	return &q, nil
+}
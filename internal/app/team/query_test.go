@@ -0,0 +1,69 @@
+package team_test
+
+import (
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewReaderQuery(t *testing.T) {
+	t.Run("returns a query for the team, season, before date and sort provided", func(t *testing.T) {
+		t.Helper()
+
+		season := uint64(99)
+		before := "2021-03-12T12:00:00+00:00"
+
+		q, err := team.NewReaderQuery(5, &season, &before, "timestamp_desc")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, uint64(5), *q.TeamID)
+		assert.Equal(t, uint64(99), *q.SeasonID)
+		assert.Equal(t, time.Date(2021, 3, 12, 12, 0, 0, 0, time.UTC).Unix(), q.Before.Unix())
+		assert.Equal(t, "timestamp_desc", q.Sort)
+	})
+
+	t.Run("leaves optional filters unset", func(t *testing.T) {
+		t.Helper()
+
+		q, err := team.NewReaderQuery(5, nil, nil, "")
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, uint64(5), *q.TeamID)
+		assert.Nil(t, q.SeasonID)
+		assert.Nil(t, q.Before)
+		assert.Equal(t, "", q.Sort)
+	})
+
+	t.Run("returns an error if the before date is not in RFC3339 format", func(t *testing.T) {
+		t.Helper()
+
+		before := "12/03/2021"
+
+		_, err := team.NewReaderQuery(5, nil, &before, "")
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "parsing time \"12/03/2021\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"12/03/2021\" as \"2006\"", err.Error())
+	})
+
+	t.Run("returns an error if the sort is not supported", func(t *testing.T) {
+		t.Helper()
+
+		_, err := team.NewReaderQuery(5, nil, nil, "points_desc")
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, "sort \"points_desc\" is not supported, use timestamp_asc or timestamp_desc", err.Error())
+	})
+}
@@ -1,6 +1,7 @@
 package team
 
 import (
+	"context"
 	"database/sql"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/statistico/statistico-ratings/internal/app"
@@ -8,119 +9,171 @@ import (
 )
 
 type RatingReader interface {
-	Latest(teamID uint64) (*Rating, error)
-	Get(q *ReaderQuery) ([]*Rating, error)
+	Latest(ctx context.Context, teamID uint64) (*Rating, error)
+	// LatestByTeams returns the most recent Rating for each of the teams provided, optionally limited to ratings
+	// calculated on or before the date provided. Teams without a rating are omitted from the slice returned.
+	LatestByTeams(ctx context.Context, teamIDs []uint64, before *time.Time) ([]*Rating, error)
+	// ByFixture returns the Rating structs calculated for the teams competing in the fixture provided.
+	ByFixture(ctx context.Context, fixtureID uint64) ([]*Rating, error)
+	Get(ctx context.Context, q *ReaderQuery) ([]*Rating, error)
 }
 
 type ratingReader struct {
 	connection *sql.DB
 }
 
-func (r *ratingReader) Latest(teamID uint64) (*Rating, error) {
-	b := queryBuilder(r.connection)
-
-	var rating Rating
-	var date int64
-	var timestamp int64
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	row := b.
-		Select(
-			"team_id",
-			"fixture_id",
-			"season_id",
-			"attack_total",
-			"attack_points",
-			"defence_total",
-			"defence_points",
-			"fixture_date",
-			"timestamp",
-		).
-		From("team_rating").
+func (r *ratingReader) Latest(ctx context.Context, teamID uint64) (*Rating, error) {
+	row := selectRatings(r.connection).
 		Where(sq.Eq{"team_id": teamID}).
 		OrderBy("timestamp DESC").
 		OrderBy("id DESC").
 		Limit(1).
-		QueryRow().
-		Scan(
-			&rating.TeamID,
-			&rating.FixtureID,
-			&rating.SeasonID,
-			&rating.Attack.Total,
-			&rating.Attack.Difference,
-			&rating.Defence.Total,
-			&rating.Defence.Difference,
-			&date,
-			&timestamp,
-		)
-
-	if row != nil {
+		QueryRowContext(ctx)
+
+	rating, err := scanRating(row)
+
+	if err == sql.ErrNoRows {
 		return nil, &app.NotFoundError{TeamID: teamID}
 	}
 
-	rating.FixtureDate = time.Unix(date, 0)
-	rating.Timestamp = time.Unix(timestamp, 0)
+	if err != nil {
+		return nil, err
+	}
 
-	return &rating, nil
+	return rating, nil
 }
 
-func (r *ratingReader) Get(q *ReaderQuery) ([]*Rating, error) {
-	b := queryBuilder(r.connection)
+func (r *ratingReader) LatestByTeams(ctx context.Context, teamIDs []uint64, before *time.Time) ([]*Rating, error) {
+	query := selectRatings(r.connection).
+		Options("DISTINCT ON (team_id)").
+		Where(sq.Eq{"team_id": teamIDs}).
+		OrderBy("team_id ASC", "timestamp DESC", "id DESC")
+
+	if before != nil {
+		query = query.Where(sq.LtOrEq{"timestamp": before.Unix()})
+	}
+
+	rows, err := query.QueryContext(ctx)
+
+	if err != nil {
+		return []*Rating{}, err
+	}
+
+	return rowsToRatingSlice(rows)
+}
 
-	query := b.
+func (r *ratingReader) ByFixture(ctx context.Context, fixtureID uint64) ([]*Rating, error) {
+	rows, err := selectRatings(r.connection).
+		Where(sq.Eq{"fixture_id": fixtureID}).
+		OrderBy("id ASC").
+		QueryContext(ctx)
+
+	if err != nil {
+		return []*Rating{}, err
+	}
+
+	return rowsToRatingSlice(rows)
+}
+
+func (r *ratingReader) Get(ctx context.Context, q *ReaderQuery) ([]*Rating, error) {
+	rows, err := buildQuery(selectRatings(r.connection), q).QueryContext(ctx)
+
+	if err != nil {
+		return []*Rating{}, err
+	}
+
+	return rowsToRatingSlice(rows)
+}
+
+func selectRatings(c *sql.DB) sq.SelectBuilder {
+	return queryBuilder(c).
 		Select(
 			"team_id",
 			"fixture_id",
 			"season_id",
+			"competition_id",
 			"attack_total",
 			"attack_points",
 			"defence_total",
 			"defence_points",
 			"fixture_date",
 			"timestamp",
+			"goals",
+			"adjusted_goals",
+			"k_factor",
+			"opponent_attack_total",
+			"opponent_defence_total",
+			"rule_version",
 		).
 		From("team_rating")
+}
 
-	rows, err := buildQuery(query, q).Query()
+func scanRating(s scanner) (*Rating, error) {
+	var rating Rating
+	var date int64
+	var timestamp int64
+	var goals sql.NullInt64
+	var adjustedGoals sql.NullFloat64
+	var kFactor sql.NullFloat64
+	var opponentAttack sql.NullFloat64
+	var opponentDefence sql.NullFloat64
+	var ruleVersion sql.NullInt64
+
+	err := s.Scan(
+		&rating.TeamID,
+		&rating.FixtureID,
+		&rating.SeasonID,
+		&rating.CompetitionID,
+		&rating.Attack.Total,
+		&rating.Attack.Difference,
+		&rating.Defence.Total,
+		&rating.Defence.Difference,
+		&date,
+		&timestamp,
+		&goals,
+		&adjustedGoals,
+		&kFactor,
+		&opponentAttack,
+		&opponentDefence,
+		&ruleVersion,
+	)
 
 	if err != nil {
-		return []*Rating{}, err
+		return nil, err
 	}
 
-	return rowsToRatingSlice(rows)
+	rating.FixtureDate = time.Unix(date, 0)
+	rating.Timestamp = time.Unix(timestamp, 0)
+
+	if adjustedGoals.Valid && kFactor.Valid {
+		rating.Calculation = &Calculation{
+			Goals:           uint32(goals.Int64),
+			AdjustedGoals:   adjustedGoals.Float64,
+			KFactor:         kFactor.Float64,
+			OpponentAttack:  opponentAttack.Float64,
+			OpponentDefence: opponentDefence.Float64,
+			RuleVersion:     uint32(ruleVersion.Int64),
+		}
+	}
+
+	return &rating, nil
 }
 
 func rowsToRatingSlice(rows *sql.Rows) ([]*Rating, error) {
 	var ratings []*Rating
-	var date int64
-	var timestamp int64
 
 	for rows.Next() {
-		var rating Rating
-		var attack Points
-		var defence Points
-
-		err := rows.Scan(
-			&rating.TeamID,
-			&rating.FixtureID,
-			&rating.SeasonID,
-			&attack.Total,
-			&attack.Difference,
-			&defence.Total,
-			&defence.Difference,
-			&date,
-			&timestamp,
-		)
+		rating, err := scanRating(rows)
 
 		if err != nil {
 			return ratings, err
 		}
 
-		rating.Attack = attack
-		rating.Defence = defence
-		rating.FixtureDate = time.Unix(date, 0)
-		rating.Timestamp = time.Unix(timestamp, 0)
-
-		ratings = append(ratings, &rating)
+		ratings = append(ratings, rating)
 	}
 
 	err := rows.Close()
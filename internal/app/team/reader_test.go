@@ -1,6 +1,8 @@
 package team_test
 
 import (
+	"context"
+	"database/sql"
 	"github.com/statistico/statistico-ratings/internal/app/team"
 	"github.com/statistico/statistico-ratings/internal/app/test"
 	"github.com/stretchr/testify/assert"
@@ -10,6 +12,7 @@ import (
 
 func TestRatingReader_Latest(t *testing.T) {
 	conn, cleanUp := test.GetConnection(t, []string{"team_rating"})
+	ctx := context.Background()
 	writer := team.NewRatingWriter(conn)
 	reader := team.NewRatingReader(conn)
 
@@ -74,12 +77,12 @@ func TestRatingReader_Latest(t *testing.T) {
 		}
 
 		for _, st := range s {
-			if err := writer.Insert(st.Rating); err != nil {
+			if err := writer.Insert(ctx, st.Rating); err != nil {
 				t.Fatalf("Expected nil, got %s", err.Error())
 			}
 		}
 
-		fetched, err := reader.Latest(1)
+		fetched, err := reader.Latest(ctx, 1)
 
 		if err != nil {
 			t.Fatalf("Expected nil, got %s", err.Error())
@@ -101,7 +104,7 @@ func TestRatingReader_Latest(t *testing.T) {
 		t.Helper()
 		defer cleanUp()
 
-		_, err := reader.Latest(1)
+		_, err := reader.Latest(ctx, 1)
 
 		if err == nil {
 			t.Fatal("Expected error, got nil")
@@ -109,10 +112,163 @@ func TestRatingReader_Latest(t *testing.T) {
 
 		assert.Equal(t, "team 1 rating does not exist", err.Error())
 	})
+
+	t.Run("returns the context error rather than a NotFoundError if the query is cancelled", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		insertRatings(t, writer)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := reader.Latest(ctx, 1)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("returns promptly if the context is cancelled while the query is running", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		insertRatings(t, writer)
+
+		release := lockTable(t, conn, "team_rating")
+		defer release()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		start := time.Now()
+
+		_, err := reader.Latest(ctx, 1)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Less(t, int64(time.Since(start)), int64(2*time.Second))
+	})
+}
+
+func TestRatingReader_LatestByTeams(t *testing.T) {
+	conn, cleanUp := test.GetConnection(t, []string{"team_rating"})
+	ctx := context.Background()
+	writer := team.NewRatingWriter(conn)
+	reader := team.NewRatingReader(conn)
+
+	t.Run("returns the latest rating for each team", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		insertRatings(t, writer)
+
+		ratings, err := reader.LatestByTeams(ctx, []uint64{1, 2, 3}, nil)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		a := assert.New(t)
+
+		a.Equal(2, len(ratings))
+		a.Equal(uint64(1), ratings[0].TeamID)
+		a.Equal(uint64(67), ratings[0].FixtureID)
+		a.Equal(uint64(2), ratings[1].TeamID)
+		a.Equal(uint64(70), ratings[1].FixtureID)
+	})
+
+	t.Run("returns the latest rating for each team before a given date", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		insertRatings(t, writer)
+
+		date := time.Unix(1625163423, 0)
+
+		ratings, err := reader.LatestByTeams(ctx, []uint64{1, 2}, &date)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		a := assert.New(t)
+
+		a.Equal(2, len(ratings))
+		a.Equal(uint64(1), ratings[0].TeamID)
+		a.Equal(uint64(66), ratings[0].FixtureID)
+		a.Equal(uint64(2), ratings[1].TeamID)
+		a.Equal(uint64(66), ratings[1].FixtureID)
+	})
+}
+
+func TestRatingReader_ByFixture(t *testing.T) {
+	conn, cleanUp := test.GetConnection(t, []string{"team_rating"})
+	ctx := context.Background()
+	writer := team.NewRatingWriter(conn)
+	reader := team.NewRatingReader(conn)
+
+	t.Run("returns ratings and calculation inputs for a fixture", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		insertRatings(t, writer)
+
+		r := &team.Rating{
+			TeamID:        3,
+			FixtureID:     66,
+			SeasonID:      9,
+			CompetitionID: 8,
+			Attack: team.Points{
+				Total:      1245.04,
+				Difference: 19.37,
+			},
+			Defence: team.Points{
+				Total:      1240.82,
+				Difference: 12.35,
+			},
+			FixtureDate: time.Unix(1625163423, 0),
+			Timestamp:   time.Unix(1625163423, 0),
+			Calculation: &team.Calculation{
+				Goals:           3,
+				AdjustedGoals:   2.66,
+				KFactor:         5,
+				OpponentAttack:  1518.33,
+				OpponentDefence: 790.72,
+				RuleVersion:     1,
+			},
+		}
+
+		if err := writer.Insert(ctx, r); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		ratings, err := reader.ByFixture(ctx, 66)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		a := assert.New(t)
+
+		a.Equal(3, len(ratings))
+		a.Equal(uint64(1), ratings[0].TeamID)
+		a.Nil(ratings[0].Calculation)
+		a.Equal(uint64(2), ratings[1].TeamID)
+		a.Nil(ratings[1].Calculation)
+		a.Equal(r, ratings[2])
+		a.Equal(1225.67, ratings[2].Attack.PreMatch())
+		a.Equal(1228.47, ratings[2].Defence.PreMatch())
+	})
 }
 
 func TestRatingReader_Get(t *testing.T) {
 	conn, cleanUp := test.GetConnection(t, []string{"team_rating"})
+	ctx := context.Background()
 	writer := team.NewRatingWriter(conn)
 	reader := team.NewRatingReader(conn)
 
@@ -234,7 +390,7 @@ func TestRatingReader_Get(t *testing.T) {
 		insertRatings(t, writer)
 
 		for _, st := range s {
-			ratings, err := reader.Get(st.Query)
+			ratings, err := reader.Get(ctx, st.Query)
 
 			if err != nil {
 				t.Fatalf("Expected nil, got %s", err.Error())
@@ -258,7 +414,7 @@ func TestRatingReader_Get(t *testing.T) {
 			Sort:   "timestamp_desc",
 		}
 
-		ratings, err := reader.Get(&query)
+		ratings, err := reader.Get(ctx, &query)
 
 		if err != nil {
 			t.Fatalf("Expected nil, got %s", err.Error())
@@ -271,9 +427,81 @@ func TestRatingReader_Get(t *testing.T) {
 		a.Equal(uint64(66), ratings[1].FixtureID)
 		a.Equal(uint64(65), ratings[2].FixtureID)
 	})
+
+	t.Run("returns an error if the context is cancelled", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		insertRatings(t, writer)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		team1 := uint64(1)
+
+		_, err := reader.Get(ctx, &team.ReaderQuery{TeamID: &team1})
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, context.Canceled, err)
+	})
+}
+
+// lockTable takes an exclusive lock on table in a separate transaction that sleeps with pg_sleep until the returned
+// func is called, so queries against the table run until they are cancelled.
+func lockTable(t *testing.T, conn *sql.DB, table string) func() {
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		tx, err := conn.Begin()
+
+		if err != nil {
+			t.Errorf("Expected nil, got %s", err.Error())
+			close(locked)
+			return
+		}
+
+		defer tx.Rollback()
+
+		if _, err := tx.Exec("LOCK TABLE " + table + " IN ACCESS EXCLUSIVE MODE"); err != nil {
+			t.Errorf("Expected nil, got %s", err.Error())
+			close(locked)
+			return
+		}
+
+		close(locked)
+
+		for {
+			select {
+			case <-release:
+				return
+			default:
+			}
+
+			if _, err := tx.Exec("SELECT pg_sleep(0.05)"); err != nil {
+				t.Errorf("Expected nil, got %s", err.Error())
+				return
+			}
+		}
+	}()
+
+	<-locked
+
+	return func() {
+		close(release)
+		<-done
+	}
 }
 
 func insertRatings(t *testing.T, r team.RatingWriter) {
+	ctx := context.Background()
+
 	ratings := []*team.Rating{
 		{
 			TeamID:    1,
@@ -353,7 +581,7 @@ func insertRatings(t *testing.T, r team.RatingWriter) {
 	}
 
 	for _, rating := range ratings {
-		if err := r.Insert(rating); err != nil {
+		if err := r.Insert(ctx, rating); err != nil {
 			t.Fatalf("Error inserting team rating: %s", err.Error())
 		}
 	}
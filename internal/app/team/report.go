@@ -0,0 +1,63 @@
+package team
+
+// Report summarises the outcome of processing team ratings for a set of fixtures.
+type Report struct {
+	// Processed is the number of fixtures ratings were calculated for.
+	Processed int `json:"processed"`
+	// Skipped is the number of fixtures not processed as ratings already existed for the fixture.
+	Skipped int `json:"skipped"`
+	// Failed is the number of fixtures that returned an error when processed.
+	Failed int `json:"failed"`
+	// Pending is the number of fixtures not processed as an earlier fixture for one of its teams failed.
+	Pending int `json:"pending"`
+	// Unfinished is the number of fixtures not processed as they have not finished.
+	Unfinished int `json:"unfinished"`
+	// Errors holds the error returned for each failed fixture, along with any error preventing fixtures from
+	// being fetched or processed at all.
+	Errors []ReportError `json:"errors"`
+	// Skips holds the reason each unfinished fixture was not processed.
+	Skips []ReportSkip `json:"skips,omitempty"`
+}
+
+// ReportError is an error returned when processing team ratings. FixtureID is zero for errors not specific to a
+// fixture.
+type ReportError struct {
+	FixtureID uint64 `json:"fixtureId,omitempty"`
+	Error     string `json:"error"`
+}
+
+// ReportSkip is the reason a fixture was not processed.
+type ReportSkip struct {
+	FixtureID uint64 `json:"fixtureId"`
+	Reason    string `json:"reason"`
+}
+
+// AddError records an error not specific to a fixture.
+func (r *Report) AddError(err error) {
+	r.Errors = append(r.Errors, ReportError{Error: err.Error()})
+}
+
+// Merge adds the counts and errors of another Report to the Report.
+func (r *Report) Merge(o *Report) {
+	if o == nil {
+		return
+	}
+
+	r.Processed += o.Processed
+	r.Skipped += o.Skipped
+	r.Failed += o.Failed
+	r.Pending += o.Pending
+	r.Unfinished += o.Unfinished
+	r.Errors = append(r.Errors, o.Errors...)
+	r.Skips = append(r.Skips, o.Skips...)
+}
+
+// Success returns true if no errors were recorded.
+func (r *Report) Success() bool {
+	return len(r.Errors) == 0
+}
+
+// NewReport returns an empty Report.
+func NewReport() *Report {
+	return &Report{Errors: []ReportError{}}
+}
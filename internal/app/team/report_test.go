@@ -0,0 +1,54 @@
+package team_test
+
+import (
+	"errors"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReport_Merge(t *testing.T) {
+	t.Run("adds counts and errors of another report", func(t *testing.T) {
+		t.Helper()
+
+		report := team.NewReport()
+
+		report.Merge(&team.Report{Processed: 2, Skipped: 1, Errors: []team.ReportError{}})
+		report.Merge(&team.Report{Processed: 1, Failed: 1, Pending: 3, Errors: []team.ReportError{{FixtureID: 5, Error: "processor error"}}})
+		report.Merge(&team.Report{Unfinished: 1, Errors: []team.ReportError{}, Skips: []team.ReportSkip{{FixtureID: 6, Reason: "result does not exist"}}})
+		report.Merge(nil)
+
+		expected := team.Report{
+			Processed:  3,
+			Skipped:    1,
+			Failed:     1,
+			Pending:    3,
+			Unfinished: 1,
+			Errors:     []team.ReportError{{FixtureID: 5, Error: "processor error"}},
+			Skips:      []team.ReportSkip{{FixtureID: 6, Reason: "result does not exist"}},
+		}
+
+		assert.Equal(t, &expected, report)
+	})
+}
+
+func TestReport_Success(t *testing.T) {
+	t.Run("returns true if no errors are recorded", func(t *testing.T) {
+		t.Helper()
+
+		report := team.NewReport()
+		report.Skipped = 4
+
+		assert.True(t, report.Success())
+	})
+
+	t.Run("returns false if an error is recorded", func(t *testing.T) {
+		t.Helper()
+
+		report := team.NewReport()
+		report.AddError(errors.New("fixture fetcher error"))
+
+		assert.False(t, report.Success())
+		assert.Equal(t, []team.ReportError{{Error: "fixture fetcher error"}}, report.Errors)
+	})
+}
@@ -0,0 +1,176 @@
+package team
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// RatingStream pushes newly persisted Rating structs to subscribers as they are received from the
+// team_rating Postgres NOTIFY channel.
+type RatingStream interface {
+	// Subscribe returns a channel receiving Rating structs matching the StreamQuery provided. The channel is
+	// closed once the context provided is cancelled.
+	Subscribe(ctx context.Context, q *StreamQuery) <-chan *Rating
+}
+
+type subscriber struct {
+	query   *StreamQuery
+	ratings chan *Rating
+}
+
+type ratingStream struct {
+	subscribers map[*subscriber]struct{}
+	logger      *logrus.Logger
+	lock        sync.RWMutex
+}
+
+type notification struct {
+	TeamID          uint64   `json:"team_id"`
+	FixtureID       uint64   `json:"fixture_id"`
+	SeasonID        uint64   `json:"season_id"`
+	CompetitionID   uint64   `json:"competition_id"`
+	AttackTotal     float64  `json:"attack_total"`
+	AttackPoints    float64  `json:"attack_points"`
+	DefenceTotal    float64  `json:"defence_total"`
+	DefencePoints   float64  `json:"defence_points"`
+	FixtureDate     int64    `json:"fixture_date"`
+	Timestamp       int64    `json:"timestamp"`
+	Goals           *uint32  `json:"goals"`
+	AdjustedGoals   *float64 `json:"adjusted_goals"`
+	KFactor         *float64 `json:"k_factor"`
+	OpponentAttack  *float64 `json:"opponent_attack_total"`
+	OpponentDefence *float64 `json:"opponent_defence_total"`
+	RuleVersion     *uint32  `json:"rule_version"`
+}
+
+func (r *ratingStream) Subscribe(ctx context.Context, q *StreamQuery) <-chan *Rating {
+	s := &subscriber{
+		query:   q,
+		ratings: make(chan *Rating, 100),
+	}
+
+	r.lock.Lock()
+	r.subscribers[s] = struct{}{}
+	r.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		r.lock.Lock()
+		delete(r.subscribers, s)
+		close(s.ratings)
+		r.lock.Unlock()
+	}()
+
+	return s.ratings
+}
+
+func (r *ratingStream) listen(n <-chan *pq.Notification) {
+	for notice := range n {
+		// A nil notification is sent by the listener after re-establishing a lost database connection
+		if notice == nil {
+			continue
+		}
+
+		var x notification
+
+		if err := json.Unmarshal([]byte(notice.Extra), &x); err != nil {
+			r.logger.Errorf("error parsing team rating notification: %s", err.Error())
+			continue
+		}
+
+		rt := Rating{
+			TeamID:        x.TeamID,
+			FixtureID:     x.FixtureID,
+			SeasonID:      x.SeasonID,
+			CompetitionID: x.CompetitionID,
+			Attack: Points{
+				Total:      x.AttackTotal,
+				Difference: x.AttackPoints,
+			},
+			Defence: Points{
+				Total:      x.DefenceTotal,
+				Difference: x.DefencePoints,
+			},
+			FixtureDate: time.Unix(x.FixtureDate, 0),
+			Timestamp:   time.Unix(x.Timestamp, 0),
+		}
+
+		if x.AdjustedGoals != nil && x.KFactor != nil {
+			rt.Calculation = x.calculation()
+		}
+
+		r.publish(&rt)
+	}
+}
+
+func (n *notification) calculation() *Calculation {
+	c := Calculation{
+		AdjustedGoals: *n.AdjustedGoals,
+		KFactor:       *n.KFactor,
+	}
+
+	if n.Goals != nil {
+		c.Goals = *n.Goals
+	}
+
+	if n.OpponentAttack != nil {
+		c.OpponentAttack = *n.OpponentAttack
+	}
+
+	if n.OpponentDefence != nil {
+		c.OpponentDefence = *n.OpponentDefence
+	}
+
+	if n.RuleVersion != nil {
+		c.RuleVersion = *n.RuleVersion
+	}
+
+	return &c
+}
+
+func (r *ratingStream) publish(rt *Rating) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for s := range r.subscribers {
+		if !s.query.matches(rt) {
+			continue
+		}
+
+		select {
+		case s.ratings <- rt:
+		default:
+			r.logger.Warnf("dropping team rating for team %d and fixture %d for slow subscriber", rt.TeamID, rt.FixtureID)
+		}
+	}
+}
+
+func (q *StreamQuery) matches(rt *Rating) bool {
+	if q.TeamID != nil && *q.TeamID != rt.TeamID {
+		return false
+	}
+
+	if q.CompetitionID != nil && *q.CompetitionID != rt.CompetitionID {
+		return false
+	}
+
+	return true
+}
+
+// NewRatingStream returns a RatingStream publishing Rating structs parsed from the notifications received on
+// the channel provided, typically the Notify channel of a pq.Listener listening on the team_rating channel.
+func NewRatingStream(n <-chan *pq.Notification, l *logrus.Logger) RatingStream {
+	s := &ratingStream{
+		subscribers: map[*subscriber]struct{}{},
+		logger:      l,
+	}
+
+	go s.listen(n)
+
+	return s
+}
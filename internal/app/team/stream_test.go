@@ -0,0 +1,136 @@
+package team_test
+
+import (
+	"context"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRatingStream_Subscribe(t *testing.T) {
+	payload := `{"id":1,"team_id":1,"fixture_id":26,"season_id":17462,"competition_id":8,"attack_total":1245.04,` +
+		`"attack_points":19.37,"defence_total":1240.82,"defence_points":12.35,"fixture_date":1630343736,"timestamp":449884800,` +
+		`"goals":3,"adjusted_goals":2.5,"k_factor":5,"opponent_attack_total":1518.33,"opponent_defence_total":790.72,"rule_version":1}`
+
+	t.Run("pushes ratings parsed from notifications to subscribers", func(t *testing.T) {
+		t.Helper()
+
+		n := make(chan *pq.Notification)
+		logger, _ := test.NewNullLogger()
+
+		stream := team.NewRatingStream(n, logger)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ratings := stream.Subscribe(ctx, &team.StreamQuery{})
+
+		n <- &pq.Notification{Channel: "team_rating", Extra: payload}
+
+		rt := receiveRating(t, ratings)
+
+		a := assert.New(t)
+
+		a.Equal(uint64(1), rt.TeamID)
+		a.Equal(uint64(26), rt.FixtureID)
+		a.Equal(uint64(17462), rt.SeasonID)
+		a.Equal(uint64(8), rt.CompetitionID)
+		a.Equal(1245.04, rt.Attack.Total)
+		a.Equal(19.37, rt.Attack.Difference)
+		a.Equal(1240.82, rt.Defence.Total)
+		a.Equal(12.35, rt.Defence.Difference)
+		a.Equal(time.Unix(1630343736, 0), rt.FixtureDate)
+		a.Equal(time.Unix(449884800, 0), rt.Timestamp)
+		a.Equal(&team.Calculation{
+			Goals:           3,
+			AdjustedGoals:   2.5,
+			KFactor:         5,
+			OpponentAttack:  1518.33,
+			OpponentDefence: 790.72,
+			RuleVersion:     1,
+		}, rt.Calculation)
+	})
+
+	t.Run("filters ratings by team and competition", func(t *testing.T) {
+		t.Helper()
+
+		n := make(chan *pq.Notification)
+		logger, _ := test.NewNullLogger()
+
+		stream := team.NewRatingStream(n, logger)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		teamID := uint64(2)
+		competitionID := uint64(8)
+		otherCompetitionID := uint64(9)
+
+		byTeam := stream.Subscribe(ctx, &team.StreamQuery{TeamID: &teamID})
+		byCompetition := stream.Subscribe(ctx, &team.StreamQuery{CompetitionID: &competitionID})
+		byOtherCompetition := stream.Subscribe(ctx, &team.StreamQuery{CompetitionID: &otherCompetitionID})
+
+		n <- &pq.Notification{Channel: "team_rating", Extra: payload}
+
+		assert.Equal(t, uint64(1), receiveRating(t, byCompetition).TeamID)
+		assert.Equal(t, 0, len(byTeam))
+		assert.Equal(t, 0, len(byOtherCompetition))
+	})
+
+	t.Run("logs an error if notification payload cannot be parsed", func(t *testing.T) {
+		t.Helper()
+
+		n := make(chan *pq.Notification)
+		logger, hook := test.NewNullLogger()
+
+		stream := team.NewRatingStream(n, logger)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ratings := stream.Subscribe(ctx, &team.StreamQuery{})
+
+		n <- nil
+		n <- &pq.Notification{Channel: "team_rating", Extra: "invalid"}
+		n <- &pq.Notification{Channel: "team_rating", Extra: payload}
+
+		assert.Equal(t, uint64(26), receiveRating(t, ratings).FixtureID)
+		assert.Equal(t, 1, len(hook.Entries))
+		assert.Equal(t, "error parsing team rating notification: invalid character 'i' looking for beginning of value", hook.LastEntry().Message)
+	})
+
+	t.Run("closes subscription channel when context is cancelled", func(t *testing.T) {
+		t.Helper()
+
+		n := make(chan *pq.Notification)
+		logger, _ := test.NewNullLogger()
+
+		stream := team.NewRatingStream(n, logger)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		ratings := stream.Subscribe(ctx, &team.StreamQuery{})
+
+		cancel()
+
+		select {
+		case _, ok := <-ratings:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("Expected subscription channel to be closed")
+		}
+	})
+}
+
+func receiveRating(t *testing.T, ratings <-chan *team.Rating) *team.Rating {
+	select {
+	case rt := <-ratings:
+		return rt
+	case <-time.After(time.Second):
+		t.Fatal("Expected rating to be received, got none")
+		return nil
+	}
+}
@@ -3,13 +3,15 @@ package team
 import "time"
 
 type Rating struct {
-	TeamID      uint64
-	FixtureID   uint64
-	SeasonID    uint64
-	Attack      Points
-	Defence     Points
-	FixtureDate time.Time
-	Timestamp   time.Time
+	TeamID        uint64
+	FixtureID     uint64
+	SeasonID      uint64
+	CompetitionID uint64
+	Attack        Points
+	Defence       Points
+	FixtureDate   time.Time
+	Timestamp     time.Time
+	Calculation   *Calculation
 }
 
 type Points struct {
@@ -17,9 +19,51 @@ type Points struct {
 	Difference float64
 }
 
+// PreMatch returns the points total held before the Difference for the fixture was applied.
+func (p Points) PreMatch() float64 {
+	return p.Total - p.Difference
+}
+
+// Calculation holds the inputs used to calculate a Rating. Ratings calculated before these inputs were
+// persisted have a nil Calculation.
+type Calculation struct {
+	Goals           uint32
+	AdjustedGoals   float64
+	KFactor         float64
+	OpponentAttack  float64
+	OpponentDefence float64
+	RuleVersion     uint32
+}
+
 type ReaderQuery struct {
 	TeamID   *uint64
 	SeasonID *uint64
 	Before   *time.Time
 	Sort     string
 }
+
+// ExportQuery filters the ratings exported by RatingExporter. Nil fields are not filtered on.
+type ExportQuery struct {
+	CompetitionID *uint64
+	SeasonID      *uint64
+	// From and To limit ratings to fixtures played on or after From and before To.
+	From *time.Time
+	To   *time.Time
+}
+
+type StreamQuery struct {
+	TeamID        *uint64
+	CompetitionID *uint64
+}
+
+// TodayQuery configures the window of fixtures processed by RatingHandler.Today.
+type TodayQuery struct {
+	// Hour is the hour of the day fixtures played before are processed. If the hour has not yet been reached today
+	// the window ends at the hour on the previous day.
+	Hour int
+	// Location is the timezone the hour and the start of the day are evaluated in. UTC is used if nil.
+	Location *time.Location
+	// Lookback extends the start of the window to before midnight so fixtures kicking off late on the previous day
+	// are included.
+	Lookback time.Duration
+}
@@ -1,6 +1,7 @@
 package team
 
 import (
+	"context"
 	"database/sql"
 	sq "github.com/Masterminds/squirrel"
 	_ "github.com/lib/pq"
@@ -8,14 +9,14 @@ import (
 )
 
 type RatingWriter interface {
-	Insert(r *Rating) error
+	Insert(ctx context.Context, r *Rating) error
 }
 
 type ratingWriter struct {
 	connection *sql.DB
 }
 
-func (r *ratingWriter) Insert(x *Rating) error {
+func (r *ratingWriter) Insert(ctx context.Context, x *Rating) error {
 	var exists bool
 
 	b := queryBuilder(r.connection)
@@ -28,7 +29,7 @@ func (r *ratingWriter) Insert(x *Rating) error {
 		Where(sq.Eq{"season_id": x.SeasonID}).
 		Where(sq.Eq{"fixture_id": x.FixtureID}).
 		Suffix(")").
-		Scan(&exists)
+		ScanContext(ctx, &exists)
 
 	if err != nil {
 		return err
@@ -42,30 +43,55 @@ func (r *ratingWriter) Insert(x *Rating) error {
 		}
 	}
 
+	var goals, adjustedGoals, kFactor, opponentAttack, opponentDefence, ruleVersion interface{}
+
+	if x.Calculation != nil {
+		goals = x.Calculation.Goals
+		adjustedGoals = x.Calculation.AdjustedGoals
+		kFactor = x.Calculation.KFactor
+		opponentAttack = x.Calculation.OpponentAttack
+		opponentDefence = x.Calculation.OpponentDefence
+		ruleVersion = x.Calculation.RuleVersion
+	}
+
 	_, err = b.
 		Insert("team_rating").
 		Columns(
 			"team_id",
 			"fixture_id",
 			"season_id",
+			"competition_id",
 			"attack_total",
 			"attack_points",
 			"defence_total",
 			"defence_points",
 			"fixture_date",
-			"timestamp").
+			"timestamp",
+			"goals",
+			"adjusted_goals",
+			"k_factor",
+			"opponent_attack_total",
+			"opponent_defence_total",
+			"rule_version").
 		Values(
 			x.TeamID,
 			x.FixtureID,
 			x.SeasonID,
+			x.CompetitionID,
 			x.Attack.Total,
 			x.Attack.Difference,
 			x.Defence.Total,
 			x.Defence.Difference,
 			x.FixtureDate.Unix(),
 			x.Timestamp.Unix(),
+			goals,
+			adjustedGoals,
+			kFactor,
+			opponentAttack,
+			opponentDefence,
+			ruleVersion,
 		).
-		Exec()
+		ExecContext(ctx)
 
 	return err
 }
@@ -1,6 +1,7 @@
 package team_test
 
 import (
+	"context"
 	"github.com/statistico/statistico-ratings/internal/app/team"
 	"github.com/statistico/statistico-ratings/internal/app/test"
 	"github.com/stretchr/testify/assert"
@@ -10,6 +11,7 @@ import (
 
 func TestRatingRepository_Insert(t *testing.T) {
 	conn, cleanUp := test.GetConnection(t, []string{"team_rating"})
+	ctx := context.Background()
 	writer := team.NewRatingWriter(conn)
 
 	t.Run("increases table count", func(t *testing.T) {
@@ -77,7 +79,7 @@ func TestRatingRepository_Insert(t *testing.T) {
 		}
 
 		for _, st := range s {
-			if err := writer.Insert(st.Rating); err != nil {
+			if err := writer.Insert(ctx, st.Rating); err != nil {
 				t.Fatalf("Expected nil, got %s", err.Error())
 			}
 
@@ -113,11 +115,11 @@ func TestRatingRepository_Insert(t *testing.T) {
 			Timestamp:   time.Now(),
 		}
 
-		if err := writer.Insert(r); err != nil {
+		if err := writer.Insert(ctx, r); err != nil {
 			t.Fatalf("Expected nil, got %s", err.Error())
 		}
 
-		err := writer.Insert(r)
+		err := writer.Insert(ctx, r)
 
 		if err == nil {
 			t.Fatal("Expected error, got nil")
@@ -125,4 +127,74 @@ func TestRatingRepository_Insert(t *testing.T) {
 
 		assert.Equal(t, "team rating exists for team 1, fixture 120 and season 17462", err.Error())
 	})
+
+	t.Run("does not insert a team rating if the context deadline is exceeded", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		r := &team.Rating{
+			TeamID:      1,
+			FixtureID:   120,
+			SeasonID:    17462,
+			FixtureDate: time.Now(),
+			Timestamp:   time.Now(),
+		}
+
+		expired, cancel := context.WithTimeout(ctx, -time.Second)
+		defer cancel()
+
+		err := writer.Insert(expired, r)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, context.DeadlineExceeded, err)
+
+		ratings, err := team.NewRatingReader(conn).ByFixture(ctx, 120)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, 0, len(ratings))
+	})
+
+	t.Run("returns promptly without inserting a team rating if the context is cancelled while the insert is running", func(t *testing.T) {
+		t.Helper()
+		defer cleanUp()
+
+		r := &team.Rating{
+			TeamID:      1,
+			FixtureID:   120,
+			SeasonID:    17462,
+			FixtureDate: time.Now(),
+			Timestamp:   time.Now(),
+		}
+
+		release := lockTable(t, conn, "team_rating")
+
+		cancelled, cancel := context.WithCancel(ctx)
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		start := time.Now()
+
+		err := writer.Insert(cancelled, r)
+
+		release()
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Less(t, int64(time.Since(start)), int64(2*time.Second))
+
+		ratings, err := team.NewRatingReader(conn).ByFixture(ctx, 120)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Equal(t, 0, len(ratings))
+	})
 }
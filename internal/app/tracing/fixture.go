@@ -0,0 +1,92 @@
+package tracing
+
+import (
+	"context"
+	"github.com/statistico/statistico-football-data-go-grpc-client"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app/fixture"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+type fetcher struct {
+	fetcher fixture.Fetcher
+	tracer  trace.Tracer
+}
+
+func (f *fetcher) ByCompetition(ctx context.Context, competitionID, seasonID uint64) ([]*statistico.Fixture, error) {
+	ctx, span := f.tracer.Start(ctx, "fixture.Fetcher/ByCompetition", trace.WithAttributes(
+		attribute.Int64("competition.id", int64(competitionID)),
+		attribute.Int64("season.id", int64(seasonID)),
+	))
+
+	fixtures, err := f.fetcher.ByCompetition(ctx, competitionID, seasonID)
+
+	span.SetAttributes(attribute.Int("fixture.count", len(fixtures)))
+	end(span, err)
+
+	return fixtures, err
+}
+
+func (f *fetcher) ByDate(ctx context.Context, from, to time.Time) ([]*statistico.Fixture, error) {
+	ctx, span := f.tracer.Start(ctx, "fixture.Fetcher/ByDate", trace.WithAttributes(
+		attribute.String("date.from", from.Format(time.RFC3339)),
+		attribute.String("date.to", to.Format(time.RFC3339)),
+	))
+
+	fixtures, err := f.fetcher.ByDate(ctx, from, to)
+
+	span.SetAttributes(attribute.Int("fixture.count", len(fixtures)))
+	end(span, err)
+
+	return fixtures, err
+}
+
+func (f *fetcher) ByID(ctx context.Context, fixtureID uint64) (*statistico.Fixture, error) {
+	ctx, span := f.tracer.Start(ctx, "fixture.Fetcher/ByID", trace.WithAttributes(fixtureAttribute(fixtureID)))
+
+	fix, err := f.fetcher.ByID(ctx, fixtureID)
+
+	end(span, err)
+
+	return fix, err
+}
+
+func (f *fetcher) Seasons(ctx context.Context, competitionID uint64, last int) ([]uint64, error) {
+	ctx, span := f.tracer.Start(ctx, "fixture.Fetcher/Seasons", trace.WithAttributes(
+		attribute.Int64("competition.id", int64(competitionID)),
+		attribute.Int("season.last", last),
+	))
+
+	seasons, err := f.fetcher.Seasons(ctx, competitionID, last)
+
+	end(span, err)
+
+	return seasons, err
+}
+
+type eventClient struct {
+	client statisticodata.EventClient
+	tracer trace.Tracer
+}
+
+func (e *eventClient) FixtureEvents(ctx context.Context, fixtureID uint64) (*statistico.FixtureEventsResponse, error) {
+	ctx, span := e.tracer.Start(ctx, "EventClient/FixtureEvents", trace.WithAttributes(fixtureAttribute(fixtureID)))
+
+	events, err := e.client.FixtureEvents(ctx, fixtureID)
+
+	end(span, err)
+
+	return events, err
+}
+
+// NewFetcher returns a fixture.Fetcher creating a span for each call to the wrapped Fetcher.
+func NewFetcher(f fixture.Fetcher, t trace.Tracer) fixture.Fetcher {
+	return &fetcher{fetcher: f, tracer: t}
+}
+
+// NewEventClient returns a statisticodata.EventClient creating a span for each call to the wrapped EventClient.
+func NewEventClient(c statisticodata.EventClient, t trace.Tracer) statisticodata.EventClient {
+	return &eventClient{client: c, tracer: t}
+}
@@ -0,0 +1,135 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"testing"
+	"time"
+)
+
+func TestFetcher_ByDate(t *testing.T) {
+	from := time.Date(2021, 3, 12, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 3, 13, 0, 0, 0, 0, time.UTC)
+
+	t.Run("creates a span for the wrapped fetcher call", func(t *testing.T) {
+		t.Helper()
+
+		f := new(MockFixtureFetcher)
+		tracer, recorder := newTracer()
+
+		fetcher := tracing.NewFetcher(f, tracer)
+
+		fixtures := []*statistico.Fixture{{Id: 1}, {Id: 2}}
+
+		f.On("ByDate", mock.Anything, from, to).Return(fixtures, nil)
+
+		fetched, err := fetcher.ByDate(context.Background(), from, to)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		spans := recorder.Ended()
+
+		a := assert.New(t)
+		a.Equal(fixtures, fetched)
+		a.Equal(1, len(spans))
+		a.Equal("fixture.Fetcher/ByDate", spans[0].Name())
+		a.Equal(codes.Unset, spans[0].Status().Code)
+		a.Contains(spans[0].Attributes(), attribute.String("date.from", "2021-03-12T00:00:00Z"))
+		a.Contains(spans[0].Attributes(), attribute.Int("fixture.count", 2))
+	})
+
+	t.Run("passes the span context to the wrapped fetcher and records errors", func(t *testing.T) {
+		t.Helper()
+
+		f := new(MockFixtureFetcher)
+		tracer, recorder := newTracer()
+
+		fetcher := tracing.NewFetcher(f, tracer)
+
+		f.On("ByDate", mock.Anything, from, to).Return([]*statistico.Fixture{}, errors.New("fixture client error"))
+
+		_, err := fetcher.ByDate(context.Background(), from, to)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		spans := recorder.Ended()
+		ctx := f.Calls[0].Arguments.Get(0).(context.Context)
+
+		a := assert.New(t)
+		a.Equal("fixture client error", err.Error())
+		a.Equal(codes.Error, spans[0].Status().Code)
+		a.Equal("fixture client error", spans[0].Status().Description)
+		a.Equal(spans[0].SpanContext().SpanID(), traceSpanID(ctx))
+	})
+}
+
+func TestEventClient_FixtureEvents(t *testing.T) {
+	t.Run("creates a span for each fixture events request", func(t *testing.T) {
+		t.Helper()
+
+		c := new(MockEventClient)
+		tracer, recorder := newTracer()
+
+		client := tracing.NewEventClient(c, tracer)
+
+		res := statistico.FixtureEventsResponse{FixtureId: 26}
+
+		c.On("FixtureEvents", mock.Anything, uint64(26)).Return(&res, nil)
+
+		fetched, err := client.FixtureEvents(context.Background(), 26)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		spans := recorder.Ended()
+
+		a := assert.New(t)
+		a.Equal(&res, fetched)
+		a.Equal("EventClient/FixtureEvents", spans[0].Name())
+		a.Contains(spans[0].Attributes(), attribute.Int64("fixture.id", 26))
+	})
+}
+
+type MockFixtureFetcher struct {
+	mock.Mock
+}
+
+func (m *MockFixtureFetcher) ByCompetition(ctx context.Context, competitionID, seasonID uint64) ([]*statistico.Fixture, error) {
+	args := m.Called(ctx, competitionID, seasonID)
+	return args.Get(0).([]*statistico.Fixture), args.Error(1)
+}
+
+func (m *MockFixtureFetcher) ByDate(ctx context.Context, from, to time.Time) ([]*statistico.Fixture, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).([]*statistico.Fixture), args.Error(1)
+}
+
+func (m *MockFixtureFetcher) ByID(ctx context.Context, fixtureID uint64) (*statistico.Fixture, error) {
+	args := m.Called(ctx, fixtureID)
+	return args.Get(0).(*statistico.Fixture), args.Error(1)
+}
+
+func (m *MockFixtureFetcher) Seasons(ctx context.Context, competitionID uint64, last int) ([]uint64, error) {
+	args := m.Called(ctx, competitionID, last)
+	return args.Get(0).([]uint64), args.Error(1)
+}
+
+type MockEventClient struct {
+	mock.Mock
+}
+
+func (m *MockEventClient) FixtureEvents(ctx context.Context, fixtureID uint64) (*statistico.FixtureEventsResponse, error) {
+	args := m.Called(ctx, fixtureID)
+	return args.Get(0).(*statistico.FixtureEventsResponse), args.Error(1)
+}
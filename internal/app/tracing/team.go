@@ -0,0 +1,137 @@
+package tracing
+
+import (
+	"context"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+type ratingProcessor struct {
+	processor team.RatingProcessor
+	tracer    trace.Tracer
+}
+
+// ByFixture creates a span for each fixture processed so calls to fetch events and read and write ratings are
+// grouped by fixture. An app.DuplicationError is recorded as a skipped fixture rather than an error.
+func (r *ratingProcessor) ByFixture(ctx context.Context, f *statistico.Fixture) error {
+	ctx, span := r.tracer.Start(ctx, "team.RatingProcessor/ByFixture", trace.WithAttributes(
+		fixtureAttribute(uint64(f.GetId())),
+		attribute.Int64("competition.id", int64(f.GetCompetition().GetId())),
+	))
+
+	err := r.processor.ByFixture(ctx, f)
+
+	if _, ok := err.(*app.DuplicationError); ok {
+		span.SetAttributes(attribute.Bool("fixture.skipped", true))
+		end(span, nil)
+		return err
+	}
+
+	end(span, err)
+
+	return err
+}
+
+type ratingReader struct {
+	reader team.RatingReader
+	tracer trace.Tracer
+}
+
+func (r *ratingReader) Latest(ctx context.Context, teamID uint64) (*team.Rating, error) {
+	ctx, span := r.start(ctx, "Latest", attribute.Int64("team.id", int64(teamID)))
+
+	rating, err := r.reader.Latest(ctx, teamID)
+
+	// A team without a rating is expected for a team's first fixture so is not recorded as an error.
+	if _, ok := err.(*app.NotFoundError); ok {
+		end(span, nil)
+		return rating, err
+	}
+
+	end(span, err)
+
+	return rating, err
+}
+
+func (r *ratingReader) LatestByTeams(ctx context.Context, teamIDs []uint64, before *time.Time) ([]*team.Rating, error) {
+	ctx, span := r.start(ctx, "LatestByTeams", attribute.Int("team.count", len(teamIDs)))
+
+	ratings, err := r.reader.LatestByTeams(ctx, teamIDs, before)
+
+	end(span, err)
+
+	return ratings, err
+}
+
+func (r *ratingReader) ByFixture(ctx context.Context, fixtureID uint64) ([]*team.Rating, error) {
+	ctx, span := r.start(ctx, "ByFixture", fixtureAttribute(fixtureID))
+
+	ratings, err := r.reader.ByFixture(ctx, fixtureID)
+
+	end(span, err)
+
+	return ratings, err
+}
+
+func (r *ratingReader) Get(ctx context.Context, q *team.ReaderQuery) ([]*team.Rating, error) {
+	ctx, span := r.start(ctx, "Get")
+
+	ratings, err := r.reader.Get(ctx, q)
+
+	span.SetAttributes(attribute.Int("rating.count", len(ratings)))
+	end(span, err)
+
+	return ratings, err
+}
+
+func (r *ratingReader) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, semconv.DBSystemPostgreSQL, semconv.DBSQLTableKey.String("team_rating"))
+
+	return r.tracer.Start(ctx, "team.RatingReader/"+method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+type ratingWriter struct {
+	writer team.RatingWriter
+	tracer trace.Tracer
+}
+
+func (r *ratingWriter) Insert(ctx context.Context, x *team.Rating) error {
+	ctx, span := r.tracer.Start(
+		ctx,
+		"team.RatingWriter/Insert",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBSQLTableKey.String("team_rating"),
+			fixtureAttribute(x.FixtureID),
+			attribute.Int64("team.id", int64(x.TeamID)),
+		),
+	)
+
+	err := r.writer.Insert(ctx, x)
+
+	end(span, err)
+
+	return err
+}
+
+// NewRatingProcessor returns a team.RatingProcessor creating a span for each fixture processed by the wrapped
+// RatingProcessor.
+func NewRatingProcessor(p team.RatingProcessor, t trace.Tracer) team.RatingProcessor {
+	return &ratingProcessor{processor: p, tracer: t}
+}
+
+// NewRatingReader returns a team.RatingReader creating a span for each query made by the wrapped RatingReader.
+func NewRatingReader(r team.RatingReader, t trace.Tracer) team.RatingReader {
+	return &ratingReader{reader: r, tracer: t}
+}
+
+// NewRatingWriter returns a team.RatingWriter creating a span for each rating inserted by the wrapped RatingWriter.
+func NewRatingWriter(w team.RatingWriter, t trace.Tracer) team.RatingWriter {
+	return &ratingWriter{writer: w, tracer: t}
+}
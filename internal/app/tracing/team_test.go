@@ -0,0 +1,173 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"github.com/statistico/statistico-proto/go"
+	"github.com/statistico/statistico-ratings/internal/app"
+	"github.com/statistico/statistico-ratings/internal/app/team"
+	"github.com/statistico/statistico-ratings/internal/app/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+	"time"
+)
+
+func TestRatingProcessor_ByFixture(t *testing.T) {
+	fix := statistico.Fixture{Id: 26, Competition: &statistico.Competition{Id: 8}}
+
+	t.Run("creates a span for each fixture processed", func(t *testing.T) {
+		t.Helper()
+
+		p := new(MockRatingProcessor)
+		tracer, recorder := newTracer()
+
+		processor := tracing.NewRatingProcessor(p, tracer)
+
+		p.On("ByFixture", mock.Anything, &fix).Return(nil)
+
+		if err := processor.ByFixture(context.Background(), &fix); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		spans := recorder.Ended()
+
+		a := assert.New(t)
+		a.Equal("team.RatingProcessor/ByFixture", spans[0].Name())
+		a.Contains(spans[0].Attributes(), attribute.Int64("fixture.id", 26))
+		a.Contains(spans[0].Attributes(), attribute.Int64("competition.id", 8))
+	})
+
+	t.Run("records duplicate fixtures as skipped rather than an error", func(t *testing.T) {
+		t.Helper()
+
+		p := new(MockRatingProcessor)
+		tracer, recorder := newTracer()
+
+		processor := tracing.NewRatingProcessor(p, tracer)
+
+		p.On("ByFixture", mock.Anything, &fix).Return(&app.DuplicationError{FixtureID: 26})
+
+		err := processor.ByFixture(context.Background(), &fix)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		spans := recorder.Ended()
+
+		a := assert.New(t)
+		a.IsType(&app.DuplicationError{}, err)
+		a.Equal(codes.Unset, spans[0].Status().Code)
+		a.Contains(spans[0].Attributes(), attribute.Bool("fixture.skipped", true))
+	})
+}
+
+func TestRatingReader_Latest(t *testing.T) {
+	t.Run("does not record a missing rating as an error", func(t *testing.T) {
+		t.Helper()
+
+		r := new(MockRatingReader)
+		tracer, recorder := newTracer()
+
+		reader := tracing.NewRatingReader(r, tracer)
+
+		r.On("Latest", mock.Anything, uint64(5)).Return(&team.Rating{}, &app.NotFoundError{TeamID: 5})
+
+		_, err := reader.Latest(context.Background(), 5)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		spans := recorder.Ended()
+
+		a := assert.New(t)
+		a.IsType(&app.NotFoundError{}, err)
+		a.Equal("team.RatingReader/Latest", spans[0].Name())
+		a.Equal(trace.SpanKindClient, spans[0].SpanKind())
+		a.Equal(codes.Unset, spans[0].Status().Code)
+		a.Contains(spans[0].Attributes(), attribute.String("db.system", "postgresql"))
+	})
+}
+
+func TestRatingWriter_Insert(t *testing.T) {
+	t.Run("records errors returned by the wrapped writer", func(t *testing.T) {
+		t.Helper()
+
+		w := new(MockRatingWriter)
+		tracer, recorder := newTracer()
+
+		writer := tracing.NewRatingWriter(w, tracer)
+
+		ctx, parent := tracer.Start(context.Background(), "team.RatingProcessor/ByFixture")
+		rating := team.Rating{TeamID: 5, FixtureID: 26}
+
+		w.On("Insert", mock.Anything, &rating).Return(errors.New("connection refused"))
+
+		err := writer.Insert(ctx, &rating)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		parent.End()
+
+		spans := recorder.Ended()
+
+		a := assert.New(t)
+		a.Equal("team.RatingWriter/Insert", spans[0].Name())
+		a.Equal(codes.Error, spans[0].Status().Code)
+		a.Equal(parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+		a.Equal(1, len(spans[0].Events()))
+	})
+}
+
+func traceSpanID(ctx context.Context) trace.SpanID {
+	return trace.SpanContextFromContext(ctx).SpanID()
+}
+
+type MockRatingProcessor struct {
+	mock.Mock
+}
+
+func (m *MockRatingProcessor) ByFixture(ctx context.Context, f *statistico.Fixture) error {
+	args := m.Called(ctx, f)
+	return args.Error(0)
+}
+
+type MockRatingReader struct {
+	mock.Mock
+}
+
+func (m *MockRatingReader) Latest(ctx context.Context, teamID uint64) (*team.Rating, error) {
+	args := m.Called(ctx, teamID)
+	return args.Get(0).(*team.Rating), args.Error(1)
+}
+
+func (m *MockRatingReader) LatestByTeams(ctx context.Context, teamIDs []uint64, before *time.Time) ([]*team.Rating, error) {
+	args := m.Called(ctx, teamIDs, before)
+	return args.Get(0).([]*team.Rating), args.Error(1)
+}
+
+func (m *MockRatingReader) ByFixture(ctx context.Context, fixtureID uint64) ([]*team.Rating, error) {
+	args := m.Called(ctx, fixtureID)
+	return args.Get(0).([]*team.Rating), args.Error(1)
+}
+
+func (m *MockRatingReader) Get(ctx context.Context, q *team.ReaderQuery) ([]*team.Rating, error) {
+	args := m.Called(ctx, q)
+	return args.Get(0).([]*team.Rating), args.Error(1)
+}
+
+type MockRatingWriter struct {
+	mock.Mock
+}
+
+func (m *MockRatingWriter) Insert(ctx context.Context, r *team.Rating) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+)
+
+// Name is the instrumentation name of the Tracer used to create spans within the application.
+const Name = "github.com/statistico/statistico-ratings"
+
+// Exporters supported by NewTracerProvider.
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// NewTracerProvider returns a TracerProvider exporting spans in batches using the exporter provided. The OTLP
+// exporter sends spans over gRPC to the collector configured by the standard OTEL_EXPORTER_OTLP_* environment
+// variables and the stdout exporter writes spans as JSON to the io.Writer provided. Spans are not sampled if exporter
+// is empty.
+func NewTracerProvider(ctx context.Context, exporter, service string, w io.Writer) (*sdktrace.TracerProvider, error) {
+	res := resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(service))
+
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+
+	switch exporter {
+	case "":
+		// The SDK fails to shut down a TracerProvider without a span processor, so one discarding spans is registered.
+		opts = append(
+			opts,
+			sdktrace.WithSampler(sdktrace.NeverSample()),
+			sdktrace.WithSpanProcessor(sdktrace.NewSimpleSpanProcessor(discard{})),
+		)
+	case ExporterOTLP:
+		exp, err := otlptracegrpc.New(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	case ExporterStdout:
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(w))
+
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	default:
+		return nil, fmt.Errorf("tracing exporter %q is not supported", exporter)
+	}
+
+	return sdktrace.NewTracerProvider(opts...), nil
+}
+
+type discard struct{}
+
+func (discard) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error {
+	return nil
+}
+
+func (discard) Shutdown(context.Context) error {
+	return nil
+}
+
+// end records err against the span, if not nil, and ends the span.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+func fixtureAttribute(id uint64) attribute.KeyValue {
+	return attribute.Int64("fixture.id", int64(id))
+}
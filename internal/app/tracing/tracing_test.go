@@ -0,0 +1,75 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/statistico/statistico-ratings/internal/app/tracing"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	t.Run("writes spans to the writer provided using the stdout exporter", func(t *testing.T) {
+		t.Helper()
+
+		var buf bytes.Buffer
+
+		tp, err := tracing.NewTracerProvider(context.Background(), tracing.ExporterStdout, "statistico-ratings", &buf)
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		_, span := tp.Tracer(tracing.Name).Start(context.Background(), "team:range")
+		span.End()
+
+		if err := tp.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		assert.Contains(t, buf.String(), `"Name":"team:range"`)
+		assert.Contains(t, buf.String(), `"Value":"statistico-ratings"`)
+	})
+
+	t.Run("does not sample spans and shuts down cleanly without an exporter", func(t *testing.T) {
+		t.Helper()
+
+		tp, err := tracing.NewTracerProvider(context.Background(), "", "statistico-ratings", &bytes.Buffer{})
+
+		if err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+
+		_, span := tp.Tracer(tracing.Name).Start(context.Background(), "team:range")
+		span.End()
+
+		assert.False(t, span.SpanContext().IsSampled())
+
+		if err := tp.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Expected nil, got %s", err.Error())
+		}
+	})
+
+	t.Run("returns an error if the exporter is not supported", func(t *testing.T) {
+		t.Helper()
+
+		_, err := tracing.NewTracerProvider(context.Background(), "jaeger", "statistico-ratings", &bytes.Buffer{})
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		assert.Equal(t, `tracing exporter "jaeger" is not supported`, err.Error())
+	})
+}
+
+// newTracer returns a Tracer recording spans to the SpanRecorder returned.
+func newTracer() (trace.Tracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	return tp.Tracer(tracing.Name), recorder
+}